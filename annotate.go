@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+
+	"github.com/ZipRecruiter/splinter/internal/calls"
+	"github.com/ZipRecruiter/splinter/internal/config"
+	"github.com/ZipRecruiter/splinter/internal/driver"
+)
+
+// annotate implements `splinter annotate`, which copies the source of the
+// analyzed packages into an analysistest tree, with a // want comment for
+// each diagnostic on the line it's reported on, so the current behavior of a
+// configuration can be kept as a regression test.
+func annotate(analyzers []*analysis.Analyzer, args []string) int {
+	fset := flag.NewFlagSet("annotate", flag.ExitOnError)
+	fset.Usage = func() {
+		fmt.Fprintf(fset.Output(), "usage: splinter annotate [-o dir] [analyzer flags] packages...\n\n")
+		fset.PrintDefaults()
+	}
+	out := fset.String("o", "testdata", "write the annotated packages under dir/src, the layout analysistest expects")
+	analyzerFlags(fset, analyzers)
+	config.Parse(fset, args)
+
+	pkgs, err := driver.Load(driver.LoadConfig{Tests: true}, fset.Args()...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter annotate: %s\n", err)
+		return 1
+	}
+	graph, err := checker.Analyze(analyzers, pkgs, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter annotate: %s\n", err)
+		return 1
+	}
+	diags, err := driver.Diagnostics(graph)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter annotate: %s\n", err)
+		return 1
+	}
+
+	wants := map[string]map[int][]string{} // by file, then line
+	for _, d := range diags {
+		posn := d.Position()
+		if wants[posn.Filename] == nil {
+			wants[posn.Filename] = map[int][]string{}
+		}
+		wants[posn.Filename][posn.Line] = append(wants[posn.Filename][posn.Line], d.Message)
+	}
+
+	// a package and its test variants share files, which are only
+	// written once; external test packages go with the package they test
+	written := map[string]bool{}
+	for _, pkg := range pkgs {
+		dir := filepath.Join(*out, "src", filepath.FromSlash(calls.PkgPath(pkg.Types)))
+		for _, file := range pkg.GoFiles {
+			if written[file] {
+				continue
+			}
+			written[file] = true
+
+			src, err := os.ReadFile(file)
+			if err == nil {
+				err = os.MkdirAll(dir, 0o755)
+			}
+			if err == nil {
+				err = os.WriteFile(filepath.Join(dir, filepath.Base(file)), annotateSource(src, wants[file]), 0o644)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "splinter annotate: %s\n", err)
+				return 1
+			}
+		}
+	}
+	fmt.Fprintf(os.Stderr, "annotated %d diagnostics in %d files under %s\n", len(diags), len(written), filepath.Join(*out, "src"))
+	return 0
+}
+
+// annotateSource returns src with a // want comment at the end of each line
+// with messages, expecting each of them.
+func annotateSource(src []byte, messages map[int][]string) []byte {
+	if len(messages) == 0 {
+		return src
+	}
+
+	lines := bytes.SplitAfter(src, []byte("\n"))
+	var b bytes.Buffer
+	for i, line := range lines {
+		msgs := messages[i+1]
+		if len(msgs) == 0 {
+			b.Write(line)
+			continue
+		}
+
+		var quoted []string
+		for _, msg := range msgs {
+			quoted = append(quoted, strconv.Quote(regexp.QuoteMeta(msg)))
+		}
+		text := strings.TrimRight(string(line), "\r\n")
+		b.WriteString(text + " // want " + strings.Join(quoted, " ") + string(line[len(text):]))
+	}
+	return b.Bytes()
+}
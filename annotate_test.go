@@ -0,0 +1,36 @@
+package main
+
+import "testing"
+
+func TestAnnotateSource(t *testing.T) {
+	src := "package a\n\nfunc F() {\n\tlog.Log(\"a\")\n\tlog.Log(k, 1)\r\n}"
+	tests := []struct {
+		name     string
+		messages map[int][]string
+		expected string
+	}{
+		{"none", nil, src},
+		{
+			"one",
+			map[int][]string{4: {"1 args passed to m/log.Log; must be even"}},
+			"package a\n\nfunc F() {\n\tlog.Log(\"a\") // want \"1 args passed to m/log\\\\.Log; must be even\"\n\tlog.Log(k, 1)\r\n}",
+		},
+		{
+			"several",
+			map[int][]string{5: {"key k (arg 0) isn't constant", `a "quoted" (message)`}},
+			"package a\n\nfunc F() {\n\tlog.Log(\"a\")\n\tlog.Log(k, 1) // want \"key k \\\\(arg 0\\\\) isn't constant\" \"a \\\"quoted\\\" \\\\(message\\\\)\"\r\n}",
+		},
+		{
+			"last line",
+			map[int][]string{6: {"end"}},
+			"package a\n\nfunc F() {\n\tlog.Log(\"a\")\n\tlog.Log(k, 1)\r\n} // want \"end\"",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := string(annotateSource([]byte(src), test.messages)); got != test.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", test.expected, got)
+			}
+		})
+	}
+}
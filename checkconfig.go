@@ -0,0 +1,138 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/internal/calls"
+	"github.com/ZipRecruiter/splinter/internal/config"
+	"github.com/ZipRecruiter/splinter/internal/driver"
+)
+
+// offsetFlags take selectors with the offset of their pairs, which may only
+// be given once per selector.
+var offsetFlags = map[string]bool{"pair-func": true, "event-func": true}
+
+// fileFlags take the path of a file that must exist.
+var fileFlags = map[string]bool{"event-registry": true}
+
+// checkConfig implements `splinter check-config`, which validates a
+// configuration file without analyzing anything, and exits with 1 if it
+// has any problems.
+func checkConfig(analyzers []*analysis.Analyzer, args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "usage: splinter check-config file\n")
+		return 2
+	}
+
+	fset := flag.NewFlagSet("check-config", flag.ContinueOnError)
+	analyzerFlags(fset, analyzers)
+	policyFlags(fset)
+
+	problems := configProblems(args[0], fset)
+	for _, p := range problems {
+		fmt.Fprintln(os.Stderr, p)
+	}
+	if len(problems) != 0 {
+		return 1
+	}
+	return 0
+}
+
+// configProblems sets the flags of fset from the configuration file at path,
+// and returns every problem with it: entries that aren't valid flag values,
+// duplicate entries, selectors given conflicting offsets, unknown rules and
+// missing files.
+func configProblems(path string, fset *flag.FlagSet) []string {
+	entries, err := config.Read(path)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	var problems []string
+	report := func(e config.Entry, format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf("%s:%d: ", path, e.Line)+fmt.Sprintf(format, args...))
+	}
+
+	seen := map[config.Entry]int{}
+	offsets := map[string]config.Entry{}
+	for _, e := range entries {
+		if err := e.Set(fset); err != nil {
+			report(e, "%s", err)
+			continue
+		}
+
+		key := config.Entry{Flag: e.Flag, Value: e.Value}
+		if line, ok := seen[key]; ok {
+			report(e, "duplicate %s %q; also on line %d", e.Flag, e.Value, line)
+			continue
+		}
+		seen[key] = e.Line
+
+		if v, remove := config.Removal(e.Value); offsetFlags[e.Flag] && remove {
+			// a later entry may add the selector back with any offset
+			if i := strings.LastIndexByte(v, '='); i >= 0 {
+				v = v[:i]
+			}
+			if sel, err := calls.ParseSelector(v); err == nil {
+				delete(offsets, e.Flag+" "+sel.String())
+			}
+		} else if offsetFlags[e.Flag] {
+			sel, _, err := calls.ParseOffset(e.Value)
+			if err == nil {
+				if prev, ok := offsets[e.Flag+" "+sel.String()]; ok {
+					report(e, "%s %q conflicts with %q on line %d", e.Flag, e.Value, prev.Value, prev.Line)
+				} else {
+					offsets[e.Flag+" "+sel.String()] = e
+				}
+			}
+		}
+
+		if fileFlags[e.Flag] {
+			if _, err := os.Stat(e.Value); err != nil {
+				report(e, "%s: %s", e.Flag, err)
+			}
+		}
+
+		if err := entryRules(e); err != nil {
+			report(e, "%s: %s", e.Flag, err)
+		}
+	}
+	return problems
+}
+
+// entryRules returns an error naming the first unknown rule in e, if it's
+// an entry of a flag taking rules that aren't validated when set.
+func entryRules(e config.Entry) error {
+	switch e.Flag {
+	case "escalate":
+		set := driver.RuleSet{}
+		if err := set.Set(e.Value); err != nil {
+			return err
+		}
+		return validRules(set)
+	case "suppress":
+		var s driver.Suppressions
+		if err := s.Set(e.Value); err != nil {
+			return err
+		}
+		return validRules(s.Rules())
+	case "severity":
+		s := driver.Severities{}
+		if err := s.Set(e.Value); err != nil {
+			return err
+		}
+		return validRules(s.Rules())
+	case "disable":
+		var d driver.Disables
+		if err := d.Set(e.Value); err != nil {
+			return err
+		}
+		return validRules(d.Rules())
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+
+	"github.com/ZipRecruiter/splinter/internal/config"
+	"github.com/ZipRecruiter/splinter/internal/driver"
+	"github.com/ZipRecruiter/splinter/pairs"
+)
+
+// containers implements `splinter containers`, which summarizes how
+// whitelisted containers are fed, from the pairs ContainerUsage facts of
+// every analyzed package and its dependencies.
+func containers(analyzers []*analysis.Analyzer, args []string) int {
+	fset := flag.NewFlagSet("containers", flag.ExitOnError)
+	fset.Usage = func() {
+		fmt.Fprintf(fset.Output(), "usage: splinter containers [analyzer flags] packages...\n\n")
+		fset.PrintDefaults()
+	}
+	workspace := fset.Bool("workspace", false, "summarize every module of the enclosing go.work workspace; relative patterns (default ./...) apply within each module")
+	analyzerFlags(fset, analyzers)
+	config.Parse(fset, args)
+
+	pkgs, err := driver.Load(driver.LoadConfig{Tests: true, Workspace: *workspace}, fset.Args()...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter containers: %s\n", err)
+		return 1
+	}
+	graph, err := checker.Analyze(analyzers, pkgs, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter containers: %s\n", err)
+		return 1
+	}
+
+	var facts []*pairs.ContainerUsage
+	for _, fact := range pairsFacts[pairs.ContainerUsage](graph.All()) {
+		facts = append(facts, fact)
+	}
+	printContainers(os.Stdout, facts)
+	return 0
+}
+
+// printContainers merges the feeds in facts by container and callee, and
+// writes them to w.
+func printContainers(w io.Writer, facts []*pairs.ContainerUsage) {
+	merged := map[string]map[string]*pairs.ContainerFeed{}
+	for _, fact := range facts {
+		for _, f := range fact.Feeds {
+			if merged[f.Container] == nil {
+				merged[f.Container] = map[string]*pairs.ContainerFeed{}
+			}
+			m := merged[f.Container][f.Callee]
+			if m == nil {
+				m = &pairs.ContainerFeed{Container: f.Container, Callee: f.Callee, ValueTypes: map[string]int{}}
+				merged[f.Container][f.Callee] = m
+			}
+			m.Calls += f.Calls
+			m.ConstantKeys += f.ConstantKeys
+			m.OtherKeys += f.OtherKeys
+			for t, n := range f.ValueTypes {
+				m.ValueTypes[t] += n
+			}
+		}
+	}
+
+	for _, container := range sortedKeys(merged) {
+		fmt.Fprintln(w, container)
+		for _, callee := range sortedKeys(merged[container]) {
+			f := merged[container][callee]
+			var values []string
+			for _, t := range sortedKeys(f.ValueTypes) {
+				values = append(values, fmt.Sprintf("%s %d", t, f.ValueTypes[t]))
+			}
+			fmt.Fprintf(w, "\t%s: %d calls, %d keys (%d constant); values: %s\n",
+				callee, f.Calls, f.ConstantKeys+f.OtherKeys, f.ConstantKeys, strings.Join(values, ", "))
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/types"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/ZipRecruiter/splinter/internal/calls"
+	"github.com/ZipRecruiter/splinter/internal/config"
+	"github.com/ZipRecruiter/splinter/internal/driver"
+	"github.com/ZipRecruiter/splinter/pairs"
+)
+
+// doctor implements `splinter doctor`, which loads the packages and checks
+// that every configured pair func and assumed pair type exists among them
+// or their dependencies, exiting with 1 if any doesn't, since a typo in a
+// selector otherwise silently checks nothing.
+func doctor(analyzers []*analysis.Analyzer, args []string) int {
+	fset := flag.NewFlagSet("doctor", flag.ExitOnError)
+	fset.Usage = func() {
+		fmt.Fprintf(fset.Output(), "usage: splinter doctor [-flag] [packages]\n\n")
+		fset.PrintDefaults()
+	}
+	analyzerFlags(fset, analyzers)
+	config.Parse(fset, args)
+
+	patterns := fset.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	roots, err := driver.Load(driver.LoadConfig{Tests: true}, patterns...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter doctor: %s\n", err)
+		return 1
+	}
+
+	loaded := map[string][]*types.Package{}
+	packages.Visit(roots, nil, func(p *packages.Package) {
+		if p.Types != nil {
+			path := calls.PkgPath(p.Types)
+			loaded[path] = append(loaded[path], p.Types)
+		}
+	})
+
+	problems := configDiagnosis(fset, loaded)
+	for _, p := range problems {
+		fmt.Println(p)
+	}
+	if len(problems) != 0 {
+		return 1
+	}
+	return 0
+}
+
+// presetValues returns the values the presets set, by flag, which are only
+// checked against the packages loaded if those packages are among them.
+func presetValues() map[string]bool {
+	values := map[string]bool{}
+	for _, p := range pairs.Presets {
+		for flag, vs := range p.Flags {
+			for _, v := range vs {
+				values[flag+" "+v] = true
+			}
+		}
+	}
+	return values
+}
+
+// configDiagnosis returns the problems with the -pair-func and -assume-pair
+// values of fset, resolved against loaded, the packages by import path.
+// Values in a package that isn't loaded are problems unless a preset sets
+// them, since presets cover libraries a module needn't use.
+func configDiagnosis(fset *flag.FlagSet, loaded map[string][]*types.Package) []string {
+	fromPresets := presetValues()
+	var problems []string
+	report := func(flag, v, format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf("-%s %s: ", flag, v)+fmt.Sprintf(format, args...))
+	}
+
+	for _, v := range flagValues(fset, "pair-func") {
+		sel, offset, err := calls.ParseOffset(v)
+		if err != nil {
+			report("pair-func", v, "%s", err)
+			continue
+		}
+		sig, problem, found := resolveFunc(loaded, sel)
+		switch {
+		case !found && fromPresets["pair-func "+v]:
+		case problem != "":
+			report("pair-func", v, "%s", problem)
+		case !sig.Variadic():
+			report("pair-func", v, "%s isn't variadic, so it can't take pairs", sel)
+		case offset < sig.Params().Len()-1:
+			report("pair-func", v, "offset %d is before the variadic param of %s, at %d, so fixed params are checked as pairs", offset, sel, sig.Params().Len()-1)
+		}
+	}
+
+	for _, v := range flagValues(fset, "assume-pair") {
+		i := strings.LastIndexByte(v, '.')
+		if i < 0 {
+			report("assume-pair", v, "should be of form <pkg>.<type>")
+			continue
+		}
+		pkg, name := v[:i], v[i+1:]
+		pkgs, ok := loaded[pkg]
+		switch {
+		case !ok && fromPresets["assume-pair "+v]:
+		case !ok:
+			report("assume-pair", v, "package %s isn't among the packages loaded or their dependencies", pkg)
+		case lookupType(pkgs, name) == nil:
+			report("assume-pair", v, "no type %s in package %s", name, pkg)
+		}
+	}
+	return problems
+}
+
+// flagValues returns the values of the repeated flag of fset named name.
+func flagValues(fset *flag.FlagSet, name string) []string {
+	f := fset.Lookup(name)
+	if f == nil || f.Value.String() == "" {
+		return nil
+	}
+	return strings.Split(f.Value.String(), ",")
+}
+
+// resolveFunc returns the signature of the func or method sel selects in
+// loaded.  If it can't be resolved, problem says why, and found is false if
+// that's because its package isn't loaded.  A selector of a method of any
+// type resolves to the first method of that name.
+func resolveFunc(loaded map[string][]*types.Package, sel calls.Selector) (sig *types.Signature, problem string, found bool) {
+	if sel.Pkg == "" {
+		var paths []string
+		for path := range loaded {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			for _, pkg := range loaded[path] {
+				for _, name := range pkg.Scope().Names() {
+					if tn, ok := pkg.Scope().Lookup(name).(*types.TypeName); ok {
+						if sig := lookupMethod(tn, sel.Fun); sig != nil {
+							return sig, "", true
+						}
+					}
+				}
+			}
+		}
+		return nil, fmt.Sprintf("no method %s in the packages loaded or their dependencies", sel.Fun), true
+	}
+
+	// a package whose last element has a dot, like gopkg.in/yaml.v3,
+	// parses as a type
+	if _, ok := loaded[sel.Pkg]; !ok && sel.Typ != "" {
+		if _, ok := loaded[sel.Pkg+"."+sel.Typ]; ok {
+			sel = calls.Selector{Pkg: sel.Pkg + "." + sel.Typ, Fun: sel.Fun}
+		}
+	}
+	pkgs, ok := loaded[sel.Pkg]
+	if !ok {
+		return nil, fmt.Sprintf("package %s isn't among the packages loaded or their dependencies", sel.Pkg), false
+	}
+
+	if sel.Typ == "" {
+		for _, pkg := range pkgs {
+			switch obj := pkg.Scope().Lookup(sel.Fun).(type) {
+			case *types.Func:
+				return obj.Type().(*types.Signature), "", true
+			case *types.TypeName:
+				// values of named func types are selected by the type
+				if sig, ok := obj.Type().Underlying().(*types.Signature); ok {
+					return sig, "", true
+				}
+			}
+		}
+		return nil, fmt.Sprintf("no func %s in package %s", sel.Fun, sel.Pkg), true
+	}
+
+	tn := lookupType(pkgs, sel.Typ)
+	if tn == nil {
+		return nil, fmt.Sprintf("no type %s in package %s", sel.Typ, sel.Pkg), true
+	}
+	if sig := lookupMethod(tn, sel.Fun); sig != nil {
+		return sig, "", true
+	}
+	return nil, fmt.Sprintf("type %s.%s has no method %s", sel.Pkg, sel.Typ, sel.Fun), true
+}
+
+// lookupType returns the type named name in any of pkgs, the variants of a
+// package, or nil.
+func lookupType(pkgs []*types.Package, name string) *types.TypeName {
+	for _, pkg := range pkgs {
+		if tn, ok := pkg.Scope().Lookup(name).(*types.TypeName); ok {
+			return tn
+		}
+	}
+	return nil
+}
+
+// lookupMethod returns the signature of the method of tn, or of a pointer
+// to it, named name, or nil.
+func lookupMethod(tn *types.TypeName, name string) *types.Signature {
+	typ := tn.Type()
+	if _, ok := typ.Underlying().(*types.Interface); !ok {
+		typ = types.NewPointer(typ)
+	}
+	if s := types.NewMethodSet(typ).Lookup(tn.Pkg(), name); s != nil {
+		return s.Type().(*types.Signature)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConfigDiagnosis(t *testing.T) {
+	src := `package log
+
+type Logger struct{}
+
+func (Logger) Log(kv ...interface{}) {}
+
+type Pairs []interface{}
+
+func Log(kv ...interface{}) {}
+
+func Info(msg string, kv ...interface{}) {}
+
+func Fixed(key, value string) {}
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "log.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := new(types.Config).Check("example.com/log", fset, []*ast.File{f}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := map[string][]*types.Package{"example.com/log": {pkg}}
+
+	tests := []struct {
+		flag, value string
+		expected    string
+	}{
+		{"pair-func", "example.com/log.Log=0", ""},
+		{"pair-func", "example.com/log.Logger.Log=0", ""},
+		{"pair-func", ".Log=0", ""},
+		{"pair-func", "log/slog.Info=1", ""}, // set by the slog preset
+		{"pair-func", "example.com/log.Info=0", "-pair-func example.com/log.Info=0: offset 0 is before the variadic param of example.com/log.Info, at 1, so fixed params are checked as pairs"},
+		{"pair-func", "example.com/log.Fixed=0", "-pair-func example.com/log.Fixed=0: example.com/log.Fixed isn't variadic, so it can't take pairs"},
+		{"pair-func", "example.com/log.Missing=0", "-pair-func example.com/log.Missing=0: no func Missing in package example.com/log"},
+		{"pair-func", "example.com/log.Logger.Info=0", "-pair-func example.com/log.Logger.Info=0: type example.com/log.Logger has no method Info"},
+		{"pair-func", ".Info=0", "-pair-func .Info=0: no method Info in the packages loaded or their dependencies"},
+		{"pair-func", "example.com/other.Log=0", "-pair-func example.com/other.Log=0: package example.com/other isn't among the packages loaded or their dependencies"},
+		{"pair-func", "example.com/log.Log", "-pair-func example.com/log.Log: invalid func offset; should be of form [pkg[.type]].<func>=<offset>"},
+		{"assume-pair", "example.com/log.Pairs", ""},
+		{"assume-pair", "example.com/log.Missing", "-assume-pair example.com/log.Missing: no type Missing in package example.com/log"},
+		{"assume-pair", "example.com/other.Pairs", "-assume-pair example.com/other.Pairs: package example.com/other isn't among the packages loaded or their dependencies"},
+		{"assume-pair", "Pairs", "-assume-pair Pairs: should be of form <pkg>.<type>"},
+	}
+	for _, test := range tests {
+		t.Run(test.flag+" "+test.value, func(t *testing.T) {
+			flags := flag.NewFlagSet("test", flag.ContinueOnError)
+			flags.String("pair-func", "", "")
+			flags.String("assume-pair", "", "")
+			if err := flags.Set(test.flag, test.value); err != nil {
+				t.Fatal(err)
+			}
+
+			var expected []string
+			if test.expected != "" {
+				expected = []string{test.expected}
+			}
+			if d := cmp.Diff(expected, configDiagnosis(flags, loaded)); d != "" {
+				t.Errorf("unexpected problems (-expected +got):\n%s", d)
+			}
+		})
+	}
+
+	// the problems of every value are reported, in order
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.String("pair-func", strings.Join([]string{"example.com/log.Fixed=0", "example.com/log.Missing=0"}, ","), "")
+	if problems := configDiagnosis(flags, loaded); len(problems) != 2 {
+		t.Errorf("expected 2 problems, got %q", problems)
+	}
+}
@@ -0,0 +1,282 @@
+/*
+Package events checks analytics calls against a registry of events.
+
+Analytics SDKs commonly take an event name and a set of properties:
+
+	analytics.Track("signup_completed", map[string]interface{}{"plan": plan})
+
+The sole analyzer (from NewAnalyzer) in this package takes an -event-func
+flag naming such funcs, along with the offset of the event name; the
+properties are the rest of the args, either as a single map literal or as
+key/value pairs:
+
+	-event-func example.com/analytics.Track=0
+	-event-func .Track=1
+
+The -event-registry flag names a JSON file mapping each known event to the
+property keys it may carry:
+
+	{
+		"signup_completed": ["plan", "referrer"],
+		"checkout": ["cart_id", "total"]
+	}
+
+Event names that are not in the registry and property keys that are not
+registered for the event are reported.  Both must be constant strings to be
+checked; other names are reported as dynamic-event, and other keys as
+event-key, this analyzer's own rule rather than the pairs analyzer's.
+*/
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/ZipRecruiter/splinter/internal/calls"
+	"github.com/ZipRecruiter/splinter/internal/ignore"
+	"github.com/ZipRecruiter/splinter/internal/keys"
+	"github.com/ZipRecruiter/splinter/internal/ruledoc"
+)
+
+// The rules checked by the analyzer.  Each is used as the Category of the
+// diagnostics it reports.
+const (
+	UnknownEvent    = "unknown-event"
+	UnknownEventKey = "unknown-event-key"
+	DynamicEvent    = "dynamic-event"
+	EventKey        = "event-key"
+)
+
+// Rules lists every rule the analyzer can report.
+var Rules = []string{
+	UnknownEvent,
+	UnknownEventKey,
+	DynamicEvent,
+	EventKey,
+}
+
+// RuleDoc describes a rule for tools that list the rules.
+type RuleDoc struct {
+	// Code is the rule's stable code, like SPL101, which can be used in
+	// place of its name.  Codes are never changed or reused; the events
+	// analyzer's are SPL101 to SPL199.
+	Code string
+
+	Description string
+
+	// Flags are the flags that enable or configure the rule.
+	Flags []string
+
+	// OptIn is true if the rule is only checked once one of its flags
+	// is set.
+	OptIn bool
+
+	// Fixable is true if the rule's diagnostics can suggest a fix.
+	Fixable bool
+}
+
+// RuleDocs describes each of Rules.  None is checked until -event-func
+// names the event funcs.
+var RuleDocs = map[string]RuleDoc{
+	UnknownEvent: {
+		Code:        "SPL101",
+		Description: "an event name isn't in the event registry",
+		Flags:       []string{"event-func", "event-registry"},
+		OptIn:       true,
+	},
+	UnknownEventKey: {
+		Code:        "SPL102",
+		Description: "a property key isn't registered for the event",
+		Flags:       []string{"event-func", "event-registry"},
+		OptIn:       true,
+	},
+	DynamicEvent: {
+		Code:        "SPL103",
+		Description: "an event name isn't a constant string",
+		Flags:       []string{"event-func"},
+		OptIn:       true,
+	},
+	EventKey: {
+		Code:        "SPL104",
+		Description: "a property key isn't a constant string",
+		Flags:       []string{"event-func"},
+		OptIn:       true,
+	},
+}
+
+type funcOffset map[calls.Selector]int
+
+func (o funcOffset) Set(v string) error {
+	sel, offset, err := calls.ParseOffset(v)
+	if err != nil {
+		return err
+	}
+
+	o[sel] = offset
+	return nil
+}
+
+func (o funcOffset) String() string {
+	var s []string
+	for sel, offset := range o {
+		s = append(s, sel.String()+"="+strconv.Itoa(offset))
+	}
+	sort.Strings(s)
+	return strings.Join(s, ",")
+}
+
+// registry maps event names to the set of keys allowed for each.
+type registry map[string]map[string]bool
+
+func loadRegistry(path string) (registry, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string][]string
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("couldn't parse event registry %s: %w", path, err)
+	}
+
+	r := make(registry, len(raw))
+	for event, keys := range raw {
+		r[event] = map[string]bool{}
+		for _, k := range keys {
+			r[event][k] = true
+		}
+	}
+	return r, nil
+}
+
+type checker struct {
+	offsets      funcOffset
+	registryPath string
+
+	loadOnce sync.Once
+	registry registry
+	err      error
+}
+
+// NewAnalyzer returns a fresh events analyzer.
+func NewAnalyzer() *analysis.Analyzer {
+	fset := flag.NewFlagSet("events", flag.ContinueOnError)
+
+	c := &checker{offsets: funcOffset{}}
+
+	fset.Var(c.offsets, "event-func", "validate calls to this func against the event registry")
+	fset.StringVar(&c.registryPath, "event-registry", "", "JSON file mapping event names to their property keys")
+
+	return &analysis.Analyzer{
+		Name:  "events",
+		Doc:   "events verifies analytics event names and property keys against a registry; see -event-func and -event-registry",
+		URL:   ruledoc.URL,
+		Flags: *fset,
+		Run:   c.run,
+	}
+}
+
+func (c *checker) report(p *analysis.Pass, n ast.Node, rule, format string, args ...interface{}) {
+	p.Report(analysis.Diagnostic{
+		Pos:      n.Pos(),
+		End:      n.End(),
+		Category: rule,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *checker) run(p *analysis.Pass) (interface{}, error) {
+	if len(c.offsets) == 0 {
+		return nil, nil
+	}
+	c.loadOnce.Do(func() {
+		if c.registryPath == "" {
+			c.err = errors.New("-event-func requires -event-registry")
+			return
+		}
+		c.registry, c.err = loadRegistry(c.registryPath)
+	})
+	if c.err != nil {
+		return nil, c.err
+	}
+	p = ruledoc.Link(p)
+	p = ignore.Filter(p)
+
+	inspector.New(p.Files).Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		callee, ok := calls.Resolve(p.TypesInfo, call)
+		if !ok {
+			return
+		}
+
+		for _, sel := range callee.Selectors() {
+			if offset, ok := c.offsets[sel]; ok {
+				c.trackCorrect(p, callee.Name, offset, call)
+				return
+			}
+		}
+	})
+	return nil, nil
+}
+
+func (c *checker) trackCorrect(p *analysis.Pass, name string, offset int, call *ast.CallExpr) {
+	if len(call.Args) <= offset {
+		return
+	}
+
+	nameArg := call.Args[offset]
+	kind, _, event := keys.Classify(p.TypesInfo, nameArg)
+	if kind != keys.Constant {
+		c.report(p, nameArg, DynamicEvent, "event name passed to %s should be a constant string", name)
+		return
+	}
+	allowed, ok := c.registry[event]
+	if !ok {
+		c.report(p, nameArg, UnknownEvent, "event %q passed to %s is not in the registry", event, name)
+		return
+	}
+
+	props := call.Args[offset+1:]
+	if len(props) == 1 {
+		if lit, ok := ast.Unparen(props[0]).(*ast.CompositeLit); ok {
+			if _, ok := p.TypesInfo.TypeOf(lit).Underlying().(*types.Map); ok {
+				for _, elt := range lit.Elts {
+					if kv, ok := elt.(*ast.KeyValueExpr); ok {
+						c.keyCorrect(p, event, allowed, kv.Key)
+					}
+				}
+			}
+		}
+		// other property values, like variables, can't be checked
+		return
+	}
+
+	for i, a := range props {
+		if i%2 == 0 {
+			c.keyCorrect(p, event, allowed, a)
+		}
+	}
+}
+
+func (c *checker) keyCorrect(p *analysis.Pass, event string, allowed map[string]bool, k ast.Expr) {
+	kind, typ, key := keys.Classify(p.TypesInfo, k)
+	switch kind {
+	case keys.Constant:
+		if !allowed[key] {
+			c.report(p, k, UnknownEventKey, "key %q is not registered for event %q", key, event)
+		}
+	case keys.Expression, keys.StringTypeParam, keys.NonStringConstant, keys.NonStringExpression:
+		c.report(p, k, EventKey, "key for event %q is %s but should be a constant string", event, types.TypeString(typ, nil))
+	}
+}
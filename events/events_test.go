@@ -0,0 +1,106 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalysis(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import "a/analytics"
+
+type Props map[string]interface{}
+
+func Foo(plan string, c *analytics.Client) {
+	analytics.Track("signup", map[string]interface{}{"plan": plan, "referrer": "ad"})
+	analytics.Track("signup", map[string]interface{}{"plna": plan}) // want "key \"plna\" is not registered for event \"signup\""
+	analytics.Track("signup", Props{plan: plan}) // want "key for event \"signup\" is string but should be a constant string"
+	analytics.Track("sginup", nil) // want "event \"sginup\" passed to a/analytics.Track is not in the registry"
+	analytics.Track(plan, nil) // want "event name passed to a/analytics.Track should be a constant string"
+
+	// properties that aren't literals can't be checked
+	props := Props{"nope": 1}
+	analytics.Track("signup", props)
+
+	// pairs
+	c.Track(1, "checkout", "cart_id", 1, "total", 2)
+	c.Track(1, "checkout", "cart", 1) // want "key \"cart\" is not registered for event \"checkout\""
+	c.Track(1, "checkout", 1, 1) // want "key for event \"checkout\" is int but should be a constant string"
+}
+`,
+		"a/analytics/analytics.go": `package analytics
+
+func Track(event string, props map[string]interface{}) {}
+
+type Client struct{}
+
+func (c *Client) Track(userID int, event string, props ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	registry := filepath.Join(dir, "registry.json")
+	if err := os.WriteFile(registry, []byte(`{
+		"signup": ["plan", "referrer"],
+		"checkout": ["cart_id", "total"]
+	}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("event-func", "a/analytics.Track=0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Flags.Set("event-func", "a/analytics.Client.Track=1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Flags.Set("event-registry", registry); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
+
+func TestRuleDocs(t *testing.T) {
+	a := NewAnalyzer()
+	code := regexp.MustCompile(`^SPL1\d\d$`)
+	codes := map[string]string{}
+	page, err := os.ReadFile("../docs/rules.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rule := range Rules {
+		if !strings.Contains(string(page), "\n## "+rule+"\n") {
+			t.Errorf("docs/rules.md has no section for %s; regenerate it with splinter rules -markdown", rule)
+		}
+		doc, ok := RuleDocs[rule]
+		if !ok {
+			t.Errorf("no RuleDocs entry for %s", rule)
+			continue
+		}
+		if !code.MatchString(doc.Code) {
+			t.Errorf("%s has code %q; should be SPL101 to SPL199", rule, doc.Code)
+		}
+		if other, ok := codes[doc.Code]; ok {
+			t.Errorf("%s has the code of %s, %s", rule, other, doc.Code)
+		}
+		codes[doc.Code] = rule
+		for _, name := range doc.Flags {
+			if a.Flags.Lookup(name) == nil {
+				t.Errorf("%s names unknown flag -%s", rule, name)
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+)
+
+// pairsFacts returns the package facts of type F the pairs analyzer
+// exported in actions, by import path.  A package and its test variant
+// both export facts; the variant with the most files includes everything
+// the other does, so its fact is the one returned.
+func pairsFacts[F any, PF interface {
+	*F
+	analysis.Fact
+}](actions func(yield func(*checker.Action) bool)) map[string]PF {
+	type variant struct {
+		files int
+		fact  PF
+	}
+	byPkg := map[string]variant{}
+	actions(func(act *checker.Action) bool {
+		if act.Analyzer.Name != "pairs" {
+			return true
+		}
+		fact := PF(new(F))
+		if !act.PackageFact(act.Package.Types, fact) {
+			return true
+		}
+		if v, ok := byPkg[act.Package.PkgPath]; !ok || len(act.Package.CompiledGoFiles) > v.files {
+			byPkg[act.Package.PkgPath] = variant{len(act.Package.CompiledGoFiles), fact}
+		}
+		return true
+	})
+
+	facts := map[string]PF{}
+	for path, v := range byPkg {
+		facts[path] = v.fact
+	}
+	return facts
+}
+
+// roots returns the actions of the root packages of graph, in the form of
+// graph.All.
+func roots(graph *checker.Graph) func(yield func(*checker.Action) bool) {
+	return func(yield func(*checker.Action) bool) {
+		for _, act := range graph.Roots {
+			if !yield(act) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+
+	"github.com/ZipRecruiter/splinter/internal/driver"
+	"github.com/ZipRecruiter/splinter/pairs"
+)
+
+func TestPairsFacts(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"log/log.go":  "package log\n\nfunc Log(kv ...interface{}) {}\n",
+		"a/a.go":      "package a\n\nimport \"m/log\"\n\nfunc F() {\n\tlog.Log(\"a\", 1)\n}\n",
+		"a/a_test.go": "package a\n\nimport \"m/log\"\n\nfunc G() {\n\tlog.Log(\"test\", 1)\n}\n",
+		"b/b.go":      "package b\n\nimport \"m/log\"\n\nfunc F() {\n\tlog.Log(\"b\", 1)\n}\n",
+	})
+
+	a := pairs.NewAnalyzer()
+	for name, value := range map[string]string{"pair-func": "m/log.Log=0", "sites": "true"} {
+		if err := a.Flags.Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pkgs, err := driver.Load(driver.LoadConfig{Tests: true, Dir: dir}, "./a", "./b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	graph, err := checker.Analyze([]*analysis.Analyzer{a}, pkgs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the test variant of a, with the most files, is the one kept
+	for _, test := range []struct {
+		name    string
+		actions func(yield func(*checker.Action) bool)
+	}{
+		{"roots", roots(graph)},
+		{"all", graph.All()},
+	} {
+		var got []string
+		for path, fact := range pairsFacts[pairs.CallSites](test.actions) {
+			var files []string
+			for _, s := range fact.Sites {
+				files = append(files, filepath.Base(s.File))
+			}
+			got = append(got, path+": "+strings.Join(files, " "))
+		}
+		sort.Strings(got)
+		if s, expected := strings.Join(got, "; "), "m/a: a.go a_test.go; m/b: b.go"; s != expected {
+			t.Errorf("%s: expected %s, got %s", test.name, expected, s)
+		}
+	}
+}
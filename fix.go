@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+
+	"github.com/ZipRecruiter/splinter/internal/config"
+	"github.com/ZipRecruiter/splinter/internal/driver"
+)
+
+// fix implements `splinter fix`, which applies the suggested fixes of the
+// selected rules and prints a summary.
+func fix(analyzers []*analysis.Analyzer, args []string) int {
+	fset := flag.NewFlagSet("fix", flag.ExitOnError)
+	fset.Usage = func() {
+		fmt.Fprintf(fset.Output(), "usage: splinter fix [-rules rule,...] [-dry-run] [analyzer flags] packages...\n\n")
+		fset.PrintDefaults()
+	}
+	fixable := fixableRules()
+	selectedRules := fset.String("rules", "", "comma separated rules or codes to apply fixes for; all if empty (only "+strings.Join(fixable, ", ")+" suggest fixes)")
+	dryRun := fset.Bool("dry-run", false, "print a diff of the fixes instead of writing them")
+	workspace := fset.Bool("workspace", false, "fix every module of the enclosing go.work workspace; relative patterns (default ./...) apply within each module")
+	analyzerFlags(fset, analyzers)
+	config.Parse(fset, args)
+
+	given := driver.RuleSet{}
+	given.Set(*selectedRules)
+	if err := validRules(given); err != nil {
+		fmt.Fprintf(os.Stderr, "splinter fix: %s\n", err)
+		return 2
+	}
+	names := map[string]string{}
+	for r, code := range ruleCodes() {
+		names[r] = r
+		names[code] = r
+	}
+	selected := driver.RuleSet{}
+	for r := range given {
+		selected[names[r]] = true
+	}
+
+	pkgs, err := driver.Load(driver.LoadConfig{Tests: true, Workspace: *workspace}, fset.Args()...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter fix: %s\n", err)
+		return 1
+	}
+	graph, err := checker.Analyze(analyzers, pkgs, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter fix: %s\n", err)
+		return 1
+	}
+	diags, err := driver.Diagnostics(graph)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter fix: %s\n", err)
+		return 1
+	}
+
+	fixes := driver.Fixes(diags, selected)
+	if *dryRun {
+		if err := fixes.Diff(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "splinter fix: %s\n", err)
+			return 1
+		}
+		fmt.Println("would apply " + fixes.Summary())
+		return 0
+	}
+
+	if err := fixes.Write(); err != nil {
+		fmt.Fprintf(os.Stderr, "splinter fix: %s\n", err)
+		return 1
+	}
+	fmt.Println("applied " + fixes.Summary())
+	return 0
+}
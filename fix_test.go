@@ -0,0 +1,47 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/events"
+	"github.com/ZipRecruiter/splinter/pairs"
+)
+
+func TestFixRules(t *testing.T) {
+	writeModule(t, map[string]string{
+		"log/log.go": "package log\n\nfunc Log(kv ...interface{}) {}\n",
+		"a/a.go":     "package a\n\nimport \"m/log\"\n\nfunc F() {\n\tlog.Log(\"UserID\", 1, \"a\")\n}\n",
+	})
+
+	tests := []struct {
+		rules    string
+		expected string
+		code     int
+	}{
+		{"key-pattern", "would apply 1 fixes in 1 files (key-pattern: 1)\n", 0},
+		{"SPL007", "would apply 1 fixes in 1 files (key-pattern: 1)\n", 0},
+		{"numeric-key", "would apply 0 fixes in 0 files\n", 0},
+		{"key-pattern,odd-arity", "would apply 1 fixes in 1 files (key-pattern: 1)\n", 0},
+		{"SPL007,SPL001", "would apply 1 fixes in 1 files (key-pattern: 1)\n", 0},
+		{"key-pattern,no-such-rule", "", 2},
+	}
+	for _, test := range tests {
+		t.Run(test.rules, func(t *testing.T) {
+			analyzers := []*analysis.Analyzer{pairs.NewAnalyzer(), events.NewAnalyzer()}
+			args := []string{"-rules", test.rules, "-dry-run", "-pair-func", "m/log.Log=0", "-key-case", "snake", "./a"}
+			out, code := captureStdout(t, func() int { return fix(analyzers, args) })
+			if code != test.code {
+				t.Errorf("expected exit code %d, got %d", test.code, code)
+			}
+			if i := strings.Index(out, "would apply"); i >= 0 {
+				out = out[i:]
+			}
+			if out != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, out)
+			}
+		})
+	}
+}
@@ -1,8 +1,16 @@
 module github.com/ZipRecruiter/splinter
 
-go 1.14
+go 1.22.0
 
 require (
-	github.com/google/go-cmp v0.4.0
-	golang.org/x/tools v0.0.0-20200114235610-7ae403b6b589
+	github.com/fsnotify/fsnotify v1.7.0
+	github.com/google/go-cmp v0.6.0
+	golang.org/x/mod v0.23.0
+	golang.org/x/tools v0.30.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	golang.org/x/sync v0.11.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
 )
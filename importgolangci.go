@@ -0,0 +1,474 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/types"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ZipRecruiter/splinter/internal/calls"
+	"github.com/ZipRecruiter/splinter/internal/config"
+	"github.com/ZipRecruiter/splinter/pairs"
+)
+
+// importGolangci implements `splinter import-golangci`, which converts the
+// loggercheck and sloglint settings of a golangci-lint configuration file,
+// along with those of splinter as a custom linter, into a splinter
+// configuration file.
+func importGolangci(args []string) int {
+	fset := flag.NewFlagSet("import-golangci", flag.ExitOnError)
+	fset.Usage = func() {
+		fmt.Fprintf(fset.Output(), "usage: splinter import-golangci [-o file] [-force] .golangci.yml\n\n")
+		fset.PrintDefaults()
+	}
+	out := fset.String("o", "", "write the configuration to this file rather than to stdout")
+	force := fset.Bool("force", false, "overwrite the file if it already exists")
+	fset.Parse(args)
+	if fset.NArg() != 1 {
+		fset.Usage()
+		return 2
+	}
+	path := fset.Arg(0)
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter import-golangci: %s\n", err)
+		return 1
+	}
+	var golangci map[string]any
+	if err := yaml.Unmarshal(src, &golangci); err != nil {
+		fmt.Fprintf(os.Stderr, "splinter import-golangci: %s: %s\n", path, err)
+		return 1
+	}
+	imp, err := importSettings(golangci)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter import-golangci: %s: %s\n", path, err)
+		return 1
+	}
+	imp.resolveOffsets()
+
+	name := *out
+	if name == "" {
+		name = ".splinter.yaml"
+	}
+	converted := imp.config(path, name)
+	if *out == "" {
+		os.Stdout.Write(converted)
+	} else {
+		if _, err := os.Stat(*out); err == nil && !*force {
+			fmt.Fprintf(os.Stderr, "splinter import-golangci: %s already exists; use -force to overwrite it\n", *out)
+			return 1
+		} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "splinter import-golangci: %s\n", err)
+			return 1
+		}
+		if err := os.WriteFile(*out, converted, 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "splinter import-golangci: %s\n", err)
+			return 1
+		}
+	}
+
+	for _, n := range imp.unconverted {
+		fmt.Fprintf(os.Stderr, "splinter import-golangci: not converted: %s\n", n)
+	}
+	for _, r := range imp.rules {
+		if r.err != nil {
+			fmt.Fprintf(os.Stderr, "splinter import-golangci: loggercheck rule %s: %s; its pair-func is commented out\n", r.rule, r.err)
+		}
+	}
+	return 0
+}
+
+// loggercheckPresets maps the library switches of loggercheck to the
+// presets checking the same libraries.
+var loggercheckPresets = []struct{ setting, preset string }{
+	{"slog", "slog"},
+	{"kitlog", "go-kit"},
+	{"klog", "klog"},
+	{"logr", "logr"},
+	{"zap", "zap-sugar"},
+}
+
+// golangciImport is a splinter configuration converted from the settings of
+// golangci-lint.
+type golangciImport struct {
+	presets    []string
+	noDefaults bool
+
+	// rules are the custom funcs of loggercheck, as pair funcs.
+	rules []loggercheckRule
+
+	// entries set the rest of the flags, in order, each with a comment
+	// naming the setting it came from.
+	entries []config.Entry
+
+	// unconverted describes the settings splinter has no equivalent of.
+	unconverted []string
+}
+
+// loggercheckRule is a custom func of loggercheck, which checks its
+// variadic param as pairs.
+type loggercheckRule struct {
+	rule   string
+	sel    calls.Selector
+	offset int
+	err    error
+}
+
+// importSettings converts golangci, a golangci-lint configuration of either
+// version, enabling loggercheck or sloglint or carrying splinter settings.
+func importSettings(golangci map[string]any) (*golangciImport, error) {
+	linters := mapping(golangci["linters"])
+	settings := mapping(golangci["linters-settings"])
+	if s := mapping(linters["settings"]); s != nil {
+		settings = s
+	}
+	custom := mapping(mapping(mapping(settings["custom"])["splinter"])["settings"])
+
+	imp := &golangciImport{}
+	loggercheck := linterEnabled(linters, "loggercheck")
+	sloglint := linterEnabled(linters, "sloglint")
+	if !loggercheck && !sloglint && custom == nil {
+		return nil, errors.New("enables neither loggercheck nor sloglint, and has no settings for splinter")
+	}
+
+	slog := sloglint
+	if loggercheck {
+		lc := mapping(settings["loggercheck"])
+		for _, p := range loggercheckPresets {
+			on, err := boolSetting(lc, p.setting, true)
+			if err != nil {
+				return nil, fmt.Errorf("loggercheck: %w", err)
+			}
+			if on && p.preset == pairs.DefaultPreset {
+				slog = true
+			} else if on {
+				imp.preset(p.preset)
+			}
+		}
+		if noPrintf, err := boolSetting(lc, "no-printf-like", false); err != nil {
+			return nil, fmt.Errorf("loggercheck: %w", err)
+		} else if noPrintf {
+			imp.unconverted = append(imp.unconverted, "loggercheck no-printf-like")
+		}
+		rules, _ := lc["rules"].([]any)
+		for _, r := range rules {
+			rule := fmt.Sprint(r)
+			sel, err := parseLoggercheckRule(rule)
+			if err != nil {
+				return nil, fmt.Errorf("loggercheck: %w", err)
+			}
+			imp.rules = append(imp.rules, loggercheckRule{rule: rule, sel: sel})
+		}
+	}
+	if sloglint {
+		if err := imp.sloglint(mapping(settings["sloglint"])); err != nil {
+			return nil, fmt.Errorf("sloglint: %w", err)
+		}
+	}
+
+	if custom != nil {
+		entries, err := config.Settings(custom)
+		if err != nil {
+			return nil, fmt.Errorf("custom splinter settings: %w", err)
+		}
+		for _, e := range entries {
+			switch e.Flag {
+			case "preset":
+				for _, p := range strings.Split(e.Value, ",") {
+					if p = strings.TrimSpace(p); p == pairs.DefaultPreset {
+						slog = true
+					} else if p != "" {
+						imp.preset(p)
+					}
+				}
+			case "no-defaults":
+				// decided by whether slog is checked at all
+			default:
+				e.Comment = "custom splinter settings"
+				imp.entries = append(imp.entries, e)
+			}
+		}
+	}
+
+	if slog && len(imp.presets) != 0 {
+		imp.presets = append([]string{pairs.DefaultPreset}, imp.presets...)
+	}
+	imp.noDefaults = !slog
+	return imp, nil
+}
+
+// sloglint converts the sloglint settings.
+func (imp *golangciImport) sloglint(settings map[string]any) error {
+	for _, name := range sortedKeys(settings) {
+		v := settings[name]
+		switch name {
+		case "key-naming-case":
+			switch c := fmt.Sprint(v); c {
+			case "snake", "kebab", "camel":
+				imp.entries = append(imp.entries, config.Entry{Flag: "key-case", Value: c, Comment: "sloglint key-naming-case"})
+			default:
+				imp.unconverted = append(imp.unconverted, fmt.Sprintf("sloglint key-naming-case: %s", c))
+			}
+		case "no-raw-keys":
+			on, err := boolSetting(settings, name, false)
+			if err != nil {
+				return err
+			}
+			if on {
+				imp.entries = append(imp.entries, config.Entry{Flag: "repeated-keys", Value: "true", Comment: "sloglint no-raw-keys; reports only literal keys used more than once"})
+			}
+		case "no-mixed-args":
+			// sloglint's default, and splinter has no equivalent
+			if on, err := boolSetting(settings, name, true); err != nil {
+				return err
+			} else if !on {
+				continue
+			}
+			fallthrough
+		default:
+			if unset(v) {
+				continue
+			}
+			imp.unconverted = append(imp.unconverted, fmt.Sprintf("sloglint %s: %s", name, settingString(v)))
+		}
+	}
+	return nil
+}
+
+// preset adds the preset named name, unless it's already added or isn't in
+// this build of splinter.
+func (imp *golangciImport) preset(name string) {
+	if slices.Contains(imp.presets, name) {
+		return
+	}
+	for _, p := range pairs.Presets {
+		if p.Name == name {
+			imp.presets = append(imp.presets, name)
+			return
+		}
+	}
+	imp.unconverted = append(imp.unconverted, fmt.Sprintf("preset %s, which isn't in this build of splinter", name))
+}
+
+// resolveOffsets type checks the packages of the custom loggercheck rules
+// to find the offsets of their variadic params.
+func (imp *golangciImport) resolveOffsets() {
+	if len(imp.rules) == 0 {
+		return
+	}
+	var paths []string
+	for _, r := range imp.rules {
+		if !slices.Contains(paths, r.sel.Pkg) {
+			paths = append(paths, r.sel.Pkg)
+		}
+	}
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.LoadAllSyntax}, paths...)
+	byPath := map[string]*packages.Package{}
+	for _, p := range pkgs {
+		byPath[p.PkgPath] = p
+	}
+
+	for i, r := range imp.rules {
+		p := byPath[r.sel.Pkg]
+		switch {
+		case err != nil:
+			imp.rules[i].err = err
+		case p == nil || p.Types == nil:
+			imp.rules[i].err = fmt.Errorf("can't load package %s", r.sel.Pkg)
+		case len(p.Errors) != 0:
+			imp.rules[i].err = p.Errors[0]
+		default:
+			imp.rules[i].offset, imp.rules[i].err = variadicOffset(p.Types, r.sel)
+		}
+	}
+}
+
+// variadicOffset returns the offset of the variadic param of the func or
+// method of pkg that sel names.
+func variadicOffset(pkg *types.Package, sel calls.Selector) (int, error) {
+	var obj types.Object
+	if sel.Typ == "" {
+		obj = pkg.Scope().Lookup(sel.Fun)
+	} else if typ := pkg.Scope().Lookup(sel.Typ); typ != nil {
+		obj, _, _ = types.LookupFieldOrMethod(typ.Type(), true, pkg, sel.Fun)
+	}
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return 0, fmt.Errorf("no func %s", sel)
+	}
+	sig := fn.Type().(*types.Signature)
+	if !sig.Variadic() {
+		return 0, fmt.Errorf("%s isn't variadic", sel)
+	}
+	return sig.Params().Len() - 1, nil
+}
+
+// parseLoggercheckRule parses a custom loggercheck rule, which names a func
+// as <pkg>.<func> or a method as (<pkg>.<type>).<method> or
+// (*<pkg>.<type>).<method>, into a selector.
+func parseLoggercheckRule(rule string) (calls.Selector, error) {
+	if !strings.HasPrefix(rule, "(") {
+		i := strings.LastIndexByte(rule, '.')
+		if i <= 0 || strings.Contains(rule[i:], "/") {
+			return calls.Selector{}, fmt.Errorf("invalid rule %q; should be of form <pkg>.<func> or (<pkg>.<type>).<method>", rule)
+		}
+		return calls.Selector{Pkg: rule[:i], Fun: rule[i+1:]}, nil
+	}
+
+	recv, method, ok := strings.Cut(strings.TrimPrefix(rule, "("), ").")
+	recv = strings.TrimPrefix(recv, "*")
+	i := strings.LastIndexByte(recv, '.')
+	if !ok || method == "" || i <= 0 || strings.Contains(recv[i:], "/") {
+		return calls.Selector{}, fmt.Errorf("invalid rule %q; should be of form <pkg>.<func> or (<pkg>.<type>).<method>", rule)
+	}
+	return calls.Selector{Pkg: recv[:i], Typ: recv[i+1:], Fun: method}, nil
+}
+
+// config returns the contents of the converted configuration file, to be
+// written to path, naming the golangci-lint file from.
+func (imp *golangciImport) config(from, path string) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# splinter configuration, converted from %s by splinter import-golangci.\n", from)
+	fmt.Fprintf(&b, "# Each entry sets the flag of the same name, and a list sets a repeated\n")
+	fmt.Fprintf(&b, "# flag once per element:\n")
+	fmt.Fprintf(&b, "#\n")
+	fmt.Fprintf(&b, "#\tsplinter -config %s ./...\n", path)
+
+	if len(imp.presets) != 0 {
+		fmt.Fprintf(&b, "\n# presets for the libraries loggercheck and sloglint checked\n")
+		fmt.Fprintf(&b, "preset:\n")
+		for _, p := range imp.presets {
+			fmt.Fprintf(&b, "  - %s\n", p)
+		}
+	}
+	if imp.noDefaults {
+		fmt.Fprintf(&b, "\n# loggercheck didn't check log/slog\n")
+		fmt.Fprintf(&b, "no-defaults: true\n")
+	}
+
+	if len(imp.rules) != 0 {
+		fmt.Fprintf(&b, "\n# the custom rules of loggercheck, which checks their variadic params\n")
+		fmt.Fprintf(&b, "pair-func:\n")
+		for _, r := range imp.rules {
+			if r.err != nil {
+				fmt.Fprintf(&b, "  # - %s=<offset>  # %s: %s\n", r.sel, r.rule, r.err)
+				continue
+			}
+			fmt.Fprintf(&b, "  - %s=%d  # %s\n", r.sel, r.offset, r.rule)
+		}
+	}
+
+	for i := 0; i < len(imp.entries); {
+		e, n := imp.entries[i], 1
+		for i+n < len(imp.entries) && imp.entries[i+n].Flag == e.Flag {
+			n++
+		}
+		fmt.Fprintf(&b, "\n")
+		if i == 0 || e.Comment != imp.entries[i-1].Comment {
+			fmt.Fprintf(&b, "# %s\n", e.Comment)
+		}
+		if n == 1 {
+			fmt.Fprintf(&b, "%s: %s\n", e.Flag, yamlString(e.Value))
+		} else {
+			fmt.Fprintf(&b, "%s:\n", e.Flag)
+			for _, e := range imp.entries[i : i+n] {
+				fmt.Fprintf(&b, "  - %s\n", yamlString(e.Value))
+			}
+		}
+		i += n
+	}
+
+	if len(imp.unconverted) != 0 {
+		fmt.Fprintf(&b, "\n# not converted, since splinter has no equivalent:\n")
+		for _, n := range imp.unconverted {
+			fmt.Fprintf(&b, "#\t%s\n", n)
+		}
+	}
+	return b.Bytes()
+}
+
+// linterEnabled reports whether golangci-lint runs the linter named name
+// under linters, the linters section of either version of its
+// configuration.
+func linterEnabled(linters map[string]any, name string) bool {
+	named := func(key string) bool {
+		list, _ := linters[key].([]any)
+		for _, l := range list {
+			if fmt.Sprint(l) == name {
+				return true
+			}
+		}
+		return false
+	}
+	if named("disable") {
+		return false
+	}
+	all, _ := linters["enable-all"].(bool)
+	return named("enable") || all || linters["default"] == "all"
+}
+
+// mapping returns v as a mapping, or nil if it isn't one.
+func mapping(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+// boolSetting returns the boolean setting name of settings, or def if it
+// isn't set.
+func boolSetting(settings map[string]any, name string, def bool) (bool, error) {
+	v, ok := settings[name]
+	if !ok || v == nil {
+		return def, nil
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s is %v; should be true or false", name, v)
+	}
+	return b, nil
+}
+
+// unset reports whether v, the value of a setting, is false, empty or null,
+// as sloglint's settings are by default.
+func unset(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	}
+	return false
+}
+
+// settingString formats the value of a setting for a comment.
+func settingString(v any) string {
+	if list, ok := v.([]any); ok {
+		var s []string
+		for _, e := range list {
+			s = append(s, fmt.Sprint(e))
+		}
+		return "[" + strings.Join(s, ", ") + "]"
+	}
+	return fmt.Sprint(v)
+}
+
+// yamlString quotes v if YAML wouldn't read it back as the same string.
+func yamlString(v string) string {
+	var back string
+	if err := yaml.Unmarshal([]byte(v), &back); err == nil && back == v {
+		return v
+	}
+	return fmt.Sprintf("%q", v)
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ZipRecruiter/splinter/internal/calls"
+	"github.com/ZipRecruiter/splinter/internal/config"
+)
+
+func TestImportSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		golangci string
+		expected *golangciImport
+		err      string
+	}{
+		{
+			name: "loggercheck defaults",
+			golangci: `
+linters:
+  enable: [loggercheck]
+`,
+			expected: &golangciImport{presets: []string{"slog", "go-kit", "klog", "logr", "zap-sugar"}},
+		},
+		{
+			name: "loggercheck rules",
+			golangci: `
+linters:
+  enable: [loggercheck]
+linters-settings:
+  loggercheck:
+    slog: false
+    kitlog: false
+    klog: false
+    logr: false
+    no-printf-like: true
+    rules:
+      - example.com/log.Log
+      - (*example.com/log.Logger).Info
+`,
+			expected: &golangciImport{
+				noDefaults: true,
+				rules: []loggercheckRule{
+					{rule: "example.com/log.Log", sel: calls.Selector{Pkg: "example.com/log", Fun: "Log"}},
+					{rule: "(*example.com/log.Logger).Info", sel: calls.Selector{Pkg: "example.com/log", Typ: "Logger", Fun: "Info"}},
+				},
+				presets:     []string{"zap-sugar"},
+				unconverted: []string{"loggercheck no-printf-like"},
+			},
+		},
+		{
+			name: "sloglint",
+			golangci: `
+linters:
+  enable: [sloglint]
+linters-settings:
+  sloglint:
+    key-naming-case: snake
+    no-raw-keys: true
+    attr-only: true
+    static-msg: false
+`,
+			expected: &golangciImport{
+				entries: []config.Entry{
+					{Flag: "key-case", Value: "snake", Comment: "sloglint key-naming-case"},
+					{Flag: "repeated-keys", Value: "true", Comment: "sloglint no-raw-keys; reports only literal keys used more than once"},
+				},
+				unconverted: []string{"sloglint attr-only: true"},
+			},
+		},
+		{
+			name: "version 2 custom settings",
+			golangci: `
+version: "2"
+linters:
+  default: none
+  settings:
+    custom:
+      splinter:
+        settings:
+          preset: zerolog
+          pair-func: example.com/log.Log=0
+`,
+			expected: &golangciImport{
+				presets:    []string{"zerolog"},
+				noDefaults: true,
+				entries:    []config.Entry{{Flag: "pair-func", Value: "example.com/log.Log=0", Comment: "custom splinter settings"}},
+			},
+		},
+		{
+			name: "loggercheck disabled",
+			golangci: `
+linters:
+  enable-all: true
+  disable: [loggercheck, sloglint]
+`,
+			err: "enables neither loggercheck nor sloglint, and has no settings for splinter",
+		},
+		{
+			name: "invalid switch",
+			golangci: `
+linters:
+  enable: [loggercheck]
+linters-settings:
+  loggercheck:
+    zap: sometimes
+`,
+			err: "loggercheck: zap is sometimes; should be true or false",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var golangci map[string]any
+			if err := yaml.Unmarshal([]byte(test.golangci), &golangci); err != nil {
+				t.Fatal(err)
+			}
+			imp, err := importSettings(golangci)
+			if test.err != "" {
+				if err == nil || err.Error() != test.err {
+					t.Errorf("expected error %q, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(test.expected, imp, cmp.AllowUnexported(golangciImport{}, loggercheckRule{})); d != "" {
+				t.Errorf("unexpected import (-expected +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestParseLoggercheckRule(t *testing.T) {
+	tests := []struct {
+		rule     string
+		expected calls.Selector
+		ok       bool
+	}{
+		{"example.com/log.Log", calls.Selector{Pkg: "example.com/log", Fun: "Log"}, true},
+		{"(example.com/log.Logger).Info", calls.Selector{Pkg: "example.com/log", Typ: "Logger", Fun: "Info"}, true},
+		{"(*example.com/log.Logger).Info", calls.Selector{Pkg: "example.com/log", Typ: "Logger", Fun: "Info"}, true},
+		{"k8s.io/klog/v2.InfoS", calls.Selector{Pkg: "k8s.io/klog/v2", Fun: "InfoS"}, true},
+		{"Log", calls.Selector{}, false},
+		{"example.com/log", calls.Selector{}, false},
+		{"(example.com/log.Logger)", calls.Selector{}, false},
+		{"(example.com/log.Logger).", calls.Selector{}, false},
+		{"(Logger).Info", calls.Selector{}, false},
+	}
+	for _, test := range tests {
+		sel, err := parseLoggercheckRule(test.rule)
+		if ok := err == nil; ok != test.ok {
+			t.Errorf("%s: expected ok %t, got error %v", test.rule, test.ok, err)
+			continue
+		}
+		if sel != test.expected {
+			t.Errorf("%s: expected %+v, got %+v", test.rule, test.expected, sel)
+		}
+	}
+}
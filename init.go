@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/ZipRecruiter/splinter/pairs"
+)
+
+// initConfig implements `splinter init`, which writes a starter
+// configuration file enabling the presets of the libraries the packages
+// import.
+func initConfig(args []string) int {
+	fset := flag.NewFlagSet("init", flag.ExitOnError)
+	fset.Usage = func() {
+		fmt.Fprintf(fset.Output(), "usage: splinter init [-o file] [-force] [-scan] [packages]\n\n")
+		fset.PrintDefaults()
+	}
+	out := fset.String("o", ".splinter.yaml", "write the configuration to this file")
+	force := fset.Bool("force", false, "overwrite the file if it already exists")
+	scan := fset.Bool("scan", false, "type check the packages and suggest the variadic ...interface{} funcs whose calls mostly pass pairs as pair funcs")
+	fset.Parse(args)
+
+	patterns := fset.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	mode := packages.NeedName | packages.NeedImports
+	if *scan {
+		mode = packages.LoadAllSyntax | packages.NeedModule
+	}
+	pkgs, err := packages.Load(&packages.Config{Mode: mode, Tests: true}, patterns...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter init: %s\n", err)
+		return 1
+	}
+	if n := packages.PrintErrors(pkgs); n > 0 {
+		fmt.Fprintf(os.Stderr, "splinter init: %d errors loading packages\n", n)
+		return 1
+	}
+
+	imports := map[string]bool{}
+	for _, p := range pkgs {
+		for path := range p.Imports {
+			imports[path] = true
+		}
+	}
+	presets := detectPresets(imports)
+
+	var candidates []pairCandidate
+	if *scan {
+		var presetPkgs []string
+		for _, p := range pairs.Presets {
+			for _, name := range presets {
+				if p.Name == name {
+					presetPkgs = append(presetPkgs, p.Packages...)
+				}
+			}
+		}
+		candidates = scanPairFuncs(pkgs, presetPkgs)
+	}
+
+	if _, err := os.Stat(*out); err == nil && !*force {
+		fmt.Fprintf(os.Stderr, "splinter init: %s already exists; use -force to overwrite it\n", *out)
+		return 1
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "splinter init: %s\n", err)
+		return 1
+	}
+	if err := os.WriteFile(*out, starterConfig(*out, presets, candidates), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "splinter init: %s\n", err)
+		return 1
+	}
+
+	switch {
+	case len(presets) == 0 && len(candidates) == 0:
+		fmt.Printf("wrote %s; no known libraries found, so add pair funcs by hand\n", *out)
+	case len(candidates) == 0:
+		fmt.Printf("wrote %s with presets %s\n", *out, strings.Join(presets, ", "))
+	case len(presets) == 0:
+		fmt.Printf("wrote %s with %d suggested pair funcs to review\n", *out, len(candidates))
+	default:
+		fmt.Printf("wrote %s with presets %s and %d suggested pair funcs to review\n", *out, strings.Join(presets, ", "), len(candidates))
+	}
+	return 0
+}
+
+// detectPresets returns the names of the presets for the libraries among
+// imports, including their subpackages.
+func detectPresets(imports map[string]bool) []string {
+	var names []string
+	for _, p := range pairs.Presets {
+		for _, pkg := range p.Packages {
+			found := imports[pkg]
+			for path := range imports {
+				found = found || strings.HasPrefix(path, pkg+"/")
+			}
+			if found {
+				names = append(names, p.Name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// starterConfig returns the contents of a configuration file, to be written
+// to path, enabling presets and suggesting candidates as pair funcs.
+func starterConfig(path string, presets []string, candidates []pairCandidate) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# splinter configuration, written by splinter init.  Each entry sets the\n")
+	fmt.Fprintf(&b, "# flag of the same name, and a list sets a repeated flag once per element:\n")
+	fmt.Fprintf(&b, "#\n")
+	fmt.Fprintf(&b, "#\tsplinter -config %s ./...\n\n", path)
+
+	fmt.Fprintf(&b, "# presets for the libraries the module imports\n")
+	if len(presets) == 0 {
+		fmt.Fprintf(&b, "preset: []\n\n")
+	} else {
+		fmt.Fprintf(&b, "preset:\n")
+		for _, p := range presets {
+			fmt.Fprintf(&b, "  - %s\n", p)
+		}
+		fmt.Fprintf(&b, "\n")
+	}
+
+	fmt.Fprintf(&b, "# more pair funcs, as [pkg[.type]].<func>=<offset>\n")
+	if len(candidates) == 0 {
+		fmt.Fprintf(&b, "pair-func: []\n\n")
+	} else {
+		fmt.Fprintf(&b, "# (suggested by splinter init -scan; review before relying on them)\n")
+		fmt.Fprintf(&b, "pair-func:\n")
+		for _, c := range candidates {
+			fmt.Fprintf(&b, "  - %s=%d  # %d of %d calls look like pairs\n", c.Selector, c.Offset, c.PairCalls, c.Calls)
+		}
+		fmt.Fprintf(&b, "\n")
+	}
+
+	fmt.Fprintf(&b, "# the keys vocabulary; once it has any keys, constant keys that aren't\n")
+	fmt.Fprintf(&b, "# among them are reported\n")
+	fmt.Fprintf(&b, "key: []\n")
+	return b.Bytes()
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/ZipRecruiter/splinter/internal/calls"
+	"github.com/ZipRecruiter/splinter/internal/keys"
+)
+
+// pairCandidate is a variadic func whose calls look like they pass pairs,
+// suggested as a pair func by `splinter init -scan`.
+type pairCandidate struct {
+	Selector string
+	Offset   int
+
+	// Calls counts the calls passing anything in the variadic param, and
+	// PairCalls those passing an even number of args with constant
+	// string keys.
+	Calls, PairCalls int
+}
+
+const (
+	minCandidateCalls = 2
+	minPairCallRatio  = 0.8
+)
+
+// scanPairFuncs returns the funcs taking a final ...interface{} (or ...any)
+// param whose calls in pkgs mostly pass alternating constant string keys and
+// values there, sorted by selector.  Funcs in the standard library, where
+// the likes of fmt.Println can look the same, and in the packages of
+// presets, which configure them already, are skipped.
+func scanPairFuncs(pkgs []*packages.Package, presetPkgs []string) []pairCandidate {
+	found := map[string]*pairCandidate{}
+	seen := map[string]bool{} // calls in both a package and its test variant
+
+	// modules without a dot in their path, like a main module named app,
+	// aren't the standard library
+	var modules []string
+	for _, p := range pkgs {
+		if p.Module != nil {
+			modules = append(modules, p.Module.Path)
+		}
+	}
+
+	for _, p := range pkgs {
+		for _, f := range p.Syntax {
+			ast.Inspect(f, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok || call.Ellipsis.IsValid() {
+					return true
+				}
+				offset, ok := variadicPairs(p.TypesInfo, call)
+				if !ok || len(call.Args) <= offset {
+					return true
+				}
+				callee, ok := calls.Resolve(p.TypesInfo, call)
+				if !ok || callee.Pkg == "" || isStdlib(callee.Pkg) && !underAny(callee.Pkg, modules) || underAny(callee.Pkg, presetPkgs) {
+					return true
+				}
+				posn := p.Fset.Position(call.Pos()).String()
+				if seen[posn] {
+					return true
+				}
+				seen[posn] = true
+
+				sels := callee.Selectors()
+				sel := sels[len(sels)-1].String()
+				c := found[sel]
+				if c == nil {
+					c = &pairCandidate{Selector: sel, Offset: offset}
+					found[sel] = c
+				}
+				c.Calls++
+				if looksLikePairs(p.TypesInfo, call.Args[offset:]) {
+					c.PairCalls++
+				}
+				return true
+			})
+		}
+	}
+
+	var candidates []pairCandidate
+	for _, c := range found {
+		if c.Calls >= minCandidateCalls && float64(c.PairCalls) >= minPairCallRatio*float64(c.Calls) {
+			candidates = append(candidates, *c)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Selector < candidates[j].Selector })
+	return candidates
+}
+
+// variadicPairs returns the index of the final ...interface{} param of the
+// func call calls, if it has one.
+func variadicPairs(info *types.Info, call *ast.CallExpr) (int, bool) {
+	sig, ok := info.TypeOf(call.Fun).(*types.Signature)
+	if !ok || !sig.Variadic() {
+		return 0, false
+	}
+	last := sig.Params().At(sig.Params().Len() - 1)
+	slice, ok := last.Type().(*types.Slice)
+	if !ok {
+		return 0, false
+	}
+	iface, ok := slice.Elem().Underlying().(*types.Interface)
+	if !ok || !iface.Empty() {
+		return 0, false
+	}
+	return sig.Params().Len() - 1, true
+}
+
+// looksLikePairs returns true if args are an even number of alternating
+// constant string keys and values.
+func looksLikePairs(info *types.Info, args []ast.Expr) bool {
+	if len(args)%2 != 0 {
+		return false
+	}
+	for i := 0; i < len(args); i += 2 {
+		if kind, _, _ := keys.Classify(info, args[i]); kind != keys.Constant {
+			return false
+		}
+	}
+	return true
+}
+
+// isStdlib returns true if path is the import path of a standard library
+// package, whose first element has no dot.
+func isStdlib(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+// underAny returns true if path is one of pkgs or within one of them.
+func underAny(path string, pkgs []string) bool {
+	for _, pkg := range pkgs {
+		if path == pkg || strings.HasPrefix(path, pkg+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestScanPairFuncs(t *testing.T) {
+	writeModule(t, map[string]string{
+		"log/log.go": `package log
+
+type Logger struct{}
+
+func (Logger) Info(msg string, kv ...any) {}
+
+func Log(kv ...interface{}) {}
+
+func Mostly(kv ...interface{}) {}
+
+func Printf(format string, args ...interface{}) {}
+
+func Once(kv ...interface{}) {}
+`,
+		"preset/preset.go": `package preset
+
+func Log(kv ...interface{}) {}
+`,
+		"a/a.go": `package a
+
+import (
+	"fmt"
+
+	"m/log"
+	"m/preset"
+)
+
+const key = "k"
+
+func F(l log.Logger, kv []interface{}, n int) {
+	l.Info("msg", "k", 1)
+	l.Info("msg", key, n, "k2", 2)
+
+	log.Log("k", 1)
+	log.Log(key, n)
+	log.Log(kv...) // spreads aren't counted
+
+	log.Mostly("k", 1)
+	log.Mostly("k", 1)
+	log.Mostly("k", 1)
+	log.Mostly("k", 1)
+	log.Mostly("k")
+
+	log.Printf("%d", 1)
+	log.Printf("%s %d", "a", 1)
+
+	log.Once("k", 1)
+
+	preset.Log("k", 1)
+	preset.Log("k", 1)
+
+	fmt.Println("k", 1)
+	fmt.Println("k", 1)
+}
+`,
+		"a/a_test.go": `package a
+
+import "m/log"
+
+func G() {
+	log.Once("k", 1)
+}
+`,
+	})
+
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.LoadAllSyntax | packages.NeedModule, Tests: true}, "./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []pairCandidate{
+		{Selector: "m/log.Log", Offset: 0, Calls: 2, PairCalls: 2},
+		{Selector: "m/log.Logger.Info", Offset: 1, Calls: 2, PairCalls: 2},
+		{Selector: "m/log.Mostly", Offset: 0, Calls: 5, PairCalls: 4},
+		{Selector: "m/log.Once", Offset: 0, Calls: 2, PairCalls: 2},
+	}
+	if d := cmp.Diff(expected, scanPairFuncs(pkgs, []string{"m/preset"})); d != "" {
+		t.Errorf("unexpected candidates (-expected +got):\n%s", d)
+	}
+}
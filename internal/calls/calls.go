@@ -0,0 +1,232 @@
+// Package calls resolves the funcs called by call expressions for the
+// splinter analyzers.
+package calls
+
+import (
+	"errors"
+	"go/ast"
+	"go/types"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Callee describes the func called by a call expression.
+type Callee struct {
+	// Pkg is the import path of the package declaring the func, or of
+	// the method's receiver type.  It is empty for methods of universe
+	// types like error.
+	Pkg string
+
+	// Typ is the name of the receiver type of a method, after
+	// dereferencing pointers, or empty for package funcs.
+	Typ string
+
+	// Fun is the name of the func or method.
+	Fun string
+
+	// Method is true if the callee is a method.
+	Method bool
+
+	// Embedded selects a method promoted through embedded fields by each
+	// of the types it's promoted through, from the type declaring it
+	// outwards, short of the receiver type.
+	Embedded []Selector
+
+	// Name describes the callee in diagnostics.
+	Name string
+}
+
+// Resolve returns the callee of call.  ok is false if the call is not to a
+// package func, to a method with a named receiver or to a value of a named
+// func type, which is resolved like a package func named after the type.
+//
+// Package funcs are resolved through the type checker, so the identifiers
+// used at the call site (import aliases, dot imports) do not matter.
+func Resolve(i *types.Info, call *ast.CallExpr) (c Callee, ok bool) {
+	fun := ast.Unparen(call.Fun)
+	switch f := fun.(type) { // explicitly instantiated generic funcs
+	case *ast.IndexExpr:
+		fun = f.X
+	case *ast.IndexListExpr:
+		fun = f.X
+	}
+
+	// values (or fields) of named func types, like an adapter type
+	// LogFunc func(...interface{}), which are selected by the type's name
+	if tv, ok := i.Types[fun]; ok && tv.IsValue() {
+		if named, ok := tv.Type.(*types.Named); ok && named.Obj().Pkg() != nil {
+			if _, ok := named.Underlying().(*types.Signature); ok {
+				return Callee{Pkg: PkgPath(named.Obj().Pkg()), Fun: named.Obj().Name(), Name: named.Obj().Pkg().Path() + "." + named.Obj().Name()}, true
+			}
+		}
+	}
+
+	if s, ok := fun.(*ast.SelectorExpr); ok {
+		if nv, ok := i.Selections[s]; ok {
+			if nv.Kind() != types.MethodVal {
+				// an unnamed func field or a method expression, neither
+				// of which conform to interfaces, and thus are not
+				// relevant to this
+				return Callee{}, false
+			}
+			return method(nv)
+		}
+	}
+
+	// package functions
+	fn, ok := typeutil.Callee(i, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Type().(*types.Signature).Recv() != nil {
+		return Callee{}, false
+	}
+	return Callee{Pkg: PkgPath(fn.Pkg()), Fun: fn.Name(), Name: fn.Pkg().Path() + "." + fn.Name()}, true
+}
+
+func method(nv *types.Selection) (Callee, bool) {
+	recv := nv.Recv()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	_, typeParam := recv.(*types.TypeParam)
+	if typeParam {
+		// a method of the constraint, selected like a method of the
+		// interface declaring it, or only by the generous selector if
+		// that interface is unnamed, as in [L interface{ Log(...any) }]
+		recv = nv.Obj().(*types.Func).Type().(*types.Signature).Recv().Type()
+		if _, ok := recv.(*types.Named); !ok {
+			return Callee{Fun: nv.Obj().Name(), Method: true, Name: types.SelectionString(nv, nil)}, true
+		}
+	}
+	named, ok := recv.(*types.Named)
+	if !ok {
+		// if there is no receiver (or it's anonymous) it's some
+		// weird thing like an anonymous struct with a func being
+		// called.
+		return Callee{}, false
+	}
+
+	c := Callee{
+		Typ:    named.Obj().Name(),
+		Fun:    nv.Obj().Name(),
+		Method: true,
+		Name:   types.SelectionString(nv, nil),
+	}
+	if pkg := named.Obj().Pkg(); pkg != nil { // nil for universe types like error
+		c.Pkg = PkgPath(pkg)
+	}
+	if path := nv.Index(); len(path) > 1 && !typeParam {
+		c.Embedded = embedded(named, path[:len(path)-1], c.Fun)
+	}
+	return c, true
+}
+
+// embedded returns the selectors for the method fun of the named types of
+// the embedded fields at path, one struct within the next starting at recv,
+// innermost first.
+func embedded(recv *types.Named, path []int, fun string) []Selector {
+	var sels []Selector
+	t := types.Type(recv)
+	for _, i := range path {
+		st, ok := t.Underlying().(*types.Struct)
+		if !ok {
+			break
+		}
+		t = st.Field(i).Type()
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		named, ok := t.(*types.Named)
+		if !ok || named.Obj().Pkg() == nil {
+			break
+		}
+		sels = append([]Selector{{Pkg: PkgPath(named.Obj().Pkg()), Typ: named.Obj().Name(), Fun: fun}}, sels...)
+	}
+	return sels
+}
+
+// PkgPath returns the import path selectors use to refer to pkg.  This is
+// its path, except for external test packages (package foo_test), which
+// use the path of the package they test, so that a selector for an
+// unexported test helper works no matter which kind of test file declares
+// it.
+func PkgPath(pkg *types.Package) string {
+	path := pkg.Path()
+	if strings.HasSuffix(pkg.Name(), "_test") && strings.HasSuffix(path, "_test") {
+		return strings.TrimSuffix(path, "_test")
+	}
+	return path
+}
+
+// Selector identifies funcs the way users configure them: package funcs by
+// Pkg and Fun, methods by Pkg, Typ and Fun, and any method named Fun if Pkg
+// and Typ are empty.
+type Selector struct{ Pkg, Typ, Fun string }
+
+// String returns s in the form parsed by ParseSelector.
+func (s Selector) String() string {
+	if s.Typ != "" {
+		return s.Pkg + "." + s.Typ + "." + s.Fun
+	}
+	return s.Pkg + "." + s.Fun
+}
+
+// Selectors returns the selectors that match c, most generous first.
+//
+// A selector for a package func whose import path ends in an element with
+// a dot, like gopkg.in/yaml.v3.Marshal, parses as a method (of type v3 in
+// gopkg.in/yaml), so that reading is returned too, before the plain one;
+// the type checker knows which the callee is, so only one of them can be
+// meant.
+func (c Callee) Selectors() []Selector {
+	if !c.Method {
+		plain := Selector{Pkg: c.Pkg, Fun: c.Fun}
+		if i := strings.LastIndexByte(c.Pkg, '.'); i > strings.LastIndexByte(c.Pkg, '/') {
+			return []Selector{{Pkg: c.Pkg[:i], Typ: c.Pkg[i+1:], Fun: c.Fun}, plain}
+		}
+		return []Selector{plain}
+	}
+
+	if c.Pkg == "" { // methods on universe types like error
+		return []Selector{{Fun: c.Fun}}
+	}
+
+	sels := []Selector{
+		// try generous interface first
+		{Fun: c.Fun},
+	}
+	// then the types a promoted method is promoted through
+	sels = append(sels, c.Embedded...)
+	// otherwise try concrete type
+	return append(sels, Selector{Pkg: c.Pkg, Typ: c.Typ, Fun: c.Fun})
+}
+
+var offsetMatcher = regexp.MustCompile(`^(?:(.*?)(?:\.([^\./]+))?)?\.([^\.]+)=(\d+)$`)
+
+// ParseOffset parses a selector with an offset, of the form
+// [pkg[.type]].<func>=<offset>.
+func ParseOffset(v string) (Selector, int, error) {
+	m := offsetMatcher.FindStringSubmatch(v)
+	if len(m) != 5 {
+		return Selector{}, 0, errors.New("invalid func offset; should be of form [pkg[.type]].<func>=<offset>")
+	}
+	offset, err := strconv.Atoi(m[4])
+	if err != nil {
+		return Selector{}, 0, err
+	}
+
+	return Selector{Pkg: m[1], Typ: m[2], Fun: m[3]}, offset, nil
+}
+
+var matcher = regexp.MustCompile(`^(?:(.*?)(?:\.([^\./]+))?)?\.([^\.=]+)$`)
+
+// ParseSelector parses a selector of the form [pkg[.type]].<func>.
+func ParseSelector(v string) (Selector, error) {
+	m := matcher.FindStringSubmatch(v)
+	if len(m) != 4 {
+		return Selector{}, errors.New("invalid func; should be of form [pkg[.type]].<func>")
+	}
+
+	return Selector{Pkg: m[1], Typ: m[2], Fun: m[3]}, nil
+}
@@ -0,0 +1,207 @@
+// Package config reads splinter configuration files.  A configuration file
+// is a YAML mapping from flag names to values, which sets the flags the
+// same way the command line would; a list sets a repeated flag once per
+// element:
+//
+//	preset: [slog]
+//	pair-func:
+//	  - example.com/log.Log=0
+//	duplicate-keys: true
+//
+// A file whose name ends in .toml is read as TOML instead, limited to a
+// single table of flag names to strings, booleans, integers and arrays of
+// them:
+//
+//	preset = ["slog"]
+//	pair-func = [
+//	  "example.com/log.Log=0",
+//	]
+//	duplicate-keys = true
+//
+// Settings reads the same mapping from the settings golangci-lint passes a
+// custom linter.
+//
+// Parse applies configuration in layers, presets first, then files, then
+// the rest of the command line, and a value with a leading ! removes what
+// an earlier layer added to a repeated flag that supports it.
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Entry is a single value of a flag in a configuration file.
+type Entry struct {
+	Flag, Value string
+	Comment     string // the line comment, without the #
+	Line        int
+}
+
+// Read parses the configuration file at path into its entries, in the order
+// they appear in the file, without setting any flags.
+func Read(path string) ([]Entry, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if filepath.Ext(path) == ".toml" {
+		return readTOML(path, string(b))
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil // empty file
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s:%d: should be a mapping of flag names to values", path, root.Line)
+	}
+
+	var entries []Entry
+	for i := 0; i < len(root.Content); i += 2 {
+		name, value := root.Content[i], root.Content[i+1]
+
+		var values []*yaml.Node
+		switch value.Kind {
+		case yaml.ScalarNode:
+			values = []*yaml.Node{value}
+		case yaml.SequenceNode:
+			values = value.Content
+		default:
+			return nil, fmt.Errorf("%s:%d: %s should be a value or a list of values", path, value.Line, name.Value)
+		}
+
+		for _, v := range values {
+			if v.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("%s:%d: %s should be a value or a list of values", path, v.Line, name.Value)
+			}
+			entries = append(entries, Entry{
+				Flag:    name.Value,
+				Value:   v.Value,
+				Comment: strings.TrimSpace(strings.TrimPrefix(v.LineComment, "#")),
+				Line:    v.Line,
+			})
+		}
+	}
+	return entries, nil
+}
+
+// Load reads the configuration file at path and sets the flags of fset it
+// names, in the order they appear in the file.
+func Load(path string, fset *flag.FlagSet) error {
+	entries, err := Read(path)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := e.Set(fset); err != nil {
+			return fmt.Errorf("%s:%d: %w", path, e.Line, err)
+		}
+	}
+	return nil
+}
+
+// CommentValue is implemented by flag values that take the line comment of
+// each value in a configuration file along with it, like the expiry of a
+// suppression.
+type CommentValue interface {
+	flag.Value
+	SetWithComment(value, comment string) error
+}
+
+// Set sets the flag of fset named by e to its value.
+func (e Entry) Set(fset *flag.FlagSet) error {
+	f := fset.Lookup(e.Flag)
+	if f == nil {
+		return fmt.Errorf("unknown flag %q", e.Flag)
+	}
+
+	var err error
+	if cv, ok := f.Value.(CommentValue); ok && e.Comment != "" {
+		err = cv.SetWithComment(e.Value, e.Comment)
+	} else {
+		err = fset.Set(e.Flag, e.Value)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value %q for %s: %w", e.Value, e.Flag, err)
+	}
+	return nil
+}
+
+// Flag is a flag.Value that loads each configuration file it's set to into
+// FlagSet, so that flags after it on the command line override the file
+// and flags before it are overridden by it.
+type Flag struct {
+	FlagSet *flag.FlagSet
+	paths   []string
+}
+
+func (f *Flag) Set(path string) error {
+	if err := Load(path, f.FlagSet); err != nil {
+		return err
+	}
+	f.paths = append(f.paths, path)
+	return nil
+}
+
+func (f *Flag) String() string {
+	if f == nil {
+		return ""
+	}
+	return strings.Join(f.paths, ",")
+}
+
+// Settings converts settings, a mapping from flag names to values as
+// decoded from a golangci-lint settings block, into entries: strings,
+// booleans and numbers set the flag to their value, and a list sets a
+// repeated flag once per element.  Since a mapping has no order, presets
+// come first, then the rules version, then the rest by flag name, the way
+// Parse layers a command line.
+func Settings(settings map[string]any) ([]Entry, error) {
+	var names []string
+	for name := range settings {
+		names = append(names, name)
+	}
+	rank := func(name string) int {
+		switch name {
+		case "preset":
+			return 0
+		case "rules-version":
+			return 1
+		}
+		return 2
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if ri, rj := rank(names[i]), rank(names[j]); ri != rj {
+			return ri < rj
+		}
+		return names[i] < names[j]
+	})
+
+	var entries []Entry
+	for _, name := range names {
+		values, ok := settings[name].([]any)
+		if !ok {
+			values = []any{settings[name]}
+		}
+		for _, v := range values {
+			switch v.(type) {
+			case string, bool, int, int64, uint64, float64:
+			default:
+				return nil, fmt.Errorf("settings: %s should be a value or a list of values, not %T", name, v)
+			}
+			entries = append(entries, Entry{Flag: name, Value: fmt.Sprint(v)})
+		}
+	}
+	return entries, nil
+}
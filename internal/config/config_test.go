@@ -0,0 +1,406 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type list []string
+
+func (l *list) Set(v string) error { *l = append(*l, v); return nil }
+func (l *list) String() string     { return strings.Join(*l, ",") }
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name, src string
+		funcs     []string
+		dup       bool
+		err       string
+	}{
+		{"empty", "", nil, false, ""},
+		{"values", "pair-func:\n  - .Log=0\n  - .Info=1\nduplicate-keys: true\n", []string{".Log=0", ".Info=1"}, true, ""},
+		{"scalar", "pair-func: .Log=0\n", []string{".Log=0"}, false, ""},
+		{"empty list", "pair-func: []\n", nil, false, ""},
+		{"unknown", "pair-func: .Log=0\nnope: 1\n", nil, false, `:2: unknown flag "nope"`},
+		{"mapping", "pair-func:\n  a: b\n", nil, false, ":2: pair-func should be a value or a list of values"},
+		{"invalid", "duplicate-keys: maybe\n", nil, false, `:1: invalid value "maybe" for duplicate-keys`},
+		{"not a mapping", "- .Log=0\n", nil, false, ":1: should be a mapping of flag names to values"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "splinter.yaml")
+			if err := os.WriteFile(path, []byte(test.src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			var funcs list
+			fset := flag.NewFlagSet("test", flag.ContinueOnError)
+			fset.Var(&funcs, "pair-func", "")
+			dup := fset.Bool("duplicate-keys", false, "")
+
+			err := Load(path, fset)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff([]string(test.funcs), []string(funcs)); d != "" {
+				t.Errorf("unexpected pair funcs (-expected +got):\n%s", d)
+			}
+			if *dup != test.dup {
+				t.Errorf("duplicate-keys = %v; expected %v", *dup, test.dup)
+			}
+		})
+	}
+}
+
+func TestFlagOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "splinter.yaml")
+	if err := os.WriteFile(path, []byte("c: 2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	c := fset.Int("c", 0, "")
+	fset.Var(&Flag{FlagSet: fset}, "config", "")
+
+	// flags after -config override it, and flags before are overridden
+	if err := fset.Parse([]string{"-c", "1", "-config", path}); err != nil {
+		t.Fatal(err)
+	}
+	if *c != 2 {
+		t.Errorf("c = %d; expected the config's 2", *c)
+	}
+	if err := fset.Parse([]string{"-config", path, "-c", "3"}); err != nil {
+		t.Fatal(err)
+	}
+	if *c != 3 {
+		t.Errorf("c = %d; expected the flag's 3", *c)
+	}
+}
+
+type commented []string
+
+func (c *commented) Set(v string) error { return c.SetWithComment(v, "") }
+func (c *commented) String() string     { return strings.Join(*c, ",") }
+
+func (c *commented) SetWithComment(v, comment string) error {
+	*c = append(*c, v+"|"+comment)
+	return nil
+}
+
+func TestCommentValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "splinter.yaml")
+	src := "suppress:\n  - a.go:1  # expires 2026-01-01\n  - a.go:2\nsingle: b.go:3 # why\n"
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var suppress, single commented
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	fset.Var(&suppress, "suppress", "")
+	fset.Var(&single, "single", "")
+	if err := Load(path, fset); err != nil {
+		t.Fatal(err)
+	}
+
+	if d := cmp.Diff(commented{"a.go:1|expires 2026-01-01", "a.go:2|"}, suppress); d != "" {
+		t.Errorf("unexpected values (-expected +got):\n%s", d)
+	}
+	if d := cmp.Diff(commented{"b.go:3|why"}, single); d != "" {
+		t.Errorf("unexpected values (-expected +got):\n%s", d)
+	}
+}
+
+func TestRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "splinter.yaml")
+	src := "pair-func:\n  - .Log=0\n  - .Info=1  # the info logger\nduplicate-keys: true\n"
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Entry{
+		{Flag: "pair-func", Value: ".Log=0", Line: 2},
+		{Flag: "pair-func", Value: ".Info=1", Comment: "the info logger", Line: 3},
+		{Flag: "duplicate-keys", Value: "true", Line: 4},
+	}
+	if d := cmp.Diff(expected, entries); d != "" {
+		t.Errorf("unexpected entries (-expected +got):\n%s", d)
+	}
+}
+
+func TestReadTOML(t *testing.T) {
+	tests := []struct {
+		name, src string
+		entries   []Entry
+		err       string
+	}{
+		{"empty", "# nothing\n", nil, ""},
+		{"values", "pair-func = [\n  \".Log=0\",\n  '.Info=1', # the info logger\n]\nduplicate-keys = true\n", []Entry{
+			{Flag: "pair-func", Value: ".Log=0", Line: 2},
+			{Flag: "pair-func", Value: ".Info=1", Comment: "the info logger", Line: 3},
+			{Flag: "duplicate-keys", Value: "true", Line: 5},
+		}, ""},
+		{"inline", "\"pair-func\" = [\".Log=0\", \".Info=1\"]  # both\nmax = 1_000\n", []Entry{
+			{Flag: "pair-func", Value: ".Log=0", Line: 1},
+			{Flag: "pair-func", Value: ".Info=1", Line: 1},
+			{Flag: "max", Value: "1000", Line: 2},
+		}, ""},
+		{"escapes", "key-pattern = \"^[a-z_\\\\.]+$\"\n", []Entry{
+			{Flag: "key-pattern", Value: `^[a-z_\.]+$`, Line: 1},
+		}, ""},
+		{"table", "[pairs]\nduplicate-keys = true\n", nil, ":1: tables aren't supported"},
+		{"bare", "pair-func = .Log=0\n", nil, ":1: pair-func should be a string, boolean, integer or an array of them"},
+		{"unterminated", "\n\npair-func = \".Log=0\n", nil, ":3: unterminated string"},
+		{"unclosed", "pair-func = [\".Log=0\"\n", nil, ":2: expected , or ] in pair-func"},
+		{"two", "a = 1 b = 2\n", nil, ":1: expected a new line after a"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "splinter.toml")
+			if err := os.WriteFile(path, []byte(test.src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			entries, err := Read(path)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(test.entries, entries); d != "" {
+				t.Errorf("unexpected entries (-expected +got):\n%s", d)
+			}
+		})
+	}
+}
+
+// logged logs the values of every flag it's registered as, with their names
+// and the layer they were set in.
+type logged struct {
+	name  string
+	layer Layer
+	log   *[]string
+}
+
+func (l *logged) Set(v string) error {
+	*l.log = append(*l.log, l.layer.String()+" "+l.name+"="+v)
+	return nil
+}
+func (l *logged) String() string       { return "" }
+func (l *logged) SetLayer(layer Layer) { l.layer = layer }
+
+func TestParse(t *testing.T) {
+	dir := t.TempDir()
+	repo := filepath.Join(dir, "repo.yaml")
+	if err := os.WriteFile(repo, []byte("pair-func: [.Log=0, .Info=1]\npreset: slog\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	team := filepath.Join(dir, "team.toml")
+	if err := os.WriteFile(team, []byte("pair-func = [\"!.Info\"]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var log []string
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	for _, name := range []string{"pair-func", "preset"} {
+		fset.Var(&logged{name: name, log: &log}, name, "")
+	}
+	cf := &Flag{FlagSet: fset}
+	fset.Var(cf, "config", "")
+
+	args := []string{"-pair-func", ".Warn=0", "-config", repo, "-preset", "zap", "-config", team, "./..."}
+	if err := Parse(fset, args); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"preset preset=slog",
+		"preset preset=zap",
+		"repo pair-func=.Log=0",
+		"repo pair-func=.Info=1",
+		"repo pair-func=!.Info",
+		"command line pair-func=.Warn=0",
+	}
+	if d := cmp.Diff(expected, log); d != "" {
+		t.Errorf("unexpected order (-expected +got):\n%s", d)
+	}
+	if d := cmp.Diff([]string{"./..."}, fset.Args()); d != "" {
+		t.Errorf("unexpected args (-expected +got):\n%s", d)
+	}
+	if got := cf.String(); got != repo+","+team {
+		t.Errorf("config = %q; expected both files", got)
+	}
+}
+
+func TestParseModuleFile(t *testing.T) {
+	dir := t.TempDir()
+	for path, src := range map[string]string{
+		"go.mod":              "module m\n\n// splinter:config=tools/splinter.yaml\n",
+		"tools/splinter.yaml": "pair-func: .Debug=0\npreset: zerolog\n",
+		"repo.yaml":           "pair-func: [.Log=0]\n",
+	} {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(filepath.Join(dir, "tools")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{
+			name: "after -config",
+			args: []string{"-pair-func", ".Warn=0", "-config", "../repo.yaml"},
+			expected: []string{
+				"preset preset=zerolog",
+				"repo pair-func=.Log=0",
+				"repo pair-func=.Debug=0",
+				"command line pair-func=.Warn=0",
+			},
+		},
+		{
+			name: "given by -config",
+			args: []string{"-config", "splinter.yaml"},
+			expected: []string{
+				"preset preset=zerolog",
+				"repo pair-func=.Debug=0",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var log []string
+			fset := flag.NewFlagSet("test", flag.ContinueOnError)
+			for _, name := range []string{"pair-func", "preset"} {
+				fset.Var(&logged{name: name, log: &log}, name, "")
+			}
+			fset.Var(&Flag{FlagSet: fset}, "config", "")
+
+			if err := Parse(fset, test.args); err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(test.expected, log); d != "" {
+				t.Errorf("unexpected order (-expected +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestModuleFile(t *testing.T) {
+	dir := t.TempDir()
+	for path, src := range map[string]string{
+		"a/go.mod":              "module a\n\n// splinter:config=tools/splinter.yaml\n",
+		"a/tools/splinter.yaml": "",
+		"a/sub/x.go":            "package sub\n",
+		"b/go.mod":              "module b\n",
+		"c/go.mod":              "module c\n\n//splinter:config=missing.yaml\n",
+		"d/x.go":                "package d\n",
+	} {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		dir, expected, err string
+	}{
+		{"a/sub", "a/tools/splinter.yaml", ""},
+		{"b", "", ""},
+		{"c", "", "splinter:config"},
+		{"d", "", ""},
+	}
+	for _, test := range tests {
+		path, err := ModuleFile(filepath.Join(dir, test.dir))
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: expected error containing %q, got %v", test.dir, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", test.dir, err)
+		}
+		expected := test.expected
+		if expected != "" {
+			expected = filepath.Join(dir, expected)
+		}
+		if path != expected {
+			t.Errorf("%s: expected %q, got %q", test.dir, expected, path)
+		}
+	}
+}
+
+func TestRemoval(t *testing.T) {
+	if v, ok := Removal("!.Log=0"); !ok || v != ".Log=0" {
+		t.Errorf("Removal(\"!.Log=0\") = %q, %t; expected .Log=0, true", v, ok)
+	}
+	if v, ok := Removal(".Log=0"); ok || v != ".Log=0" {
+		t.Errorf("Removal(\".Log=0\") = %q, %t; expected .Log=0, false", v, ok)
+	}
+}
+
+func TestSettings(t *testing.T) {
+	entries, err := Settings(map[string]any{
+		"pair-func":      []any{".Log=0", ".Info=1"},
+		"duplicate-keys": true,
+		"rules-version":  2,
+		"preset":         "slog",
+		"c":              3.0,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Entry{
+		{Flag: "preset", Value: "slog"},
+		{Flag: "rules-version", Value: "2"},
+		{Flag: "c", Value: "3"},
+		{Flag: "duplicate-keys", Value: "true"},
+		{Flag: "pair-func", Value: ".Log=0"},
+		{Flag: "pair-func", Value: ".Info=1"},
+	}
+	if d := cmp.Diff(expected, entries); d != "" {
+		t.Errorf("unexpected entries (-expected +got):\n%s", d)
+	}
+
+	if _, err := Settings(map[string]any{"pair-func": []any{[]any{".Log=0"}}}); err == nil {
+		t.Error("expected an error for a nested list")
+	}
+}
@@ -0,0 +1,214 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Layer is a source of configuration.  Layers are applied in increasing
+// order, so a later one overrides, or removes the entries of, an earlier
+// one.
+type Layer int
+
+const (
+	// PresetLayer is the presets, whether named on the command line or
+	// in a configuration file.
+	PresetLayer Layer = iota
+
+	// RepoLayer is the -config files, in order, and the file a go.mod
+	// references.
+	RepoLayer
+
+	// DirectoryLayer is the configuration files discovered in the
+	// directories of the packages analyzed.
+	DirectoryLayer
+
+	// CommandLineLayer is the rest of the flags on the command line.
+	CommandLineLayer
+)
+
+func (l Layer) String() string {
+	switch l {
+	case PresetLayer:
+		return "preset"
+	case RepoLayer:
+		return "repo"
+	case DirectoryLayer:
+		return "directory"
+	case CommandLineLayer:
+		return "command line"
+	}
+	return fmt.Sprintf("Layer(%d)", int(l))
+}
+
+// Layered is implemented by flag values that need to know the layer the
+// values they're set to come from, like those that directory configuration
+// applied later must not override the command line of.
+type Layered interface {
+	flag.Value
+	SetLayer(Layer)
+}
+
+// Removal returns v without its leading !, and true, if it removes an entry
+// an earlier layer added to a repeated flag rather than adding one, as in
+// -pair-func '!example.com/log.Log'.
+func Removal(v string) (string, bool) {
+	if rest, ok := strings.CutPrefix(v, "!"); ok {
+		return rest, true
+	}
+	return v, false
+}
+
+// Parse parses args, the command line, into fset, applying the layers of
+// configuration in order: first every preset, then the files given by
+// -config, in order, and the file the go.mod of the working directory
+// references, and finally the rest of the flags on the command line,
+// wherever -config and -preset appear among them.  Errors are handled per
+// the error handling of fset.
+func Parse(fset *flag.FlagSet, args []string) error {
+	// record the command line without setting anything yet
+	var cmdline []Entry
+	rec := flag.NewFlagSet(fset.Name(), fset.ErrorHandling())
+	rec.SetOutput(fset.Output())
+	rec.Usage = fset.Usage
+	fset.VisitAll(func(f *flag.Flag) {
+		rec.Var(&recorder{Flag: f, entries: &cmdline}, f.Name, f.Usage)
+	})
+	if err := rec.Parse(args); err != nil {
+		return err
+	}
+
+	type fileEntry struct {
+		Entry
+		path string // of the file it's from, if any
+	}
+	var files []string
+	var presets, repo, rest []fileEntry
+	readFile := func(path string) error {
+		entries, err := Read(path)
+		if err != nil {
+			return err
+		}
+		for _, fe := range entries {
+			fe := fileEntry{fe, path}
+			if fe.Flag == "preset" {
+				presets = append(presets, fe)
+			} else {
+				repo = append(repo, fe)
+			}
+		}
+		return nil
+	}
+	for _, e := range cmdline {
+		switch e.Flag {
+		case "config":
+			if err := readFile(e.Value); err != nil {
+				return handle(fset, err)
+			}
+			files = append(files, e.Value)
+		case "preset":
+			presets = append(presets, fileEntry{Entry: e})
+		default:
+			rest = append(rest, fileEntry{Entry: e})
+		}
+	}
+
+	// the file the go.mod of the working directory references follows the
+	// -config files, unless it's one of them
+	path, err := ModuleFile(".")
+	if err != nil {
+		return handle(fset, err)
+	}
+	if path != "" && !slices.ContainsFunc(files, func(f string) bool { return sameFile(f, path) }) {
+		if err := readFile(path); err != nil {
+			return handle(fset, err)
+		}
+	}
+
+	for _, layer := range []struct {
+		Layer
+		entries []fileEntry
+	}{
+		{PresetLayer, presets},
+		{RepoLayer, repo},
+		{CommandLineLayer, rest},
+	} {
+		setLayer(fset, layer.Layer)
+		for _, e := range layer.entries {
+			if err := e.Set(fset); err != nil {
+				if e.path != "" {
+					err = fmt.Errorf("%s:%d: %w", e.path, e.Line, err)
+				}
+				return handle(fset, err)
+			}
+		}
+	}
+
+	if f := fset.Lookup("config"); f != nil {
+		if cf, ok := f.Value.(*Flag); ok {
+			cf.paths = append(cf.paths, files...)
+		}
+	}
+	return fset.Parse(append([]string{"--"}, rec.Args()...))
+}
+
+// sameFile returns true if paths a and b name the same file.
+func sameFile(a, b string) bool {
+	ai, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bi, err := os.Stat(b)
+	return err == nil && os.SameFile(ai, bi)
+}
+
+// setLayer tells the Layered flags of fset the layer of the values they're
+// set to next.
+func setLayer(fset *flag.FlagSet, layer Layer) {
+	fset.VisitAll(func(f *flag.Flag) {
+		if l, ok := f.Value.(Layered); ok {
+			l.SetLayer(layer)
+		}
+	})
+}
+
+// handle handles err, from applying the configuration, per the error
+// handling of fset, the way fset.Parse would.
+func handle(fset *flag.FlagSet, err error) error {
+	fmt.Fprintln(fset.Output(), err)
+	switch fset.ErrorHandling() {
+	case flag.ExitOnError:
+		os.Exit(2)
+	case flag.PanicOnError:
+		panic(err)
+	}
+	return err
+}
+
+// recorder is a flag.Value recording the values its flag is set to on the
+// command line as entries, in order, without setting it.
+type recorder struct {
+	*flag.Flag
+	entries *[]Entry
+}
+
+func (r *recorder) Set(v string) error {
+	*r.entries = append(*r.entries, Entry{Flag: r.Name, Value: v})
+	return nil
+}
+
+func (r *recorder) String() string {
+	if r == nil || r.Flag == nil {
+		return ""
+	}
+	return r.Value.String()
+}
+
+// IsBoolFlag lets boolean flags be given without a value, as in -fix.
+func (r *recorder) IsBoolFlag() bool {
+	b, ok := r.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
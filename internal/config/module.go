@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// goModConfig matches the comment in a go.mod file referencing the
+// configuration file of the module, relative to the module's root.
+var goModConfig = regexp.MustCompile(`(?m)^\s*//\s*splinter:config=(\S+)\s*$`)
+
+// ModuleRoot returns the root of the module containing dir, the nearest
+// directory at or above it with a go.mod file.
+func ModuleRoot(dir string) (string, bool) {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// Referenced returns the path of the configuration file the go.mod file at
+// root references with a splinter:config comment, or "" if it doesn't.
+func Referenced(root string) (string, error) {
+	gomod := filepath.Join(root, "go.mod")
+	b, err := os.ReadFile(gomod)
+	if err != nil {
+		return "", err
+	}
+
+	m := goModConfig.FindSubmatch(b)
+	if m == nil {
+		return "", nil
+	}
+	path := filepath.FromSlash(string(m[1]))
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("%s: splinter:config: %w", gomod, err)
+	}
+	return path, nil
+}
+
+// ModuleFile returns the path of the configuration file referenced by the
+// go.mod file of the module containing dir, or "" if there's none.
+func ModuleFile(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	root, ok := ModuleRoot(dir)
+	if !ok {
+		return "", nil
+	}
+	return Referenced(root)
+}
@@ -0,0 +1,198 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// tomlScalar matches the unquoted values allowed in a TOML configuration
+// file: booleans and integers.
+var tomlScalar = regexp.MustCompile(`^(?:true|false|[-+]?\d[\d_]*)$`)
+
+// tomlParser parses the subset of TOML a configuration file needs: a single
+// table of bare or quoted keys to strings, booleans, integers and arrays of
+// them, with comments.
+type tomlParser struct {
+	path string
+	src  string
+	pos  int
+	line int
+}
+
+// readTOML parses the TOML configuration file at path, with contents src,
+// into its entries, in the order they appear in the file.
+func readTOML(path, src string) ([]Entry, error) {
+	t := &tomlParser{path: path, src: src, line: 1}
+
+	var entries []Entry
+	for {
+		t.skip(true)
+		if t.pos == len(t.src) {
+			return entries, nil
+		}
+		if t.src[t.pos] == '[' {
+			return nil, t.errorf("tables aren't supported; flags should be top-level keys")
+		}
+
+		name, err := t.key()
+		if err != nil {
+			return nil, err
+		}
+		t.skip(false)
+		if !t.consume('=') {
+			return nil, t.errorf("expected = after %s", name)
+		}
+		t.skip(false)
+
+		if t.consume('[') {
+			for {
+				t.skip(true)
+				if t.consume(']') {
+					break
+				}
+				e, err := t.value(name)
+				if err != nil {
+					return nil, err
+				}
+				t.skip(false)
+				more := t.consume(',')
+				e.Comment = t.comment()
+				entries = append(entries, e)
+				if !more {
+					t.skip(true)
+					if !t.consume(']') {
+						return nil, t.errorf("expected , or ] in %s", name)
+					}
+					break
+				}
+			}
+			t.comment()
+		} else {
+			e, err := t.value(name)
+			if err != nil {
+				return nil, err
+			}
+			e.Comment = t.comment()
+			entries = append(entries, e)
+		}
+
+		if t.pos != len(t.src) && !t.consume('\n') {
+			return nil, t.errorf("expected a new line after %s", name)
+		}
+		t.line++
+	}
+}
+
+func (t *tomlParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%s:%d: %s", t.path, t.line, fmt.Sprintf(format, args...))
+}
+
+func (t *tomlParser) consume(c byte) bool {
+	if t.pos < len(t.src) && t.src[t.pos] == c {
+		t.pos++
+		return true
+	}
+	return false
+}
+
+// skip skips spaces, and if lines is true new lines and comments too.
+func (t *tomlParser) skip(lines bool) {
+	for t.pos < len(t.src) {
+		switch c := t.src[t.pos]; {
+		case c == ' ' || c == '\t' || c == '\r':
+			t.pos++
+		case lines && c == '\n':
+			t.pos++
+			t.line++
+		case lines && c == '#':
+			t.comment()
+		default:
+			return
+		}
+	}
+}
+
+// comment returns the comment at the end of the current line, without the
+// #, if there is one, leaving the new line.
+func (t *tomlParser) comment() string {
+	t.skip(false)
+	if !t.consume('#') {
+		return ""
+	}
+	end := strings.IndexByte(t.src[t.pos:], '\n')
+	if end < 0 {
+		end = len(t.src) - t.pos
+	}
+	c := t.src[t.pos : t.pos+end]
+	t.pos += end
+	return strings.TrimSpace(c)
+}
+
+func (t *tomlParser) key() (string, error) {
+	if t.src[t.pos] == '"' || t.src[t.pos] == '\'' {
+		return t.quoted()
+	}
+	start := t.pos
+	for t.pos < len(t.src) && isBareKey(t.src[t.pos]) {
+		t.pos++
+	}
+	if t.pos == start {
+		return "", t.errorf("expected a key")
+	}
+	return t.src[start:t.pos], nil
+}
+
+func isBareKey(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_'
+}
+
+// value parses a string, boolean or integer as an entry of the flag name.
+func (t *tomlParser) value(name string) (Entry, error) {
+	e := Entry{Flag: name, Line: t.line}
+	if t.pos == len(t.src) {
+		return e, t.errorf("expected a value for %s", name)
+	}
+	if c := t.src[t.pos]; c == '"' || c == '\'' {
+		v, err := t.quoted()
+		e.Value = v
+		return e, err
+	}
+
+	start := t.pos
+	for t.pos < len(t.src) && !strings.ContainsRune(" \t\r\n,]#", rune(t.src[t.pos])) {
+		t.pos++
+	}
+	v := t.src[start:t.pos]
+	if !tomlScalar.MatchString(v) {
+		return e, t.errorf("%s should be a string, boolean, integer or an array of them", name)
+	}
+	e.Value = strings.ReplaceAll(v, "_", "")
+	return e, nil
+}
+
+// quoted parses a basic ("...") or literal ('...') string on one line.
+func (t *tomlParser) quoted() (string, error) {
+	q := t.src[t.pos]
+	end := t.pos + 1
+	for ; end < len(t.src) && t.src[end] != q && t.src[end] != '\n'; end++ {
+		if q == '"' && t.src[end] == '\\' {
+			end++
+		}
+	}
+	if end >= len(t.src) || t.src[end] != q {
+		return "", t.errorf("unterminated string")
+	}
+	s := t.src[t.pos : end+1]
+	t.pos = end + 1
+
+	if q == '\'' {
+		return s[1 : len(s)-1], nil
+	}
+	v, err := strconv.Unquote(s)
+	if err != nil {
+		return "", t.errorf("invalid string %s", s)
+	}
+	return v, nil
+}
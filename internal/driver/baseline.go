@@ -0,0 +1,158 @@
+package driver
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// baselineVersion is the version of the baseline file format.
+const baselineVersion = 1
+
+// BaselineEntry is a diagnostic recorded in a Baseline, by fingerprint.
+// File, Rule and Message are for people reading the file; only the
+// fingerprint and count are matched.
+type BaselineEntry struct {
+	Fingerprint string `json:"fingerprint"`
+	File        string `json:"file"`
+	Rule        string `json:"rule"`
+	Message     string `json:"message"`
+	Count       int    `json:"count,omitempty"`
+}
+
+// Baseline is a record of the diagnostics of a run, so that a later run
+// can drop the ones it already had and report only new ones.  Diagnostics
+// are matched by fingerprints of their file, analyzer, rule, message and
+// the text of the line they're on, rather than by position, so that edits
+// elsewhere in a file don't invalidate them; a diagnostic is dropped as
+// many times as it was recorded, so a copy of a recorded call is still
+// reported.
+type Baseline struct {
+	Version     int             `json:"version"`
+	Diagnostics []BaselineEntry `json:"diagnostics"`
+
+	// Dir is the directory that the files of diagnostics are made
+	// relative to before they're fingerprinted.
+	Dir string `json:"-"`
+}
+
+// lineRefMatcher matches the line numbers within messages, which change
+// with edits elsewhere in a file.
+var lineRefMatcher = regexp.MustCompile(`(\bline |\.go:)\d+`)
+
+// fingerprints fingerprints diagnostics, reading each file once.
+type fingerprints struct {
+	dir   string
+	files map[string][][]byte
+}
+
+// file returns the path of the file of d relative to the dir, if it's
+// within it, with forward slashes.
+func (f *fingerprints) file(d Diagnostic) string {
+	name := d.Position().Filename
+	if rel, err := filepath.Rel(f.dir, name); err == nil && !strings.HasPrefix(rel, "..") {
+		name = rel
+	}
+	return filepath.ToSlash(name)
+}
+
+func (f *fingerprints) of(d Diagnostic) string {
+	posn := d.Position()
+	lines, ok := f.files[posn.Filename]
+	if !ok {
+		src, _ := os.ReadFile(posn.Filename)
+		lines = bytes.Split(src, []byte("\n"))
+		f.files[posn.Filename] = lines
+	}
+	var text []byte
+	if posn.Line >= 1 && posn.Line <= len(lines) {
+		text = bytes.TrimSpace(lines[posn.Line-1])
+	}
+
+	h := sha256.New()
+	for _, s := range []string{f.file(d), d.Analyzer.Name, d.Category, lineRefMatcher.ReplaceAllString(d.Message, "${1}N"), string(text)} {
+		fmt.Fprintf(h, "%q\n", s)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// NewBaseline returns a Baseline of diags, with their files relative to
+// dir.
+func NewBaseline(diags []Diagnostic, dir string) *Baseline {
+	f := &fingerprints{dir: dir, files: map[string][][]byte{}}
+	b := &Baseline{Version: baselineVersion, Diagnostics: []BaselineEntry{}, Dir: dir}
+	index := map[string]int{}
+	for _, d := range diags {
+		fp := f.of(d)
+		if i, ok := index[fp]; ok {
+			b.Diagnostics[i].Count++
+			continue
+		}
+		index[fp] = len(b.Diagnostics)
+		b.Diagnostics = append(b.Diagnostics, BaselineEntry{Fingerprint: fp, File: f.file(d), Rule: d.Category, Message: d.Message, Count: 1})
+	}
+	sort.SliceStable(b.Diagnostics, func(i, j int) bool {
+		ei, ej := b.Diagnostics[i], b.Diagnostics[j]
+		if ei.File != ej.File {
+			return ei.File < ej.File
+		}
+		return ei.Fingerprint < ej.Fingerprint
+	})
+	return b
+}
+
+// ReadBaseline reads the baseline at path, whose diagnostics' files are
+// relative to dir.
+func ReadBaseline(path, dir string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	b := &Baseline{}
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if b.Version != baselineVersion {
+		return nil, fmt.Errorf("%s: unsupported baseline version %d; should be %d", path, b.Version, baselineVersion)
+	}
+	b.Dir = dir
+	return b, nil
+}
+
+// Write writes b as indented JSON.
+func (b *Baseline) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(b)
+}
+
+// Filter drops the diags recorded in b, each as many times as it was
+// recorded, and returns the rest.
+func (b *Baseline) Filter(diags []Diagnostic) []Diagnostic {
+	if b == nil || len(b.Diagnostics) == 0 {
+		return diags
+	}
+
+	left := map[string]int{}
+	for _, e := range b.Diagnostics {
+		left[e.Fingerprint] += max(e.Count, 1)
+	}
+	f := &fingerprints{dir: b.Dir, files: map[string][][]byte{}}
+	var kept []Diagnostic
+	for _, d := range diags {
+		if fp := f.of(d); left[fp] > 0 {
+			left[fp]--
+			continue
+		}
+		kept = append(kept, d)
+	}
+	return kept
+}
@@ -0,0 +1,115 @@
+package driver
+
+import (
+	"bytes"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestBaseline(t *testing.T) {
+	before := `package app
+
+func F() {
+	log.Log("a")
+	log.Log("a")
+	log.Log("b", 1, "b", 2)
+}
+`
+	// a line inserted above every call, another copy of a recorded
+	// call, and a new one
+	after := `package app
+
+import "example.com/log"
+
+func F() {
+	log.Log("a")
+	log.Log("a")
+	log.Log("a")
+	log.Log("b", 1, "b", 2)
+	log.Log("c")
+}
+`
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app", "app.go")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	pairs := &analysis.Analyzer{Name: "pairs"}
+	diags := func(src string, reports map[int]string) []Diagnostic {
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		fset := token.NewFileSet()
+		f := fset.AddFile(path, -1, len(src))
+		f.SetLinesForContent([]byte(src))
+		var diags []Diagnostic
+		for line := 1; line <= f.LineCount(); line++ {
+			if msg, ok := reports[line]; ok {
+				category, message, _ := strings.Cut(msg, ": ")
+				diags = append(diags, Diagnostic{
+					Diagnostic: analysis.Diagnostic{Pos: f.LineStart(line) + 1, Category: category, Message: message},
+					Analyzer:   pairs,
+					Fset:       fset,
+					PkgPath:    "example.com/app",
+					Severity:   Error,
+				})
+			}
+		}
+		return diags
+	}
+
+	recorded := diags(before, map[int]string{
+		4: "odd-arity: 1 arg passed to log.Log; must be even",
+		5: "odd-arity: 1 arg passed to log.Log; must be even",
+		6: `repeated-key: key "b" (arg 2 to log.Log) was already passed on line 6`,
+	})
+	var buf bytes.Buffer
+	if err := NewBaseline(recorded, dir).Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	baselinePath := filepath.Join(dir, "baseline.json")
+	if err := os.WriteFile(baselinePath, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ReadBaseline(baselinePath, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var files []string
+	for _, e := range b.Diagnostics {
+		files = append(files, e.File)
+	}
+	if d := cmp.Diff([]string{"app/app.go", "app/app.go"}, files); d != "" {
+		t.Errorf("unexpected files (-expected +got):\n%s", d)
+	}
+
+	current := diags(after, map[int]string{
+		6:  "odd-arity: 1 arg passed to log.Log; must be even",
+		7:  "odd-arity: 1 arg passed to log.Log; must be even",
+		8:  "odd-arity: 1 arg passed to log.Log; must be even",
+		9:  `repeated-key: key "b" (arg 2 to log.Log) was already passed on line 9`,
+		10: "odd-arity: 1 arg passed to log.Log; must be even",
+	})
+	var lines []int
+	for _, d := range b.Filter(current) {
+		lines = append(lines, d.Position().Line)
+	}
+	if d := cmp.Diff([]int{8, 10}, lines); d != "" {
+		t.Errorf("unexpected lines (-expected +got):\n%s", d)
+	}
+
+	if err := os.WriteFile(baselinePath, []byte(`{"version": 2, "diagnostics": []}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadBaseline(baselinePath, dir); err == nil {
+		t.Error("expected an error reading a baseline of another version")
+	}
+}
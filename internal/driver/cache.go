@@ -0,0 +1,314 @@
+package driver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+// Cache is an on-disk cache of the diagnostics of packages, so that
+// repeated runs only analyze the packages that changed, or whose
+// dependencies changed, since the last run.
+type Cache struct {
+	Dir string
+
+	// Salt is mixed into every key; it should identify the analyzers and
+	// their configuration, so that changing either invalidates the cache.
+	Salt string
+
+	// Files, if set, returns the files besides its Go files that the
+	// diagnostics of a package depend on, like the configuration files
+	// discovered for it; their contents are mixed into its key, and so is
+	// the absence of those that don't exist.
+	Files func(*packages.Package) ([]string, error)
+}
+
+// cachedPos is a position in a file, by offset.
+type cachedPos struct {
+	File   string
+	Offset int
+}
+
+type cachedEdit struct {
+	Pos, End cachedPos
+	New      string
+}
+
+type cachedFix struct {
+	Message string
+	Edits   []cachedEdit
+}
+
+type cachedRelated struct {
+	Pos, End cachedPos
+	Message  string
+}
+
+type cachedDiagnostic struct {
+	Analyzer string
+	Pos, End cachedPos
+	Category string
+	Message  string
+	URL      string
+	Fixes    []cachedFix
+	Related  []cachedRelated
+}
+
+// Analyze analyzes pkgs like checker.Analyze followed by Diagnostics, but
+// reads the diagnostics of packages whose key is in the cache instead of
+// analyzing them, and writes the diagnostics of the rest to the cache.
+func (c *Cache) Analyze(analyzers []*analysis.Analyzer, pkgs []*packages.Package) ([]Diagnostic, error) {
+	keys := map[*packages.Package]string{}
+	files := map[string]*token.File{}
+	fsets := map[*token.FileSet]bool{}
+
+	var diags []Diagnostic
+	var misses []*packages.Package
+	for _, p := range pkgs {
+		key, err := c.key(p, keys)
+		if err != nil {
+			return nil, err
+		}
+		if !fsets[p.Fset] {
+			fsets[p.Fset] = true
+			p.Fset.Iterate(func(f *token.File) bool {
+				files[f.Name()] = f
+				return true
+			})
+		}
+
+		cached, ok := c.read(key, p, analyzers, files)
+		if !ok {
+			misses = append(misses, p)
+			continue
+		}
+		diags = append(diags, cached...)
+	}
+	if len(misses) == 0 {
+		return dedupe(diags), nil
+	}
+
+	graph, err := checker.Analyze(analyzers, misses, nil)
+	if err != nil {
+		return nil, err
+	}
+	analyzed := map[*packages.Package][]Diagnostic{}
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			return nil, act.Err
+		}
+		analyzed[act.Package] = append(analyzed[act.Package], actionDiagnostics(act)...)
+	}
+	for _, p := range misses {
+		if err := c.write(keys[p], analyzed[p]); err != nil {
+			return nil, err
+		}
+		diags = append(diags, analyzed[p]...)
+	}
+	return dedupe(diags), nil
+}
+
+// key returns the cache key of p, a hash of its files and the keys of its
+// imports; keys memoizes the keys of the packages hashed so far.
+func (c *Cache) key(p *packages.Package, keys map[*packages.Package]string) (string, error) {
+	if key, ok := keys[p]; ok {
+		return key, nil
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "salt %q\nid %q\n", c.Salt, p.ID)
+	for _, name := range p.CompiledGoFiles {
+		sum, err := fileHash(name)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "file %q %x\n", name, sum)
+	}
+	if c.Files != nil {
+		names, err := c.Files(p)
+		if err != nil {
+			return "", err
+		}
+		for _, name := range names {
+			sum, err := fileHash(name)
+			if errors.Is(err, fs.ErrNotExist) {
+				fmt.Fprintf(h, "absent %q\n", name)
+				continue
+			} else if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(h, "file %q %x\n", name, sum)
+		}
+	}
+
+	paths := make([]string, 0, len(p.Imports))
+	for path := range p.Imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		key, err := c.key(p.Imports[path], keys)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "import %q %s\n", path, key)
+	}
+
+	keys[p] = hex.EncodeToString(h.Sum(nil))
+	return keys[p], nil
+}
+
+// fileHash returns the hash of the contents of the file name.
+func fileHash(name string) ([]byte, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.Dir, key[:2], key)
+}
+
+// read returns the cached diagnostics of p.  ok is false if there are
+// none, or they can't be mapped back to its files.
+func (c *Cache) read(key string, p *packages.Package, analyzers []*analysis.Analyzer, files map[string]*token.File) (diags []Diagnostic, ok bool) {
+	b, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var cached []cachedDiagnostic
+	if err := json.Unmarshal(b, &cached); err != nil {
+		return nil, false
+	}
+
+	byName := map[string]*analysis.Analyzer{}
+	for _, a := range analyzers {
+		byName[a.Name] = a
+	}
+	pos := func(p cachedPos) token.Pos {
+		if p.File == "" {
+			return token.NoPos
+		}
+		f := files[p.File]
+		if f == nil || p.Offset > f.Size() {
+			ok = false
+			return token.NoPos
+		}
+		return f.Pos(p.Offset)
+	}
+
+	ok = true
+	for _, cd := range cached {
+		d := analysis.Diagnostic{
+			Pos:      pos(cd.Pos),
+			End:      pos(cd.End),
+			Category: cd.Category,
+			Message:  cd.Message,
+			URL:      cd.URL,
+		}
+		for _, cf := range cd.Fixes {
+			fix := analysis.SuggestedFix{Message: cf.Message}
+			for _, ce := range cf.Edits {
+				fix.TextEdits = append(fix.TextEdits, analysis.TextEdit{Pos: pos(ce.Pos), End: pos(ce.End), NewText: []byte(ce.New)})
+			}
+			d.SuggestedFixes = append(d.SuggestedFixes, fix)
+		}
+		for _, cr := range cd.Related {
+			d.Related = append(d.Related, analysis.RelatedInformation{Pos: pos(cr.Pos), End: pos(cr.End), Message: cr.Message})
+		}
+
+		a := byName[cd.Analyzer]
+		if a == nil {
+			return nil, false
+		}
+		diags = append(diags, Diagnostic{
+			Diagnostic: d,
+			Analyzer:   a,
+			Fset:       p.Fset,
+			PkgPath:    p.PkgPath,
+			Severity:   Error,
+		})
+	}
+	if !ok {
+		return nil, false
+	}
+	return diags, true
+}
+
+// write writes diags to the cache under key.  The file is written under a
+// temporary name and renamed into place, so concurrent runs never read a
+// partial entry.
+func (c *Cache) write(key string, diags []Diagnostic) error {
+	cached := []cachedDiagnostic{}
+	for _, d := range diags {
+		pos := func(p token.Pos) cachedPos {
+			if !p.IsValid() {
+				return cachedPos{}
+			}
+			posn := d.Fset.Position(p)
+			return cachedPos{File: posn.Filename, Offset: posn.Offset}
+		}
+
+		cd := cachedDiagnostic{
+			Analyzer: d.Analyzer.Name,
+			Pos:      pos(d.Pos),
+			End:      pos(d.End),
+			Category: d.Category,
+			Message:  d.Message,
+			URL:      d.URL,
+		}
+		for _, fix := range d.SuggestedFixes {
+			cf := cachedFix{Message: fix.Message}
+			for _, e := range fix.TextEdits {
+				cf.Edits = append(cf.Edits, cachedEdit{Pos: pos(e.Pos), End: pos(e.End), New: string(e.NewText)})
+			}
+			cd.Fixes = append(cd.Fixes, cf)
+		}
+		for _, r := range d.Related {
+			cd.Related = append(cd.Related, cachedRelated{Pos: pos(r.Pos), End: pos(r.End), Message: r.Message})
+		}
+		cached = append(cached, cd)
+	}
+
+	b, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), key+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,116 @@
+package driver
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestCache(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"a/a.go": `package a
+
+var X = "old"
+`,
+		"b/b.go": `package b
+
+import "example.com/m/a"
+
+var Y = "one" + a.X
+`,
+	})
+
+	var runs atomic.Int32
+	counter := *renamer
+	counter.Run = func(p *analysis.Pass) (interface{}, error) {
+		runs.Add(1)
+		return renamer.Run(p)
+	}
+	analyzers := []*analysis.Analyzer{&counter}
+	cache := &Cache{Dir: t.TempDir(), Salt: "test"}
+
+	analyze := func() []string {
+		t.Helper()
+
+		runs.Store(0)
+		pkgs, err := Load(LoadConfig{Dir: dir}, "./...")
+		if err != nil {
+			t.Fatal(err)
+		}
+		diags, err := cache.Analyze(analyzers, pkgs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []string
+		for _, d := range diags {
+			posn := d.Position()
+			posn.Filename = filepath.Base(posn.Filename)
+			got = append(got, posn.String()+": "+d.Message)
+			// the fixes must survive the cache too
+			if len(d.SuggestedFixes) != 1 || d.Fset.Position(d.SuggestedFixes[0].TextEdits[0].End).Column != posn.Column+len(`"old"`) {
+				t.Errorf("%s: unexpected fixes %v", posn, d.SuggestedFixes)
+			}
+		}
+		return got
+	}
+
+	expected := []string{`a.go:3:9: rename "old"`, `b.go:5:9: rename "one"`}
+	if d := cmp.Diff(expected, analyze()); d != "" {
+		t.Errorf("unexpected diagnostics (-expected +got):\n%s", d)
+	}
+	if n := runs.Load(); n != 2 {
+		t.Errorf("expected 2 packages analyzed on the first run, got %d", n)
+	}
+
+	if d := cmp.Diff(expected, analyze()); d != "" {
+		t.Errorf("unexpected cached diagnostics (-expected +got):\n%s", d)
+	}
+	if n := runs.Load(); n != 0 {
+		t.Errorf("expected no packages analyzed on the second run, got %d", n)
+	}
+
+	// changing b only invalidates b, but changing a invalidates both
+	if err := os.WriteFile(filepath.Join(dir, "b/b.go"), []byte("package b\n\nimport \"example.com/m/a\"\n\nvar Y = a.X\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(expected[:1], analyze()); d != "" {
+		t.Errorf("unexpected diagnostics (-expected +got):\n%s", d)
+	}
+	if n := runs.Load(); n != 1 {
+		t.Errorf("expected 1 package analyzed after changing b, got %d", n)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "a/a.go"), []byte("package a\n\nvar X = \"new\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]string(nil), analyze()); d != "" {
+		t.Errorf("unexpected diagnostics (-expected +got):\n%s", d)
+	}
+	if n := runs.Load(); n != 2 {
+		t.Errorf("expected 2 packages analyzed after changing a, got %d", n)
+	}
+
+	// so does adding, or changing, a file Files lists
+	cache.Files = func(*packages.Package) ([]string, error) {
+		return []string{filepath.Join(dir, ".splinter.yaml")}, nil
+	}
+	for i, expected := range []int32{2, 0} {
+		analyze()
+		if n := runs.Load(); n != expected {
+			t.Errorf("expected %d packages analyzed on run %d with a missing file, got %d", expected, i+1, n)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".splinter.yaml"), []byte("discover-config: true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	analyze()
+	if n := runs.Load(); n != 2 {
+		t.Errorf("expected 2 packages analyzed after adding the file, got %d", n)
+	}
+}
@@ -0,0 +1,65 @@
+package driver
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// CollapseCopies collapses the diags reported at the same place in files
+// with identical content, like vendored copies of a package or generated
+// variants of a file, into the first of them, whose message is followed by
+// the files of the rest, so that one real problem isn't reported once for
+// every copy.  Their messages aren't compared, since they can name the
+// package of each copy.  diags are expected in the order of Less, as Diagnostics
+// returns them.
+func CollapseCopies(diags []Diagnostic) []Diagnostic {
+	type key struct {
+		content  string
+		offset   int
+		end      int
+		analyzer string
+		category string
+	}
+	contents := map[string]string{}
+	content := func(filename string) string {
+		c, ok := contents[filename]
+		if !ok {
+			c = filename // unreadable files aren't collapsed
+			if src, err := os.ReadFile(filename); err == nil {
+				c = fmt.Sprintf("%x", sha256.Sum256(src))
+			}
+			contents[filename] = c
+		}
+		return c
+	}
+
+	first := map[key]int{}
+	copies := map[int][]string{}
+	var kept []Diagnostic
+	for _, d := range diags {
+		posn := d.Position()
+		end := -1
+		if d.End.IsValid() {
+			end = d.Fset.Position(d.End).Offset
+		}
+		k := key{content(posn.Filename), posn.Offset, end, d.Analyzer.Name, d.Category}
+		if i, ok := first[k]; ok {
+			if kept[i].Position().Filename != posn.Filename {
+				copies[i] = append(copies[i], globPath("", posn.Filename))
+			}
+			continue
+		}
+		first[k] = len(kept)
+		kept = append(kept, d)
+	}
+	for i, files := range copies {
+		noun := "copies"
+		if len(files) == 1 {
+			noun = "copy"
+		}
+		kept[i].Message += fmt.Sprintf(" (also in %d identical %s: %s)", len(files), noun, strings.Join(files, ", "))
+	}
+	return kept
+}
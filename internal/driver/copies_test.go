@@ -0,0 +1,76 @@
+package driver
+
+import (
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestCollapseCopies(t *testing.T) {
+	src := `package app
+
+func F() {
+	log.Log("a")
+}
+`
+	edited := `package app
+
+func F() {
+	log.Log("a")
+	log.Log("b")
+}
+`
+	dir := t.TempDir()
+	pairs := &analysis.Analyzer{Name: "pairs"}
+	fset := token.NewFileSet()
+
+	var diags []Diagnostic
+	for _, file := range []struct{ name, src string }{
+		{"app/app.go", src},
+		{"gen/app.go", src},
+		{"vendor/example.com/app/app.go", src},
+		{"edited/app.go", edited},
+	} {
+		path := filepath.Join(dir, file.name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(file.src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		f := fset.AddFile(path, -1, len(file.src))
+		f.SetLinesForContent([]byte(file.src))
+		diags = append(diags, Diagnostic{
+			Diagnostic: analysis.Diagnostic{Pos: f.LineStart(4) + 1, Category: "odd-arity", Message: "odd"},
+			Analyzer:   pairs,
+			Fset:       fset,
+			Severity:   Error,
+		})
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	// the edited file isn't a copy, even with the same diagnostic
+	var got []string
+	for _, d := range CollapseCopies(diags) {
+		got = append(got, d.Position().Filename+": "+d.Message)
+	}
+	expected := []string{
+		filepath.Join(dir, "app/app.go") + ": odd (also in 2 identical copies: gen/app.go, vendor/example.com/app/app.go)",
+		filepath.Join(dir, "edited/app.go") + ": odd",
+	}
+	if d := cmp.Diff(expected, got); d != "" {
+		t.Errorf("unexpected diagnostics (-expected +got):\n%s", d)
+	}
+}
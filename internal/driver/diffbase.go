@@ -0,0 +1,182 @@
+package driver
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type lineRange struct{ start, end int } // inclusive
+
+// ChangedLines are the lines of the files of a git repository added or
+// changed since a ref, so that only the diagnostics on them are reported,
+// for gating changes on new violations while tolerating old ones.
+type ChangedLines struct {
+	root string
+
+	// by path relative to root, with forward slashes
+	lines map[string][]lineRange
+	whole map[string]bool // untracked files
+
+	resolved map[string]string // filenames of diagnostics, by path relative to root
+}
+
+var hunkMatcher = regexp.MustCompile(`^@@ -\d+(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// DiffBase returns the lines changed in the working tree of the git
+// repository containing dir since its merge base with ref, like those of a
+// pull request against ref, along with every line of untracked files.
+func DiffBase(dir, ref string) (*ChangedLines, error) {
+	git := func(args ...string) ([]byte, error) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+		}
+		return out, nil
+	}
+
+	out, err := git("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	root := strings.TrimSpace(string(out))
+	dir = root // so paths are relative to it
+
+	if out, err = git("merge-base", ref, "HEAD"); err != nil {
+		return nil, err
+	}
+	base := strings.TrimSpace(string(out))
+
+	// the prefixes and quoting are set explicitly, since they're
+	// configurable
+	diff, err := git("-c", "core.quotePath=false", "diff", "--no-color", "--no-ext-diff", "--src-prefix=a/", "--dst-prefix=b/", "-U0", base, "--")
+	if err != nil {
+		return nil, err
+	}
+	c, err := parseDiff(bytes.NewReader(diff), root)
+	if err != nil {
+		return nil, err
+	}
+
+	if out, err = git("-c", "core.quotePath=false", "ls-files", "--others", "--exclude-standard", "-z"); err != nil {
+		return nil, err
+	}
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name != "" {
+			c.whole[name] = true
+		}
+	}
+	return c, nil
+}
+
+// parseDiff returns the lines added by r, a unified diff with b/ prefixed
+// destination paths relative to root.
+func parseDiff(r io.Reader, root string) (*ChangedLines, error) {
+	c := &ChangedLines{root: root, lines: map[string][]lineRange{}, whole: map[string]bool{}, resolved: map[string]string{}}
+
+	var file string
+	left := 0 // lines of the current hunk yet to be read
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+	for s.Scan() {
+		line := s.Text()
+		if left > 0 {
+			// lines of content may start with --- or +++ too
+			if !strings.HasPrefix(line, "\\") { // \ No newline at end of file
+				left--
+			}
+			continue
+		}
+
+		if name, ok := strings.CutPrefix(line, "+++ "); ok {
+			if unquoted, err := strconv.Unquote(name); err == nil {
+				name = unquoted
+			}
+			file, _ = strings.CutPrefix(name, "b/")
+			if name == "/dev/null" {
+				file = ""
+			}
+			continue
+		}
+
+		m := hunkMatcher.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		count := func(s string) int {
+			if s == "" {
+				return 1
+			}
+			n, _ := strconv.Atoi(s)
+			return n
+		}
+		removed, start, added := count(m[1]), count(m[2]), count(m[3])
+		left = removed + added
+		if added > 0 && file != "" { // otherwise lines were only removed
+			c.lines[file] = append(c.lines[file], lineRange{start, start + added - 1})
+		}
+	}
+	return c, s.Err()
+}
+
+// file returns the path of filename relative to the root, resolving
+// symlinks, or "" if it's outside of it.
+func (c *ChangedLines) file(filename string) string {
+	if rel, ok := c.resolved[filename]; ok {
+		return rel
+	}
+	rel := ""
+	root, err := filepath.EvalSymlinks(c.root)
+	if err == nil {
+		var path string
+		if path, err = filepath.EvalSymlinks(filename); err == nil {
+			rel, err = filepath.Rel(root, path)
+		}
+	}
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = ""
+	}
+	rel = filepath.ToSlash(rel)
+	c.resolved[filename] = rel
+	return rel
+}
+
+// Changed returns true if the line of the file named filename was added or
+// changed.
+func (c *ChangedLines) Changed(filename string, line int) bool {
+	file := c.file(filename)
+	if c.whole[file] {
+		return true
+	}
+	for _, r := range c.lines[file] {
+		if r.start <= line && line <= r.end {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the diags starting on changed lines.
+func (c *ChangedLines) Filter(diags []Diagnostic) []Diagnostic {
+	if c == nil {
+		return diags
+	}
+	var kept []Diagnostic
+	for _, d := range diags {
+		posn := d.Position()
+		if c.Changed(posn.Filename, posn.Line) {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,111 @@
+package driver
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiffBase(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %s\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+	write := func(name, src string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q", "-b", "main")
+	write("a.go", "package a\n\nfunc F() {\n\tlog()\n\tlog()\n}\n")
+	write("b/b.go", "package b\n\nfunc G() {\n\tlog()\n}\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "base")
+
+	// a commit on a branch, changes in the working tree and an untracked
+	// file are all changes; a later commit on main isn't
+	git("checkout", "-q", "-b", "feature")
+	write("a.go", "package a\n\n// F logs.\nfunc F() {\n\tlog()\n\tlog()\n\tlog()\n}\n")
+	git("commit", "-q", "-am", "feature")
+	git("checkout", "-q", "main")
+	write("main.go", "package main\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "main")
+	git("checkout", "-q", "feature")
+	write("b/b.go", "package b\n\nfunc G() {\n\tlog()\n\t-- log()\n}\n")
+	write("c/c.go", "package c\n\nfunc H() {\n\tlog()\n}\n")
+
+	c, err := DiffBase(filepath.Join(dir, "b"), "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var changed []string
+	for _, name := range []string{"a.go", "b/b.go", "c/c.go", "main.go"} {
+		for line := 1; line <= 8; line++ {
+			if c.Changed(filepath.Join(dir, name), line) {
+				changed = append(changed, fmt.Sprintf("%s:%d", name, line))
+			}
+		}
+	}
+	expected := []string{
+		"a.go:3", "a.go:7",
+		"b/b.go:5",
+		"c/c.go:1", "c/c.go:2", "c/c.go:3", "c/c.go:4", "c/c.go:5", "c/c.go:6", "c/c.go:7", "c/c.go:8",
+	}
+	if d := cmp.Diff(expected, changed); d != "" {
+		t.Errorf("unexpected changed lines (-expected +got):\n%s", d)
+	}
+
+	if _, err := DiffBase(dir, "nope"); err == nil {
+		t.Error("expected an error for an unknown ref")
+	}
+}
+
+func TestParseDiff(t *testing.T) {
+	// removed and added lines that look like file headers
+	diff := `diff --git a/a.go b/a.go
+--- a/a.go
++++ b/a.go
+@@ -3 +3,2 @@ func F() {
+--- x
++++ y
+++ z
+@@ -10,2 +11,0 @@ func G() {
+-a
+-b
+diff --git a/old.go b/old.go
+deleted file mode 100644
+--- a/old.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package old
+`
+	c, err := parseDiff(strings.NewReader(diff), "/src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string][]lineRange{"a.go": {{3, 4}}}
+	if d := cmp.Diff(expected, c.lines, cmp.AllowUnexported(lineRange{})); d != "" {
+		t.Errorf("unexpected lines (-expected +got):\n%s", d)
+	}
+}
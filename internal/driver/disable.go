@@ -0,0 +1,98 @@
+package driver
+
+import (
+	"fmt"
+	"strings"
+)
+
+type disabled struct {
+	value string
+	paths Globs // nil for every file
+	rules RuleSet
+}
+
+// Disables turn rules off, everywhere or in the files matching a glob, so a
+// team can keep the rest of the checks on while opting out of one, say in a
+// service of its own.  It is a flag.Value accepting [glob=]rule[,rule],
+// where the glob is a file path relative to the working directory, as with
+// -include.
+type Disables struct {
+	list []disabled
+
+	// Dir is the directory paths are relative to; it defaults to the
+	// working directory.
+	Dir string
+}
+
+func (d *Disables) Set(v string) error {
+	dis := disabled{value: v, rules: RuleSet{}}
+
+	rules := v
+	if i := strings.LastIndexByte(v, '='); i >= 0 {
+		rules = v[i+1:]
+		if err := dis.paths.Set(v[:i]); err != nil {
+			return err
+		}
+		if len(dis.paths) == 0 {
+			return fmt.Errorf("invalid disable %q; no glob before =", v)
+		}
+	}
+	if err := dis.rules.Set(rules); err != nil {
+		return err
+	}
+	if len(dis.rules) == 0 {
+		return fmt.Errorf("invalid disable %q; should be of form [glob=]rule[,rule]", v)
+	}
+
+	d.list = append(d.list, dis)
+	return nil
+}
+
+func (d *Disables) String() string {
+	if d == nil {
+		return ""
+	}
+	var v []string
+	for _, dis := range d.list {
+		v = append(v, dis.value)
+	}
+	return strings.Join(v, ",")
+}
+
+// Rules returns the rules named by the entries.
+func (d *Disables) Rules() RuleSet {
+	rules := RuleSet{}
+	for _, dis := range d.list {
+		for r := range dis.rules {
+			rules[r] = true
+		}
+	}
+	return rules
+}
+
+// Disabled returns true if rule is disabled in the file named filename.
+func (d *Disables) Disabled(rule, filename string) bool {
+	for _, dis := range d.list {
+		if !dis.rules[rule] {
+			continue
+		}
+		if dis.paths == nil || dis.paths.Match(globPath(d.Dir, filename)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the diags whose rules aren't disabled where they start.
+func (d *Disables) Filter(diags []Diagnostic) []Diagnostic {
+	if len(d.list) == 0 {
+		return diags
+	}
+	var kept []Diagnostic
+	for _, diag := range diags {
+		if !d.Disabled(diag.Category, diag.Position().Filename) {
+			kept = append(kept, diag)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,46 @@
+package driver
+
+import (
+	"go/token"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestDisables(t *testing.T) {
+	dir := t.TempDir()
+	fset := token.NewFileSet()
+	var diags []Diagnostic
+	for _, name := range []string{"billing/a.go", "search/b.go"} {
+		f := fset.AddFile(filepath.Join(dir, name), -1, 10)
+		for _, rule := range []string{"odd-arity", "expression-key", "unsorted-keys"} {
+			diags = append(diags, Diagnostic{Diagnostic: analysis.Diagnostic{Pos: f.Pos(0), Category: rule}, Fset: fset})
+		}
+	}
+
+	d := &Disables{Dir: dir}
+	for _, v := range []string{"unsorted-keys", "billing/**=expression-key,odd-arity"} {
+		if err := d.Set(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, v := range []string{"", "billing/**=", "=odd-arity"} {
+		if err := d.Set(v); err == nil {
+			t.Errorf("%q is accepted", v)
+		}
+	}
+
+	var got []string
+	for _, diag := range d.Filter(diags) {
+		rel, _ := filepath.Rel(dir, diag.Position().Filename)
+		got = append(got, filepath.ToSlash(rel)+": "+diag.Category)
+	}
+	if d := cmp.Diff([]string{"search/b.go: odd-arity", "search/b.go: expression-key"}, got); d != "" {
+		t.Errorf("unexpected diagnostics (-expected +got):\n%s", d)
+	}
+	if d := cmp.Diff(RuleSet{"unsorted-keys": true, "expression-key": true, "odd-arity": true}, d.Rules()); d != "" {
+		t.Errorf("unexpected rules (-expected +got):\n%s", d)
+	}
+}
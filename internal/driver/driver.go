@@ -0,0 +1,167 @@
+// Package driver loads packages and runs analyzers over them for the
+// subcommands of the standalone splinter binary, which need more control
+// over the results than singlechecker offers.
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+// LoadConfig controls how Load finds packages.
+type LoadConfig struct {
+	// Dir is the directory patterns are relative to; the current
+	// directory if empty.
+	Dir string
+
+	// Tests includes the test variants of packages.
+	Tests bool
+
+	// Workspace expands relative patterns within each module of the
+	// go.work workspace containing Dir, so that they can all be analyzed
+	// (and share facts) in a single run.
+	Workspace bool
+
+	// IncludeIgnored loads patterns within testdata directories, and Go
+	// files excluded by their build constraints, which are skipped
+	// otherwise, as go tooling does when walking a tree.
+	IncludeIgnored bool
+}
+
+// Load loads and type checks the packages matched by patterns.
+func Load(cfg LoadConfig, patterns ...string) ([]*packages.Package, error) {
+	pcfg := &packages.Config{Mode: packages.LoadAllSyntax, Tests: cfg.Tests, Dir: cfg.Dir}
+	if cfg.Workspace {
+		var err error
+		if patterns, err = workspacePatterns(cfg.Dir, patterns); err != nil {
+			return nil, err
+		}
+	}
+	if !cfg.IncludeIgnored && len(patterns) != 0 {
+		if patterns = skipIgnored(cfg.Dir, patterns); len(patterns) == 0 {
+			return nil, errors.New("no packages matched; testdata and files excluded by build constraints are skipped")
+		}
+	}
+	pkgs, err := packages.Load(pcfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) == 0 {
+		return nil, errors.New("no packages matched")
+	}
+	if n := packages.PrintErrors(pkgs); n > 0 {
+		return nil, fmt.Errorf("%d errors loading packages", n)
+	}
+	return pkgs, nil
+}
+
+// Diagnostic is an analysis.Diagnostic along with the file set its
+// positions are relative to.
+type Diagnostic struct {
+	analysis.Diagnostic
+
+	Analyzer *analysis.Analyzer
+	Fset     *token.FileSet
+
+	// PkgPath is the import path of the package the diagnostic was
+	// reported against.
+	PkgPath string
+
+	// Severity is Error unless changed by a Policy.
+	Severity Severity
+
+	// Code is the stable code of the rule, set by a Policy that knows it.
+	Code string
+}
+
+// Position returns the position of the start of d.
+func (d Diagnostic) Position() token.Position { return d.Fset.Position(d.Pos) }
+
+// Diagnostics returns the diagnostics reported by the roots of graph,
+// sorted by Less.  Diagnostics reported more than once, because a file
+// belongs to both a package and its test variant, are only returned once.
+func Diagnostics(graph *checker.Graph) ([]Diagnostic, error) {
+	var diags []Diagnostic
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			return nil, act.Err
+		}
+		diags = append(diags, actionDiagnostics(act)...)
+	}
+	return dedupe(diags), nil
+}
+
+// actionDiagnostics returns the diagnostics reported by act.
+func actionDiagnostics(act *checker.Action) []Diagnostic {
+	var diags []Diagnostic
+	for _, d := range act.Diagnostics {
+		diags = append(diags, Diagnostic{
+			Diagnostic: d,
+			Analyzer:   act.Analyzer,
+			Fset:       act.Package.Fset,
+			PkgPath:    act.Package.PkgPath,
+			Severity:   Error,
+		})
+	}
+	return diags
+}
+
+// Less reports whether a sorts before b: by file, then position, end,
+// rule, message and analyzer, so that the order of diagnostics never
+// depends on the order their packages were analyzed in, which may vary
+// from run to run.
+func Less(a, b Diagnostic) bool {
+	pa, pb := a.Position(), b.Position()
+	if pa.Filename != pb.Filename {
+		return pa.Filename < pb.Filename
+	}
+	if pa.Offset != pb.Offset {
+		return pa.Offset < pb.Offset
+	}
+	if ea, eb := a.Fset.Position(a.End).Offset, b.Fset.Position(b.End).Offset; ea != eb {
+		return ea < eb
+	}
+	if a.Category != b.Category {
+		return a.Category < b.Category
+	}
+	if a.Message != b.Message {
+		return a.Message < b.Message
+	}
+	return a.Analyzer.Name < b.Analyzer.Name
+}
+
+// dedupe sorts diags by Less and removes the diagnostics reported more
+// than once, keeping the one reported against the package with the least
+// import path, so the same one is kept every run.
+func dedupe(diags []Diagnostic) []Diagnostic {
+	sort.SliceStable(diags, func(i, j int) bool {
+		if Less(diags[i], diags[j]) || Less(diags[j], diags[i]) {
+			return Less(diags[i], diags[j])
+		}
+		return diags[i].PkgPath < diags[j].PkgPath
+	})
+
+	type key struct {
+		posn     token.Position
+		category string
+		message  string
+	}
+	seen := map[key]bool{}
+
+	var deduped []Diagnostic
+	for _, d := range diags {
+		k := key{d.Position(), d.Category, d.Message}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		deduped = append(deduped, d)
+	}
+	return deduped
+}
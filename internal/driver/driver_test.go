@@ -0,0 +1,135 @@
+package driver
+
+import (
+	"go/token"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestLoadWorkspace(t *testing.T) {
+	// -mod=mod is not allowed in workspace mode
+	t.Setenv("GOFLAGS", "")
+
+	dir := writeModule(t, map[string]string{
+		"go.work":    "go 1.22\n\nuse (\n\t./svc\n\t./lib\n)\n",
+		"svc/go.mod": "module example.com/svc\n\ngo 1.22\n",
+		"svc/a/a.go": `package a
+
+import "example.com/lib/log"
+
+func F() { log.Log("a") }
+`,
+		"lib/go.mod":     "module example.com/lib\n\ngo 1.22\n",
+		"lib/log/log.go": "package log\n\nfunc Log(kv ...interface{}) {}\n",
+	})
+
+	if _, err := Load(LoadConfig{Dir: dir}, "./..."); err == nil {
+		t.Error("expected ./... to match nothing at the root of a workspace")
+	}
+
+	pkgs, err := Load(LoadConfig{Dir: dir, Workspace: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, p := range pkgs {
+		paths = append(paths, p.PkgPath)
+	}
+	sort.Strings(paths)
+	if d := cmp.Diff([]string{"example.com/lib/log", "example.com/svc/a"}, paths); d != "" {
+		t.Errorf("unexpected packages (-expected +got):\n%s", d)
+	}
+
+	if _, err := Load(LoadConfig{Dir: t.TempDir(), Workspace: true}); err == nil {
+		t.Error("expected an error outside of a workspace")
+	}
+}
+
+func TestLoadIgnored(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":            "module example.com/m\n\ngo 1.22\n",
+		"a/a.go":            "package a\n",
+		"a/testdata/t/t.go": "package t\n",
+		"gen.go":            "//go:build ignore\n\npackage main\n\nfunc main() {}\n",
+	})
+
+	for _, pattern := range []string{"./a/testdata/t", "gen.go", "a/testdata/t/t.go"} {
+		if _, err := Load(LoadConfig{Dir: dir}, pattern); err == nil {
+			t.Errorf("expected %s to be skipped", pattern)
+		}
+		if _, err := Load(LoadConfig{Dir: dir, IncludeIgnored: true}, pattern); err != nil {
+			t.Errorf("%s with IncludeIgnored: %s", pattern, err)
+		}
+	}
+
+	pkgs, err := Load(LoadConfig{Dir: dir}, "./...", "gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, p := range pkgs {
+		paths = append(paths, p.PkgPath)
+	}
+	if d := cmp.Diff([]string{"example.com/m/a"}, paths); d != "" {
+		t.Errorf("unexpected packages (-expected +got):\n%s", d)
+	}
+}
+
+func TestDiagnosticOrder(t *testing.T) {
+	fset := token.NewFileSet()
+	a := fset.AddFile("a.go", -1, 100)
+	b := fset.AddFile("b.go", -1, 100)
+	pairs, events := &analysis.Analyzer{Name: "pairs"}, &analysis.Analyzer{Name: "events"}
+
+	diag := func(f *token.File, offset, end int, category, message string, a *analysis.Analyzer, pkg string) Diagnostic {
+		return Diagnostic{
+			Diagnostic: analysis.Diagnostic{Pos: f.Pos(offset), End: f.Pos(end), Category: category, Message: message},
+			Analyzer:   a,
+			Fset:       fset,
+			PkgPath:    pkg,
+		}
+	}
+	diags := []Diagnostic{
+		diag(b, 1, 2, "odd-arity", "x", pairs, "p"),
+		diag(a, 5, 9, "odd-arity", "x", pairs, "p"), // the same, with another end
+		diag(a, 5, 7, "odd-arity", "x", pairs, "p"),
+		diag(a, 5, 7, "non-string-key", "y", pairs, "p"),
+		diag(a, 5, 7, "non-string-key", "x", pairs, "p"),
+		diag(a, 5, 7, "event-key", "x", events, "p"),
+		diag(a, 1, 3, "odd-arity", "x", pairs, "q"),
+		diag(a, 1, 3, "odd-arity", "x", pairs, "p"), // the same, in another package
+	}
+
+	type line struct {
+		posn, rule, msg, analyzer, pkg string
+	}
+	lines := func(diags []Diagnostic) []line {
+		var l []line
+		for _, d := range diags {
+			l = append(l, line{d.Position().String(), d.Category, d.Message, d.Analyzer.Name, d.PkgPath})
+		}
+		return l
+	}
+	expected := []line{
+		{"a.go:1:2", "odd-arity", "x", "pairs", "p"},
+		{"a.go:1:6", "event-key", "x", "events", "p"},
+		{"a.go:1:6", "non-string-key", "x", "pairs", "p"},
+		{"a.go:1:6", "non-string-key", "y", "pairs", "p"},
+		{"a.go:1:6", "odd-arity", "x", "pairs", "p"},
+		{"b.go:1:2", "odd-arity", "x", "pairs", "p"},
+	}
+
+	// however they're reported, they come out the same
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		shuffled := append([]Diagnostic{}, diags...)
+		r.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		if d := cmp.Diff(expected, lines(dedupe(shuffled)), cmp.AllowUnexported(line{})); d != "" {
+			t.Fatalf("unexpected order (-expected +got):\n%s", d)
+		}
+	}
+}
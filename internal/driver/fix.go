@@ -0,0 +1,212 @@
+package driver
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Edit replaces the bytes in [Start, End) of a file with New.
+type Edit struct {
+	Start, End int
+	New        string
+}
+
+func (e Edit) overlaps(o Edit) bool {
+	if e.Start == e.End || o.Start == o.End { // insertions only clash at the same offset
+		return e.Start == o.Start || (e.Start > o.Start && e.Start < o.End) || (o.Start > e.Start && o.Start < e.End)
+	}
+	return e.Start < o.End && o.Start < e.End
+}
+
+// FixSet is the set of edits selected from the suggested fixes of some
+// diagnostics.
+type FixSet struct {
+	// Edits maps filenames to non-overlapping edits, sorted by offset.
+	Edits map[string][]Edit
+
+	// Applied counts the fixes used, by diagnostic category.
+	Applied map[string]int
+
+	// Conflicts counts the fixes skipped because they overlapped a fix
+	// already in the set.
+	Conflicts int
+}
+
+// Fixes selects the first suggested fix of each of diags whose category is
+// in rules, or of every diagnostic if rules is empty.  A fix is skipped if
+// any of its edits overlaps one from a previously selected fix.
+func Fixes(diags []Diagnostic, rules map[string]bool) *FixSet {
+	s := &FixSet{Edits: map[string][]Edit{}, Applied: map[string]int{}}
+
+fixes:
+	for _, d := range diags {
+		if len(d.SuggestedFixes) == 0 {
+			continue
+		}
+		if len(rules) != 0 && !rules[d.Category] {
+			continue
+		}
+
+		type fileEdit struct {
+			file string
+			edit Edit
+		}
+		var edits []fileEdit
+		for _, te := range d.SuggestedFixes[0].TextEdits {
+			end := te.End
+			if !end.IsValid() {
+				end = te.Pos
+			}
+			start := d.Fset.Position(te.Pos)
+			edits = append(edits, fileEdit{start.Filename, Edit{
+				Start: start.Offset,
+				End:   d.Fset.Position(end).Offset,
+				New:   string(te.NewText),
+			}})
+		}
+
+		for _, fe := range edits {
+			for _, e := range s.Edits[fe.file] {
+				if e.overlaps(fe.edit) {
+					s.Conflicts++
+					continue fixes
+				}
+			}
+		}
+		for _, fe := range edits {
+			s.Edits[fe.file] = append(s.Edits[fe.file], fe.edit)
+		}
+		s.Applied[d.Category]++
+	}
+
+	for _, edits := range s.Edits {
+		sort.Slice(edits, func(i, j int) bool { return edits[i].Start < edits[j].Start })
+	}
+	return s
+}
+
+// Files returns the names of the files s edits, sorted.
+func (s *FixSet) Files() []string {
+	files := make([]string, 0, len(s.Edits))
+	for f := range s.Edits {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// Summary describes the fixes in s in a single line.
+func (s *FixSet) Summary() string {
+	rules := make([]string, 0, len(s.Applied))
+	total := 0
+	for r, n := range s.Applied {
+		rules = append(rules, fmt.Sprintf("%s: %d", r, n))
+		total += n
+	}
+	sort.Strings(rules)
+
+	summary := fmt.Sprintf("%d fixes in %d files", total, len(s.Edits))
+	if len(rules) != 0 {
+		summary += " (" + strings.Join(rules, ", ") + ")"
+	}
+	if s.Conflicts != 0 {
+		summary += fmt.Sprintf("; %d conflicting fixes skipped", s.Conflicts)
+	}
+	return summary
+}
+
+// Write applies the fixes in s to the files on disk.
+func (s *FixSet) Write() error {
+	for _, f := range s.Files() {
+		src, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		info, err := os.Stat(f)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(f, applyEdits(src, s.Edits[f]), info.Mode()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Diff writes a diff of the fixes in s to w.  Hunks cover only the lines
+// changed, without context.
+func (s *FixSet) Diff(w io.Writer) error {
+	for _, f := range s.Files() {
+		src, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "--- %s\n+++ %s\n", f, f)
+		writeHunks(w, src, s.Edits[f])
+	}
+	return nil
+}
+
+// applyEdits returns src with edits, which must be sorted and not overlap,
+// applied.
+func applyEdits(src []byte, edits []Edit) []byte {
+	var b bytes.Buffer
+	last := 0
+	for _, e := range edits {
+		b.Write(src[last:e.Start])
+		b.WriteString(e.New)
+		last = e.End
+	}
+	b.Write(src[last:])
+	return b.Bytes()
+}
+
+func writeHunks(w io.Writer, src []byte, edits []Edit) {
+	lineStart := func(off int) int { return bytes.LastIndexByte(src[:off], '\n') + 1 }
+	lineEnd := func(off int) int {
+		if i := bytes.IndexByte(src[off:], '\n'); i >= 0 {
+			return off + i + 1
+		}
+		return len(src)
+	}
+	lineOf := func(off int) int { return bytes.Count(src[:off], []byte("\n")) + 1 }
+	lines := func(b []byte) []string {
+		if len(b) == 0 {
+			return nil
+		}
+		return strings.SplitAfter(strings.TrimSuffix(string(b), "\n"), "\n")
+	}
+
+	delta := 0 // lines added by previous hunks
+	for i := 0; i < len(edits); {
+		start, end := lineStart(edits[i].Start), lineEnd(edits[i].End)
+		j := i + 1
+		for j < len(edits) && edits[j].Start < end {
+			end = lineEnd(edits[j].End)
+			j++
+		}
+
+		hunk := make([]Edit, j-i)
+		for k, e := range edits[i:j] {
+			hunk[k] = Edit{Start: e.Start - start, End: e.End - start, New: e.New}
+		}
+		old := lines(src[start:end])
+		new := lines(applyEdits(src[start:end], hunk))
+
+		first := lineOf(start)
+		fmt.Fprintf(w, "@@ -%d,%d +%d,%d @@\n", first, len(old), first+delta, len(new))
+		for _, l := range old {
+			fmt.Fprintf(w, "-%s\n", strings.TrimSuffix(l, "\n"))
+		}
+		for _, l := range new {
+			fmt.Fprintf(w, "+%s\n", strings.TrimSuffix(l, "\n"))
+		}
+
+		delta += len(new) - len(old)
+		i = j
+	}
+}
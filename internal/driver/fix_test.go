@@ -0,0 +1,193 @@
+package driver
+
+import (
+	"bytes"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+)
+
+// renamer suggests replacing the string literals "old" with "new", and
+// "one" with "two", under different categories.
+var renamer = &analysis.Analyzer{
+	Name: "renamer",
+	Doc:  "renamer is a test analyzer",
+	Run: func(p *analysis.Pass) (interface{}, error) {
+		for _, f := range p.Files {
+			ast.Inspect(f, func(n ast.Node) bool {
+				lit, ok := n.(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					return true
+				}
+				to := map[string]string{`"old"`: `"new"`, `"one"`: `"two"`}[lit.Value]
+				if to == "" {
+					return true
+				}
+				p.Report(analysis.Diagnostic{
+					Pos:      lit.Pos(),
+					Category: map[string]string{`"new"`: "rename", `"two"`: "count"}[to],
+					Message:  "rename " + lit.Value,
+					SuggestedFixes: []analysis.SuggestedFix{{
+						TextEdits: []analysis.TextEdit{{Pos: lit.Pos(), End: lit.End(), NewText: []byte(to)}},
+					}},
+				})
+				return true
+			})
+		}
+		return nil, nil
+	},
+}
+
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	files["go.mod"] = "module example.com/m\n\ngo 1.22\n"
+	for name, src := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestFixes(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"a.go": `package a
+
+var (
+	X = "old"
+	Y = "one"
+	Z = []string{"old",
+		"one"}
+)
+`,
+		"a_test.go": `package a
+
+var T = "old"
+`,
+	})
+
+	pkgs, err := Load(LoadConfig{Dir: dir, Tests: true}, "./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+	graph, err := checker.Analyze([]*analysis.Analyzer{renamer}, pkgs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diags, err := Diagnostics(graph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diags) != 5 {
+		t.Fatalf("expected 5 diagnostics, got %d", len(diags))
+	}
+
+	fixes := Fixes(diags, map[string]bool{"rename": true})
+	if d := cmp.Diff("3 fixes in 2 files (rename: 3)", fixes.Summary()); d != "" {
+		t.Errorf("unexpected summary (-expected +got):\n%s", d)
+	}
+
+	var diff bytes.Buffer
+	if err := fixes.Diff(&diff); err != nil {
+		t.Fatal(err)
+	}
+	a, at := filepath.Join(dir, "a.go"), filepath.Join(dir, "a_test.go")
+	expected := `--- ` + a + `
++++ ` + a + `
+@@ -4,1 +4,1 @@
+-	X = "old"
++	X = "new"
+@@ -6,1 +6,1 @@
+-	Z = []string{"old",
++	Z = []string{"new",
+--- ` + at + `
++++ ` + at + `
+@@ -3,1 +3,1 @@
+-var T = "old"
++var T = "new"
+`
+	if d := cmp.Diff(expected, diff.String()); d != "" {
+		t.Errorf("unexpected diff (-expected +got):\n%s", d)
+	}
+
+	if err := Fixes(diags, nil).Write(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(`package a
+
+var (
+	X = "new"
+	Y = "two"
+	Z = []string{"new",
+		"two"}
+)
+`, string(got)); d != "" {
+		t.Errorf("unexpected result (-expected +got):\n%s", d)
+	}
+}
+
+func TestFixesConflict(t *testing.T) {
+	fset := token.NewFileSet()
+	f := fset.AddFile("a.go", -1, 100)
+	diag := func(start, end int, category string) Diagnostic {
+		return Diagnostic{Fset: fset, Diagnostic: analysis.Diagnostic{
+			Pos:      f.Pos(start),
+			Category: category,
+			SuggestedFixes: []analysis.SuggestedFix{{
+				TextEdits: []analysis.TextEdit{{Pos: f.Pos(start), End: f.Pos(end)}},
+			}},
+		}}
+	}
+
+	s := Fixes([]Diagnostic{
+		diag(0, 10, "a"),
+		diag(5, 15, "b"),  // overlaps the first
+		diag(10, 10, "b"), // insertion after the first
+		diag(10, 10, "c"), // insertion at the same place
+	}, nil)
+	if d := cmp.Diff("2 fixes in 1 files (a: 1, b: 1); 2 conflicting fixes skipped", s.Summary()); d != "" {
+		t.Errorf("unexpected summary (-expected +got):\n%s", d)
+	}
+}
+
+func TestHunks(t *testing.T) {
+	src := []byte("one\ntwo\nthree\nfour\n")
+	tests := []struct {
+		edits []Edit
+		out   string
+	}{
+		{[]Edit{{Start: 4, End: 7, New: "2"}}, "@@ -2,1 +2,1 @@\n-two\n+2\n"},
+		{[]Edit{{Start: 4, End: 4, New: "zero\n"}}, "@@ -2,1 +2,2 @@\n-two\n+zero\n+two\n"},
+		{
+			[]Edit{{Start: 0, End: 3, New: "1\n1.5"}, {Start: 14, End: 18, New: "4"}},
+			"@@ -1,1 +1,2 @@\n-one\n+1\n+1.5\n@@ -4,1 +5,1 @@\n-four\n+4\n",
+		},
+		{[]Edit{{Start: 2, End: 5, New: ""}}, "@@ -1,2 +1,1 @@\n-one\n-two\n+onwo\n"},
+	}
+
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			var b bytes.Buffer
+			writeHunks(&b, src, test.edits)
+			if d := cmp.Diff(test.out, b.String()); d != "" {
+				t.Errorf("unexpected hunks (-expected +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package driver
+
+import (
+	"go/build"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// skipIgnored returns the patterns that go tooling wouldn't skip when
+// walking the tree: patterns within testdata directories, and Go files
+// excluded by their build constraints, like //go:build ignore, are
+// dropped.  File patterns are relative to dir.
+func skipIgnored(dir string, patterns []string) []string {
+	var kept []string
+	for _, p := range patterns {
+		if slices.Contains(strings.Split(filepath.ToSlash(p), "/"), "testdata") {
+			continue
+		}
+		if strings.HasSuffix(p, ".go") {
+			path := p
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+			if ok, err := build.Default.MatchFile(filepath.Dir(path), filepath.Base(path)); err == nil && !ok {
+				continue
+			}
+		}
+		kept = append(kept, p)
+	}
+	return kept
+}
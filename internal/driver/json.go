@@ -0,0 +1,73 @@
+package driver
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// jsonDiagnostic is the form of a diagnostic in the report of PrintJSON: its
+// Finding, along with the related information and suggested fixes that go
+// vet -json includes.
+type jsonDiagnostic struct {
+	Finding
+	SuggestedFixes []jsonSuggestedFix `json:"suggested_fixes,omitempty"`
+	Related        []jsonRelated      `json:"related,omitempty"`
+}
+
+type jsonSuggestedFix struct {
+	Message string         `json:"message"`
+	Edits   []jsonTextEdit `json:"edits"`
+}
+
+type jsonTextEdit struct {
+	Filename string `json:"filename"`
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+	New      string `json:"new"`
+}
+
+type jsonRelated struct {
+	Posn    string `json:"posn"`
+	Message string `json:"message"`
+}
+
+// PrintJSON writes diags to w as a JSON object mapping the import path of
+// each package to an object mapping the name of each analyzer to the
+// diagnostics it reported against the package, the shape of go vet -json
+// output.  Unlike checker's, the diagnostics are those a Policy kept, with
+// their severities and codes.
+func PrintJSON(w io.Writer, diags []Diagnostic) error {
+	tree := map[string]map[string][]jsonDiagnostic{}
+	for _, d := range diags {
+		jd := jsonDiagnostic{Finding: NewFinding(d)}
+		for _, fix := range d.SuggestedFixes {
+			jf := jsonSuggestedFix{Message: fix.Message, Edits: []jsonTextEdit{}}
+			for _, edit := range fix.TextEdits {
+				jf.Edits = append(jf.Edits, jsonTextEdit{
+					Filename: d.Fset.Position(edit.Pos).Filename,
+					Start:    d.Fset.Position(edit.Pos).Offset,
+					End:      d.Fset.Position(edit.End).Offset,
+					New:      string(edit.NewText),
+				})
+			}
+			jd.SuggestedFixes = append(jd.SuggestedFixes, jf)
+		}
+		for _, r := range d.Related {
+			jd.Related = append(jd.Related, jsonRelated{Posn: d.Fset.Position(r.Pos).String(), Message: r.Message})
+		}
+
+		byAnalyzer := tree[d.PkgPath]
+		if byAnalyzer == nil {
+			byAnalyzer = map[string][]jsonDiagnostic{}
+			tree[d.PkgPath] = byAnalyzer
+		}
+		byAnalyzer[d.Analyzer.Name] = append(byAnalyzer[d.Analyzer.Name], jd)
+	}
+
+	data, err := json.MarshalIndent(tree, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,60 @@
+package driver
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/token"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestPrintJSON(t *testing.T) {
+	fset := token.NewFileSet()
+	f := fset.AddFile("a.go", -1, 100)
+	f.SetLines([]int{0, 10, 20})
+
+	pairs := &analysis.Analyzer{Name: "pairs"}
+	diag := func(category string) Diagnostic {
+		return Diagnostic{
+			Diagnostic: analysis.Diagnostic{Pos: f.Pos(12), Category: category, Message: category},
+			Analyzer:   pairs,
+			Fset:       fset,
+			PkgPath:    "example.com/app",
+			Severity:   Error,
+		}
+	}
+
+	// only what the policy keeps is written, with its severity
+	p := Policy{Escalate: RuleSet{}, Severities: Severities{}}
+	if err := p.Disable.Set("odd-arity"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Severities.Set("expression-key=warning"); err != nil {
+		t.Fatal(err)
+	}
+	diags := p.Apply([]Diagnostic{diag("odd-arity"), diag("expression-key")})
+
+	var b bytes.Buffer
+	if err := PrintJSON(&b, diags); err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]map[string][]Finding
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]map[string][]Finding{
+		"example.com/app": {"pairs": {{
+			Posn:     "a.go:2:3",
+			Package:  "example.com/app",
+			Analyzer: "pairs",
+			Category: "expression-key",
+			Severity: "warning",
+			Message:  "expression-key",
+		}}},
+	}
+	if d := cmp.Diff(expected, got); d != "" {
+		t.Errorf("unexpected report (-expected +got):\n%s", d)
+	}
+}
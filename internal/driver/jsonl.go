@@ -0,0 +1,121 @@
+package driver
+
+import (
+	"encoding/json"
+	"go/token"
+	"go/types"
+	"io"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+// Finding is the structured form of a diagnostic, as written by Stream and
+// sent to Sinks.
+type Finding struct {
+	Posn     string `json:"posn"`
+	End      string `json:"end,omitempty"`
+	Package  string `json:"package"`
+	Analyzer string `json:"analyzer"`
+	Category string `json:"category,omitempty"`
+	Code     string `json:"code,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	URL      string `json:"url,omitempty"`
+}
+
+// NewFinding returns the Finding of d.
+func NewFinding(d Diagnostic) Finding {
+	f := Finding{
+		Posn:     d.Position().String(),
+		Package:  d.PkgPath,
+		Analyzer: d.Analyzer.Name,
+		Category: d.Category,
+		Code:     d.Code,
+		Severity: d.Severity.String(),
+		Message:  d.Message,
+		URL:      d.URL,
+	}
+	if d.End.IsValid() {
+		f.End = d.Fset.Position(d.End).String()
+	}
+	return f
+}
+
+// Stream analyzes pkgs like checker.Analyze, but rather than collecting the
+// diagnostics, writes each one reported against pkgs to w as a line of JSON
+// as soon as its analyzer reports it, so that consumers can act on them
+// before a large run finishes.  Diagnostics are silenced and assigned
+// severities by policy, and deduplicated like Diagnostics, though not
+// sorted.  It returns the number of those written that fail the run, being
+// more severe than policy.MaxExit.
+func Stream(w io.Writer, analyzers []*analysis.Analyzer, pkgs []*packages.Package, policy *Policy) (int, error) {
+	roots := map[*types.Package]bool{}
+	for _, p := range pkgs {
+		roots[p.Types] = true
+	}
+
+	type key struct {
+		posn     token.Position
+		category string
+		message  string
+	}
+	var (
+		mu     sync.Mutex
+		seen   = map[key]bool{}
+		errs   int
+		encErr error
+	)
+	enc := json.NewEncoder(w)
+	emit := func(d Diagnostic) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		k := key{d.Position(), d.Category, d.Message}
+		if seen[k] || encErr != nil {
+			return
+		}
+		seen[k] = true
+
+		diags := policy.Apply([]Diagnostic{d})
+		if len(diags) == 0 {
+			return
+		}
+		d = diags[0]
+		if policy.Fails(d) {
+			errs++
+		}
+		encErr = enc.Encode(NewFinding(d))
+	}
+
+	// the analyzers are copied with a Run that reports to emit as well,
+	// since checker offers no hook for diagnostics as they're reported
+	streaming := make([]*analysis.Analyzer, len(analyzers))
+	for i, a := range analyzers {
+		a, s := a, *a
+		s.Run = func(p *analysis.Pass) (interface{}, error) {
+			if roots[p.Pkg] {
+				report := p.Report
+				p.Report = func(d analysis.Diagnostic) {
+					report(d)
+					emit(Diagnostic{Diagnostic: d, Analyzer: a, Fset: p.Fset, PkgPath: p.Pkg.Path(), Severity: Error})
+				}
+			}
+			return a.Run(p)
+		}
+		streaming[i] = &s
+	}
+
+	graph, err := checker.Analyze(streaming, pkgs, nil)
+	if err != nil {
+		return errs, err
+	}
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			return errs, act.Err
+		}
+	}
+	return errs, encErr
+}
@@ -0,0 +1,80 @@
+package driver
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestStream(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"a.go": `package a
+
+import "example.com/m/b"
+
+var X = "old" + b.Y
+`,
+		"a_test.go": `package a
+
+var T = "one"
+`,
+		"b/b.go": `package b
+
+var Y = "old"
+`,
+	})
+
+	// only a is a root, so b's diagnostic isn't streamed; the diagnostic
+	// in a.go is reported by both a and its test variant, but only
+	// streamed once
+	pkgs, err := Load(LoadConfig{Dir: dir, Tests: true}, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy := &Policy{Escalate: RuleSet{"count": true}}
+	var buf bytes.Buffer
+	errs, err := Stream(&buf, []*analysis.Analyzer{renamer}, pkgs, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs != 1 {
+		t.Errorf("expected 1 error, got %d", errs)
+	}
+
+	var got []Finding
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var d Finding
+		if err := dec.Decode(&d); err != nil {
+			t.Fatal(err)
+		}
+		d.Posn = filepath.Base(d.Posn)
+		got = append(got, d)
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i].Posn < got[j].Posn })
+
+	expected := []Finding{{
+		Posn:     "a.go:5:9",
+		Package:  "example.com/m",
+		Analyzer: "renamer",
+		Category: "rename",
+		Severity: "error",
+		Message:  `rename "old"`,
+	}, {
+		Posn:     "a_test.go:3:9",
+		Package:  "example.com/m",
+		Analyzer: "renamer",
+		Category: "count",
+		Severity: "warning",
+		Message:  `rename "one"`,
+	}}
+	if d := cmp.Diff(expected, got); d != "" {
+		t.Errorf("unexpected diagnostics (-expected +got):\n%s", d)
+	}
+}
@@ -0,0 +1,141 @@
+package driver
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+type glob struct {
+	pattern string
+	match   *regexp.Regexp
+}
+
+// Globs are patterns of file paths, relative to the working directory with
+// forward slashes, where * matches within a path element, ** matches any
+// number of them and ? matches a single character.  It is a flag.Value
+// accepting a comma separated list.
+type Globs []glob
+
+func (g *Globs) Set(v string) error {
+	for _, pattern := range strings.Split(v, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			*g = append(*g, glob{pattern: pattern, match: globRegexp(pattern)})
+		}
+	}
+	return nil
+}
+
+func (g *Globs) String() string {
+	if g == nil {
+		return ""
+	}
+	var s []string
+	for _, glob := range *g {
+		s = append(s, glob.pattern)
+	}
+	return strings.Join(s, ",")
+}
+
+// Match returns true if any of the globs matches the path.
+func (g Globs) Match(path string) bool {
+	for _, glob := range g {
+		if glob.match.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// globRegexp returns a regexp matching the paths pattern does.
+func globRegexp(pattern string) *regexp.Regexp {
+	var re strings.Builder
+	re.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			re.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "/**") && i+3 == len(pattern):
+			re.WriteString("(?:/.*)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			re.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			re.WriteString("[^/]*")
+		case pattern[i] == '?':
+			re.WriteString("[^/]")
+		default:
+			re.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	re.WriteString("$")
+	return regexp.MustCompile(re.String())
+}
+
+// Paths selects the files whose diagnostics are reported: those matching
+// Include, if it's set, and not Exclude.
+type Paths struct {
+	Include, Exclude Globs
+
+	// Dir is the directory paths are relative to; it defaults to the
+	// working directory.
+	Dir string
+}
+
+// Selects returns true if the file named filename is selected.
+func (p *Paths) Selects(filename string) bool {
+	if len(p.Include) == 0 && len(p.Exclude) == 0 {
+		return true
+	}
+	filename = globPath(p.Dir, filename)
+	return (len(p.Include) == 0 || p.Include.Match(filename)) && !p.Exclude.Match(filename)
+}
+
+// globPath returns filename relative to dir, or the working directory if
+// it's empty, with forward slashes, as Globs match it.
+func globPath(dir, filename string) string {
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	if rel, err := filepath.Rel(dir, filename); err == nil {
+		filename = rel
+	}
+	return filepath.ToSlash(filename)
+}
+
+// Filter returns the diags starting in selected files.
+func (p *Paths) Filter(diags []Diagnostic) []Diagnostic {
+	if len(p.Include) == 0 && len(p.Exclude) == 0 {
+		return diags
+	}
+	var kept []Diagnostic
+	for _, d := range diags {
+		if p.Selects(d.Position().Filename) {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
+
+// Packages returns the pkgs with any selected files, so that those with
+// none aren't analyzed at all.
+func (p *Paths) Packages(pkgs []*packages.Package) []*packages.Package {
+	if len(p.Include) == 0 && len(p.Exclude) == 0 {
+		return pkgs
+	}
+	var kept []*packages.Package
+	for _, pkg := range pkgs {
+		for _, f := range pkg.GoFiles {
+			if p.Selects(f) {
+				kept = append(kept, pkg)
+				break
+			}
+		}
+	}
+	return kept
+}
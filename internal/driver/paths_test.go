@@ -0,0 +1,82 @@
+package driver
+
+import (
+	"go/token"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestGlobs(t *testing.T) {
+	tests := []struct {
+		pattern string
+		matches []string
+		misses  []string
+	}{
+		{"vendor/**", []string{"vendor/a.go", "vendor/x/y/b.go"}, []string{"a/vendor/a.go", "vendored/a.go"}},
+		{"**/testdata/**", []string{"testdata/a.go", "a/b/testdata/c/d.go"}, []string{"a/testdata.go", "a/mytestdata/b.go"}},
+		{"**/*_mock.go", []string{"a_mock.go", "a/b/c_mock.go"}, []string{"a/b/mock.go", "a/b/c_mock.go.orig"}},
+		{"gen/*.go", []string{"gen/a.go"}, []string{"gen/x/a.go", "gen/a.gox"}},
+		{"a/**/b.go", []string{"a/b.go", "a/x/y/b.go"}, []string{"a/xb.go"}},
+		{"a?.go", []string{"ab.go"}, []string{"a/.go", "abc.go"}},
+	}
+	for _, test := range tests {
+		var g Globs
+		if err := g.Set(test.pattern); err != nil {
+			t.Fatal(err)
+		}
+		for _, path := range test.matches {
+			if !g.Match(path) {
+				t.Errorf("%s doesn't match %s", test.pattern, path)
+			}
+		}
+		for _, path := range test.misses {
+			if g.Match(path) {
+				t.Errorf("%s matches %s", test.pattern, path)
+			}
+		}
+	}
+}
+
+func TestPaths(t *testing.T) {
+	dir := t.TempDir()
+	fset := token.NewFileSet()
+	var diags []Diagnostic
+	for _, name := range []string{"app/a.go", "app/testdata/b.go", "vendor/c.go", "lib/d.go"} {
+		f := fset.AddFile(filepath.Join(dir, name), -1, 10)
+		diags = append(diags, Diagnostic{Diagnostic: analysis.Diagnostic{Pos: f.Pos(0)}, Fset: fset})
+	}
+
+	tests := []struct {
+		name             string
+		include, exclude string
+		files            []string
+	}{
+		{"all", "", "", []string{"app/a.go", "app/testdata/b.go", "vendor/c.go", "lib/d.go"}},
+		{"exclude", "", "vendor/**,**/testdata/**", []string{"app/a.go", "lib/d.go"}},
+		{"include", "app/**", "", []string{"app/a.go", "app/testdata/b.go"}},
+		{"both", "app/**,lib/**", "**/testdata/**", []string{"app/a.go", "lib/d.go"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := &Paths{Dir: dir}
+			if err := p.Include.Set(test.include); err != nil {
+				t.Fatal(err)
+			}
+			if err := p.Exclude.Set(test.exclude); err != nil {
+				t.Fatal(err)
+			}
+
+			var files []string
+			for _, d := range p.Filter(diags) {
+				rel, _ := filepath.Rel(dir, d.Position().Filename)
+				files = append(files, filepath.ToSlash(rel))
+			}
+			if d := cmp.Diff(test.files, files); d != "" {
+				t.Errorf("unexpected files (-expected +got):\n%s", d)
+			}
+		})
+	}
+}
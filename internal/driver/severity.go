@@ -0,0 +1,277 @@
+package driver
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ZipRecruiter/splinter/internal/pkgpattern"
+)
+
+// Severity is how serious a diagnostic is; by default, only errors fail a
+// run.  It is a flag.Value accepting the name of a severity.
+type Severity int
+
+// The severities, from least to most severe.
+const (
+	Info Severity = iota + 1
+	Warning
+	Error
+)
+
+var severityNames = map[Severity]string{Info: "info", Warning: "warning", Error: "error"}
+
+func (s Severity) String() string {
+	if name, ok := severityNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+func (s *Severity) Set(v string) error {
+	for sev, name := range severityNames {
+		if name == v {
+			*s = sev
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid severity %q; should be %s, %s or %s", v, Error, Warning, Info)
+}
+
+// Severities assigns severities to rules.  It is a flag.Value accepting
+// rule[,rule]=severity.
+type Severities map[string]Severity
+
+func (s Severities) Set(v string) error {
+	i := strings.LastIndexByte(v, '=')
+	if i < 0 {
+		return fmt.Errorf("invalid severity %q; should be of form <rule>[,<rule>]=<severity>", v)
+	}
+	var sev Severity
+	if err := sev.Set(v[i+1:]); err != nil {
+		return err
+	}
+	for _, rule := range strings.Split(v[:i], ",") {
+		if rule = strings.TrimSpace(rule); rule != "" {
+			s[rule] = sev
+		}
+	}
+	return nil
+}
+
+func (s Severities) String() string {
+	var v []string
+	for rule, sev := range s {
+		v = append(v, rule+"="+sev.String())
+	}
+	sort.Strings(v)
+	return strings.Join(v, ",")
+}
+
+// Rules returns the rules assigned a severity.
+func (s Severities) Rules() RuleSet {
+	rules := RuleSet{}
+	for r := range s {
+		rules[r] = true
+	}
+	return rules
+}
+
+// The maturity tiers packages can be assigned to.
+const (
+	Experimental = "experimental"
+	Stable       = "stable"
+)
+
+type tier struct {
+	pattern string
+	match   *regexp.Regexp
+	tier    string
+}
+
+// Tiers assigns packages to maturity tiers by package pattern.  It is a
+// flag.Value accepting pattern=tier, where the pattern is an import path
+// that may contain ... wildcards, as with go list.
+type Tiers []tier
+
+func (t *Tiers) Set(v string) error {
+	i := strings.LastIndexByte(v, '=')
+	if i < 0 {
+		return fmt.Errorf("invalid tier %q; should be of form <pattern>=<tier>", v)
+	}
+	pattern, name := v[:i], v[i+1:]
+	if name != Experimental && name != Stable {
+		return fmt.Errorf("invalid tier %q; should be %s or %s", name, Experimental, Stable)
+	}
+
+	*t = append(*t, tier{pattern: pattern, match: pkgpattern.Regexp(pattern), tier: name})
+	return nil
+}
+
+func (t *Tiers) String() string {
+	var s []string
+	for _, t := range *t {
+		s = append(s, t.pattern+"="+t.tier)
+	}
+	return strings.Join(s, ",")
+}
+
+// Tier returns the tier of the package with the given import path.  The
+// longest matching pattern wins, and packages matching no pattern are
+// experimental.  External test packages, whose paths end in _test, are in
+// the tier of the package they test.
+func (t Tiers) Tier(pkgPath string) string {
+	pkgPath = strings.TrimSuffix(pkgPath, "_test")
+	best, tier := -1, Experimental
+	for _, t := range t {
+		if len(t.pattern) > best && t.match.MatchString(pkgPath) {
+			best, tier = len(t.pattern), t.tier
+		}
+	}
+	return tier
+}
+
+// RuleSet is a set of rules.  It is a flag.Value accepting a comma
+// separated list.
+type RuleSet map[string]bool
+
+func (r RuleSet) Set(v string) error {
+	for _, rule := range strings.Split(v, ",") {
+		if rule = strings.TrimSpace(rule); rule != "" {
+			r[rule] = true
+		}
+	}
+	return nil
+}
+
+// resolve replaces the codes in r with the rules they're codes of.
+func (r RuleSet) resolve(rules map[string]string) {
+	for v := range r {
+		if rule, ok := rules[v]; ok {
+			delete(r, v)
+			r[rule] = true
+		}
+	}
+}
+
+func (r RuleSet) String() string {
+	var s []string
+	for rule := range r {
+		s = append(s, rule)
+	}
+	return strings.Join(s, ",")
+}
+
+// Policy assigns severities to diagnostics, and silences some.
+type Policy struct {
+	Tiers Tiers
+
+	// Escalate holds the rules that are warnings, except in stable
+	// packages, where they are errors.
+	Escalate RuleSet
+
+	// Warnings holds the rules that are always warnings, unless they're
+	// escalated.  Other rules are always errors.
+	Warnings RuleSet
+
+	// Severities holds the severities configured for rules, which take
+	// precedence over Escalate and Warnings.
+	Severities Severities
+
+	// MaxExit is the most severe a diagnostic can be without failing a
+	// run; Warning if zero.
+	MaxExit Severity
+
+	Suppress Suppressions
+
+	// Disable turns rules off, everywhere or in some files.
+	Disable Disables
+
+	// Paths selects the files whose diagnostics are kept.
+	Paths Paths
+
+	// Baseline, if set, holds the pre-existing diagnostics to drop.
+	// Within a single call to Apply, each is dropped only as many times
+	// as it was recorded.
+	Baseline *Baseline
+
+	// Changed, if set, holds the lines to report diagnostics on; the
+	// rest are dropped.
+	Changed *ChangedLines
+
+	// Codes maps rules to their stable codes, which the rules of the
+	// rest of the fields may be given as, once ResolveCodes is called.
+	Codes map[string]string
+}
+
+// ResolveCodes replaces the codes among the rules of p with the rules they
+// are codes of.
+func (p *Policy) ResolveCodes() {
+	rules := map[string]string{}
+	for rule, code := range p.Codes {
+		rules[code] = rule
+	}
+	p.Escalate.resolve(rules)
+	p.Warnings.resolve(rules)
+	for v, sev := range p.Severities {
+		if rule, ok := rules[v]; ok {
+			delete(p.Severities, v)
+			p.Severities[rule] = sev
+		}
+	}
+	for _, sup := range p.Suppress.list {
+		sup.rules.resolve(rules)
+	}
+	for _, dis := range p.Disable.list {
+		dis.rules.resolve(rules)
+	}
+}
+
+// Apply drops the diags in files p.Paths doesn't select, of rules p.Disable
+// turns off, silenced by p.Suppress, recorded in p.Baseline or not on
+// p.Changed lines, and sets the severity and code of each of the rest,
+// which it returns.
+func (p *Policy) Apply(diags []Diagnostic) []Diagnostic {
+	diags = p.Paths.Filter(diags)
+	diags = p.Disable.Filter(diags)
+	diags = p.Suppress.Filter(diags)
+	diags = p.Baseline.Filter(diags)
+	diags = p.Changed.Filter(diags)
+	for i, d := range diags {
+		diags[i].Code = p.Codes[d.Category]
+		if sev, ok := p.Severities[d.Category]; ok {
+			diags[i].Severity = sev
+			continue
+		}
+		switch {
+		case p.Escalate[d.Category]:
+			if p.Tiers.Tier(d.PkgPath) != Stable {
+				diags[i].Severity = Warning
+			}
+		case p.Warnings[d.Category]:
+			diags[i].Severity = Warning
+		}
+	}
+	return diags
+}
+
+// Fails returns true if d is more severe than p.MaxExit, failing a run.
+func (p *Policy) Fails(d Diagnostic) bool {
+	max := p.MaxExit
+	if max == 0 {
+		max = Warning
+	}
+	return d.Severity > max
+}
+
+// Failures returns the number of diags that fail a run.
+func (p *Policy) Failures(diags []Diagnostic) int {
+	n := 0
+	for _, d := range diags {
+		if p.Fails(d) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,203 @@
+package driver
+
+import (
+	"bytes"
+	"go/token"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestTiers(t *testing.T) {
+	var tiers Tiers
+	for _, v := range []string{
+		"example.com/...=stable",
+		"example.com/labs/...=experimental",
+		"example.com/labs/graduated=stable",
+	} {
+		if err := tiers.Set(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		pkg, tier string
+	}{
+		{"example.com", Stable},
+		{"example.com/payments", Stable},
+		{"example.com/labs", Experimental},
+		{"example.com/labs/thing", Experimental},
+		{"example.com/labs/graduated", Stable},
+		{"example.com/labs/graduated/sub", Experimental},
+		{"example.com/labs/graduated_test", Stable},
+		{"example.org/other", Experimental},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if d := cmp.Diff(test.tier, tiers.Tier(test.pkg)); d != "" {
+				t.Errorf("unexpected tier for %s (-expected +got):\n%s", test.pkg, d)
+			}
+		})
+	}
+
+	for _, v := range []string{"example.com/...", "example.com/...=production"} {
+		if err := tiers.Set(v); err == nil {
+			t.Errorf("expected an error setting %q", v)
+		}
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	fset := token.NewFileSet()
+	f := fset.AddFile("a.go", -1, 100)
+	f.SetLines([]int{0, 10, 20})
+
+	diag := func(pkg, category string) Diagnostic {
+		return Diagnostic{
+			Diagnostic: analysis.Diagnostic{Pos: f.Pos(12), Category: category, Message: category},
+			Fset:       fset,
+			PkgPath:    pkg,
+			Severity:   Error,
+		}
+	}
+	diags := []Diagnostic{
+		diag("example.com/stable", "odd-arity"),
+		diag("example.com/stable", "expression-key"),
+		diag("example.com/new", "odd-arity"),
+		diag("example.com/new", "expression-key"),
+		diag("example.com/stable", "sunset-key"),
+		diag("example.com/new", "sunset-key"),
+	}
+
+	p := Policy{Escalate: RuleSet{}, Warnings: RuleSet{"sunset-key": true}}
+	if err := p.Tiers.Set("example.com/stable=stable"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Escalate.Set("expression-key"); err != nil {
+		t.Fatal(err)
+	}
+	diags = p.Apply(diags)
+
+	var got []Severity
+	for _, d := range diags {
+		got = append(got, d.Severity)
+	}
+	if d := cmp.Diff([]Severity{Error, Error, Error, Warning, Warning, Warning}, got); d != "" {
+		t.Errorf("unexpected severities (-expected +got):\n%s", d)
+	}
+	if n := p.Failures(diags); n != 3 {
+		t.Errorf("expected 3 failures, got %d", n)
+	}
+
+	var b bytes.Buffer
+	if err := PrintText(&b, diags[2:4], -1); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff("a.go:2:3: odd-arity\na.go:2:3: warning: expression-key\n", b.String()); d != "" {
+		t.Errorf("unexpected output (-expected +got):\n%s", d)
+	}
+}
+
+func TestPolicyCodes(t *testing.T) {
+	fset := token.NewFileSet()
+	f := fset.AddFile("a.go", -1, 100)
+	f.SetLines([]int{0, 10, 20})
+
+	diag := func(category string) Diagnostic {
+		return Diagnostic{
+			Diagnostic: analysis.Diagnostic{Pos: f.Pos(12), Category: category, Message: category},
+			Fset:       fset,
+			PkgPath:    "example.com/new",
+			Severity:   Error,
+		}
+	}
+	diags := []Diagnostic{diag("odd-arity"), diag("expression-key"), diag("unknown-key"), diag("other")}
+
+	p := Policy{
+		Escalate: RuleSet{},
+		Codes:    map[string]string{"odd-arity": "SPL001", "expression-key": "SPL003", "unknown-key": "SPL010"},
+	}
+	if err := p.Escalate.Set("SPL003"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Disable.Set("SPL010"); err != nil {
+		t.Fatal(err)
+	}
+	p.ResolveCodes()
+	diags = p.Apply(diags)
+
+	var got []string
+	for _, d := range diags {
+		got = append(got, d.Category+" "+d.Code+" "+d.Severity.String())
+	}
+	expected := []string{"odd-arity SPL001 error", "expression-key SPL003 warning", "other  error"}
+	if d := cmp.Diff(expected, got); d != "" {
+		t.Errorf("unexpected diagnostics (-expected +got):\n%s", d)
+	}
+}
+
+func TestPolicySeverities(t *testing.T) {
+	fset := token.NewFileSet()
+	f := fset.AddFile("a.go", -1, 100)
+	f.SetLines([]int{0, 10, 20})
+
+	diag := func(category string) Diagnostic {
+		return Diagnostic{
+			Diagnostic: analysis.Diagnostic{Pos: f.Pos(12), Category: category, Message: category},
+			Fset:       fset,
+			PkgPath:    "example.com/stable",
+			Severity:   Error,
+		}
+	}
+	diags := []Diagnostic{diag("odd-arity"), diag("key-pattern"), diag("expression-key"), diag("sunset-key")}
+
+	p := Policy{
+		Escalate:   RuleSet{},
+		Warnings:   RuleSet{"sunset-key": true},
+		Severities: Severities{},
+		Codes:      map[string]string{"key-pattern": "SPL007"},
+	}
+	if err := p.Tiers.Set("example.com/...=stable"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Escalate.Set("expression-key"); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []string{"SPL007=info", "expression-key,sunset-key=warning"} {
+		if err := p.Severities.Set(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, v := range []string{"odd-arity", "odd-arity=fatal"} {
+		if err := p.Severities.Set(v); err == nil {
+			t.Errorf("expected an error setting %q", v)
+		}
+	}
+	p.ResolveCodes()
+	diags = p.Apply(diags)
+
+	var got []Severity
+	for _, d := range diags {
+		got = append(got, d.Severity)
+	}
+	if d := cmp.Diff([]Severity{Error, Info, Warning, Warning}, got); d != "" {
+		t.Errorf("unexpected severities (-expected +got):\n%s", d)
+	}
+
+	for _, test := range []struct {
+		max      Severity
+		failures int
+	}{
+		{0, 1},
+		{Error, 0},
+		{Warning, 1},
+		{Info, 3},
+	} {
+		p.MaxExit = test.max
+		if n := p.Failures(diags); n != test.failures {
+			t.Errorf("expected %d failures with -max-severity-exit %s, got %d", test.failures, test.max, n)
+		}
+	}
+}
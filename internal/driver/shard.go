@@ -0,0 +1,69 @@
+package driver
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Shard selects one of a number of disjoint shards of the packages, so that
+// analyzing a very large repository can be spread over several machines,
+// whose reports are combined by splinter merge-reports.  It is a flag.Value
+// accepting i/N, where i counts from 1 to N.  The zero Shard selects every
+// package.
+type Shard struct {
+	Index, Count int
+}
+
+func (s *Shard) Set(v string) error {
+	i := strings.IndexByte(v, '/')
+	if i < 0 {
+		return fmt.Errorf("invalid shard %q; should be of form <i>/<N>", v)
+	}
+	index, err1 := strconv.Atoi(v[:i])
+	count, err2 := strconv.Atoi(v[i+1:])
+	if err1 != nil || err2 != nil || count < 1 || index < 1 || index > count {
+		return fmt.Errorf("invalid shard %q; should be of form <i>/<N>, with i from 1 to N", v)
+	}
+	s.Index, s.Count = index, count
+	return nil
+}
+
+func (s *Shard) String() string {
+	if s == nil || s.Count == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", s.Index, s.Count)
+}
+
+// Selects returns true if the package with the given import path is in the
+// shard.  Packages are assigned by a hash of their path, so that the shard
+// of a package doesn't depend on which others are analyzed, and external
+// test packages, whose paths end in _test, are in the shard of the package
+// they test.
+func (s Shard) Selects(pkgPath string) bool {
+	if s.Count <= 1 {
+		return true
+	}
+	h := fnv.New32a()
+	h.Write([]byte(strings.TrimSuffix(pkgPath, "_test")))
+	return int(h.Sum32()%uint32(s.Count)) == s.Index-1
+}
+
+// Packages returns the pkgs in the shard.  The packages they import are
+// still loaded, for their facts, but not reported on.
+func (s Shard) Packages(pkgs []*packages.Package) []*packages.Package {
+	if s.Count <= 1 {
+		return pkgs
+	}
+	var kept []*packages.Package
+	for _, pkg := range pkgs {
+		if s.Selects(pkg.PkgPath) {
+			kept = append(kept, pkg)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,52 @@
+package driver
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestShard(t *testing.T) {
+	for _, v := range []string{"1", "0/2", "3/2", "a/2", "1/0", "1/b"} {
+		var s Shard
+		if err := s.Set(v); err == nil {
+			t.Errorf("-shard %s was accepted", v)
+		}
+	}
+
+	var paths []string
+	for i := 0; i < 100; i++ {
+		paths = append(paths, fmt.Sprintf("example.com/app/pkg%d", i))
+	}
+
+	// every package is in exactly one shard, along with its external
+	// tests
+	in := map[string]int{}
+	for i := 1; i <= 3; i++ {
+		var s Shard
+		if err := s.Set(fmt.Sprintf("%d/3", i)); err != nil {
+			t.Fatal(err)
+		}
+		n := 0
+		for _, path := range paths {
+			if s.Selects(path) {
+				in[path]++
+				n++
+				if !s.Selects(path + "_test") {
+					t.Errorf("%s_test isn't in shard %s with %s", path, &s, path)
+				}
+			}
+		}
+		if n == 0 {
+			t.Errorf("shard %s is empty", &s)
+		}
+	}
+	for _, path := range paths {
+		if in[path] != 1 {
+			t.Errorf("%s is in %d shards", path, in[path])
+		}
+	}
+
+	if !(Shard{}).Selects(paths[0]) {
+		t.Errorf("the zero shard doesn't select %s", paths[0])
+	}
+}
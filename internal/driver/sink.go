@@ -0,0 +1,141 @@
+package driver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// SinkReport is what's sent to Sinks, and the data of a sink template.
+type SinkReport struct {
+	Findings []Finding `json:"findings"`
+	Errors   int       `json:"errors"`
+	Warnings int       `json:"warnings"`
+	Infos    int       `json:"infos"`
+}
+
+// Sinks send the findings of a run to files and webhooks, so that scans can
+// feed other systems directly.  It is a flag.Value accepting file:<path>,
+// written with the report, or an http:// or https:// URL, to which it's
+// POSTed.  The report is JSON, unless a Template is set.
+type Sinks struct {
+	list []string
+
+	// Template, if set, renders the report instead, with a SinkReport as
+	// its data and a json func quoting values as JSON.
+	Template *template.Template
+
+	// Client sends the requests to webhooks; it defaults to a client
+	// with a 30 second timeout.
+	Client *http.Client
+}
+
+func (s *Sinks) Set(v string) error {
+	if path, ok := strings.CutPrefix(v, "file:"); ok {
+		if path == "" {
+			return fmt.Errorf("invalid sink %q; no path after file:", v)
+		}
+	} else if u, err := url.Parse(v); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid sink %q; should be file:<path> or an http:// or https:// URL", v)
+	}
+	s.list = append(s.list, v)
+	return nil
+}
+
+func (s *Sinks) String() string {
+	if s == nil {
+		return ""
+	}
+	return strings.Join(s.list, ",")
+}
+
+// Len returns the number of sinks.
+func (s *Sinks) Len() int {
+	return len(s.list)
+}
+
+// SetTemplate parses the sink template in the file at path.
+func (s *Sinks) SetTemplate(path string) error {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	t, err := template.New(path).Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}).Parse(string(text))
+	if err != nil {
+		return err
+	}
+	s.Template = t
+	return nil
+}
+
+// Send sends the report of diags to every sink, returning the first error.
+func (s *Sinks) Send(diags []Diagnostic) error {
+	if s == nil || len(s.list) == 0 {
+		return nil
+	}
+
+	r := SinkReport{Findings: []Finding{}}
+	for _, d := range diags {
+		r.Findings = append(r.Findings, NewFinding(d))
+		switch {
+		case d.Severity >= Error:
+			r.Errors++
+		case d.Severity == Warning:
+			r.Warnings++
+		default:
+			r.Infos++
+		}
+	}
+	var body bytes.Buffer
+	if s.Template != nil {
+		if err := s.Template.Execute(&body, r); err != nil {
+			return err
+		}
+	} else {
+		enc := json.NewEncoder(&body)
+		enc.SetIndent("", "\t")
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+
+	for _, sink := range s.list {
+		if err := s.send(sink, body.Bytes()); err != nil {
+			return fmt.Errorf("sink %s: %w", sink, err)
+		}
+	}
+	return nil
+}
+
+func (s *Sinks) send(sink string, body []byte) error {
+	if path, ok := strings.CutPrefix(sink, "file:"); ok {
+		return os.WriteFile(path, body, 0o644)
+	}
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Post(sink, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s", resp.Status)
+	}
+	return nil
+}
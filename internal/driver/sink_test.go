@@ -0,0 +1,108 @@
+package driver
+
+import (
+	"encoding/json"
+	"go/token"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestSinks(t *testing.T) {
+	fset := token.NewFileSet()
+	f := fset.AddFile("a.go", -1, 100)
+	a := &analysis.Analyzer{Name: "pairs"}
+	diags := []Diagnostic{{
+		Diagnostic: analysis.Diagnostic{Pos: f.Pos(10), Category: "odd-arity", Message: "3 args passed to log.Log; must be even"},
+		Analyzer:   a,
+		Fset:       fset,
+		PkgPath:    "example.com/app",
+		Severity:   Error,
+	}, {
+		Diagnostic: analysis.Diagnostic{Pos: f.Pos(20), Category: "expression-key", Message: `key "a"+b`},
+		Analyzer:   a,
+		Fset:       fset,
+		PkgPath:    "example.com/app",
+		Severity:   Warning,
+	}}
+
+	var posted []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		posted, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "findings.json")
+	s := &Sinks{}
+	for _, v := range []string{"file:" + path, server.URL + "/hook"} {
+		if err := s.Set(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Send(diags); err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(string(written), string(posted)); d != "" {
+		t.Errorf("file and webhook got different reports (-file +webhook):\n%s", d)
+	}
+	var got SinkReport
+	if err := json.Unmarshal(written, &got); err != nil {
+		t.Fatal(err)
+	}
+	expected := SinkReport{
+		Findings: []Finding{
+			{Posn: "a.go:1:11", Package: "example.com/app", Analyzer: "pairs", Category: "odd-arity", Severity: "error", Message: "3 args passed to log.Log; must be even"},
+			{Posn: "a.go:1:21", Package: "example.com/app", Analyzer: "pairs", Category: "expression-key", Severity: "warning", Message: `key "a"+b`},
+		},
+		Errors:   1,
+		Warnings: 1,
+	}
+	if d := cmp.Diff(expected, got); d != "" {
+		t.Errorf("unexpected report (-expected +got):\n%s", d)
+	}
+
+	tmpl := filepath.Join(dir, "issue.tmpl")
+	if err := os.WriteFile(tmpl, []byte(`{"title": "{{.Errors}} splinter errors", "body": {{range $i, $f := .Findings}}{{if $i}} + {{end}}{{json $f.Message}}{{end}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetTemplate(tmpl); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Send(diags); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(`{"title": "1 splinter errors", "body": "3 args passed to log.Log; must be even" + "key \"a\"+b"}`, string(posted)); d != "" {
+		t.Errorf("unexpected templated report (-expected +got):\n%s", d)
+	}
+
+	s = &Sinks{}
+	if err := s.Set(server.URL + "/missing"); err != nil {
+		t.Fatal(err)
+	}
+	server.Config.Handler = http.NotFoundHandler()
+	if err := s.Send(diags); err == nil {
+		t.Error("expected an error from a webhook responding 404")
+	}
+
+	for _, v := range []string{"file:", "ftp://example.com", "findings.json", "https://"} {
+		if err := s.Set(v); err == nil {
+			t.Errorf("expected an error setting %q", v)
+		}
+	}
+}
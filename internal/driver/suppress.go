@@ -0,0 +1,259 @@
+package driver
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZipRecruiter/splinter/internal/calls"
+)
+
+type suppression struct {
+	value string
+
+	// either file and line, or fun
+	file string
+	line int
+	fun  calls.Selector
+
+	rules   RuleSet // nil for every rule
+	expires time.Time
+}
+
+// Suppressions silence the diagnostics at specific call sites, for code
+// that can't carry a directive, like generated code edited by hand or a
+// vendored fork.  It is a flag.Value accepting <file>:<line>, for the
+// diagnostics starting on that line of a file (given by a path relative to
+// the module, or any trailing part of it), or [pkg[.type]].<func>, for the
+// diagnostics within that func (or, without pkg, any func or method of that
+// name); either may be followed by =rule[,rule] to silence only those rules.
+//
+// In a configuration file, an entry may have a line comment giving the date
+// it expires on, after which it silences nothing:
+//
+//	suppress:
+//	  - gen/models.go:120  # expires 2026-12-31, once the generator is fixed
+type Suppressions struct {
+	list []suppression
+
+	// Now returns the current time, for expiry; it defaults to time.Now.
+	Now func() time.Time
+}
+
+var (
+	fileLineMatcher = regexp.MustCompile(`^(.+\.go):(\d+)$`)
+	expiryMatcher   = regexp.MustCompile(`\bexpires:?\s+(\d{4}-\d{2}-\d{2})\b`)
+)
+
+func (s *Suppressions) Set(v string) error {
+	return s.SetWithComment(v, "")
+}
+
+// SetWithComment sets v, taking its expiry from comment, if it has one.
+func (s *Suppressions) SetWithComment(v, comment string) error {
+	sup := suppression{value: v}
+
+	scope := v
+	if i := strings.LastIndexByte(v, '='); i >= 0 {
+		scope = v[:i]
+		sup.rules = RuleSet{}
+		if err := sup.rules.Set(v[i+1:]); err != nil {
+			return err
+		}
+		if len(sup.rules) == 0 {
+			return fmt.Errorf("invalid suppression %q; no rules after =", v)
+		}
+	}
+
+	if m := fileLineMatcher.FindStringSubmatch(scope); m != nil {
+		line, err := strconv.Atoi(m[2])
+		if err != nil {
+			return err
+		}
+		sup.file, sup.line = filepath.ToSlash(filepath.Clean(m[1])), line
+	} else {
+		sel, err := calls.ParseSelector(scope)
+		if err != nil || strings.Contains(scope, ":") || strings.HasSuffix(scope, ".go") {
+			return fmt.Errorf("invalid suppression %q; should be of form <file>:<line> or [pkg[.type]].<func>, optionally followed by =rule[,rule]", v)
+		}
+		sup.fun = sel
+	}
+
+	if m := expiryMatcher.FindStringSubmatch(comment); m != nil {
+		expires, err := time.Parse(time.DateOnly, m[1])
+		if err != nil {
+			return fmt.Errorf("invalid expiry for suppression %q: %w", v, err)
+		}
+		sup.expires = expires
+	}
+
+	s.list = append(s.list, sup)
+	return nil
+}
+
+func (s *Suppressions) String() string {
+	if s == nil {
+		return ""
+	}
+	var v []string
+	for _, sup := range s.list {
+		v = append(v, sup.value)
+	}
+	return strings.Join(v, ",")
+}
+
+func (s *Suppressions) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+func (sup suppression) expired(now time.Time) bool {
+	// an entry is good through the day it expires on
+	return !sup.expires.IsZero() && !now.Before(sup.expires.AddDate(0, 0, 1))
+}
+
+// Rules returns the rules named by the entries.
+func (s *Suppressions) Rules() RuleSet {
+	rules := RuleSet{}
+	for _, sup := range s.list {
+		for r := range sup.rules {
+			rules[r] = true
+		}
+	}
+	return rules
+}
+
+// Expired returns the entries that have expired, with their expiry dates.
+func (s *Suppressions) Expired() []string {
+	var v []string
+	for _, sup := range s.list {
+		if sup.expired(s.now()) {
+			v = append(v, fmt.Sprintf("%s (expired %s)", sup.value, sup.expires.Format(time.DateOnly)))
+		}
+	}
+	return v
+}
+
+// Filter returns the diags that aren't silenced by an unexpired entry.
+func (s *Suppressions) Filter(diags []Diagnostic) []Diagnostic {
+	if s == nil || len(s.list) == 0 {
+		return diags
+	}
+
+	funcs := funcFinder{}
+	var kept []Diagnostic
+	for _, d := range diags {
+		if !s.suppressed(d, funcs) {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
+
+func (s *Suppressions) suppressed(d Diagnostic, funcs funcFinder) bool {
+	posn := d.Position()
+	for _, sup := range s.list {
+		if sup.expired(s.now()) || (sup.rules != nil && !sup.rules[d.Category]) {
+			continue
+		}
+		if sup.file != "" {
+			name := filepath.ToSlash(posn.Filename)
+			if posn.Line == sup.line && (name == sup.file || strings.HasSuffix(name, "/"+sup.file)) {
+				return true
+			}
+			continue
+		}
+
+		typ, fun, ok := funcs.enclosing(posn)
+		if !ok || fun != sup.fun.Fun {
+			continue
+		}
+		if sup.fun.Pkg == "" { // any func or method with the name
+			return true
+		}
+		if typ == sup.fun.Typ && (sup.fun.Pkg == d.PkgPath || sup.fun.Pkg+"_test" == d.PkgPath) {
+			return true
+		}
+	}
+	return false
+}
+
+type funcDecl struct {
+	start, end int // offsets
+	typ, fun   string
+}
+
+// funcFinder finds the funcs enclosing positions, parsing each file once.
+type funcFinder map[string][]funcDecl
+
+// enclosing returns the receiver type, if any, and the name of the func
+// declaration enclosing posn.
+func (f funcFinder) enclosing(posn token.Position) (typ, fun string, ok bool) {
+	decls, seen := f[posn.Filename]
+	if !seen {
+		decls = parseFuncs(posn.Filename)
+		f[posn.Filename] = decls
+	}
+	for _, d := range decls {
+		if d.start <= posn.Offset && posn.Offset < d.end {
+			return d.typ, d.fun, true
+		}
+	}
+	return "", "", false
+}
+
+// parseFuncs returns the func declarations of the file at path, or none if
+// it can't be parsed.
+func parseFuncs(path string) []funcDecl {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+
+	var decls []funcDecl
+	for _, d := range file.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		decl := funcDecl{
+			start: fset.Position(fn.Pos()).Offset,
+			end:   fset.Position(fn.End()).Offset,
+			fun:   fn.Name.Name,
+		}
+		if fn.Recv != nil && len(fn.Recv.List) == 1 {
+			decl.typ = recvType(fn.Recv.List[0].Type)
+		}
+		decls = append(decls, decl)
+	}
+	return decls
+}
+
+// recvType returns the name of the type of a method receiver.
+func recvType(e ast.Expr) string {
+	for {
+		switch x := e.(type) {
+		case *ast.StarExpr:
+			e = x.X
+		case *ast.ParenExpr:
+			e = x.X
+		case *ast.IndexExpr: // generic types
+			e = x.X
+		case *ast.IndexListExpr:
+			e = x.X
+		case *ast.Ident:
+			return x.Name
+		default:
+			return ""
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package driver
+
+import (
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestSuppressions(t *testing.T) {
+	src := `package gen
+
+func Models() {
+	log()
+	log()
+}
+
+func (*Store) Save() {
+	log()
+}
+`
+	dir := t.TempDir()
+	path := filepath.Join(dir, "internal", "gen", "models.go")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fset := token.NewFileSet()
+	f := fset.AddFile(path, -1, len(src))
+	f.SetLinesForContent([]byte(src))
+	diag := func(line int, category string) Diagnostic {
+		return Diagnostic{
+			Diagnostic: analysis.Diagnostic{Pos: f.LineStart(line) + 1, Category: category},
+			Fset:       fset,
+			PkgPath:    "example.com/internal/gen",
+			Severity:   Error,
+		}
+	}
+	diags := []Diagnostic{
+		diag(4, "odd-arity"),
+		diag(5, "odd-arity"),
+		diag(5, "expression-key"),
+		diag(9, "odd-arity"),
+	}
+
+	tests := []struct {
+		name    string
+		values  []string
+		comment string
+		lines   []int
+	}{
+		{"none", nil, "", []int{4, 5, 5, 9}},
+		{"file:line", []string{"gen/models.go:4"}, "", []int{5, 5, 9}},
+		{"other file", []string{"other/models.go:4"}, "", []int{4, 5, 5, 9}},
+		{"rules", []string{"internal/gen/models.go:5=expression-key"}, "", []int{4, 5, 9}},
+		{"func", []string{"example.com/internal/gen.Models"}, "", []int{9}},
+		{"method", []string{"example.com/internal/gen.Store.Save=odd-arity"}, "", []int{4, 5, 5}},
+		{"generous", []string{".Save"}, "", []int{4, 5, 5}},
+		{"other package", []string{"example.com/other.Models"}, "", []int{4, 5, 5, 9}},
+		{"unexpired", []string{"gen/models.go:4"}, "expires 2026-10-15", []int{5, 5, 9}},
+		{"expired", []string{"gen/models.go:4"}, "expires: 2026-10-14, ask in #platform", []int{4, 5, 5, 9}},
+	}
+	now := func() time.Time { return time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC) }
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &Suppressions{Now: now}
+			for _, v := range test.values {
+				if err := s.SetWithComment(v, test.comment); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var lines []int
+			for _, d := range s.Filter(diags) {
+				lines = append(lines, d.Position().Line)
+			}
+			if d := cmp.Diff(test.lines, lines); d != "" {
+				t.Errorf("unexpected lines (-expected +got):\n%s", d)
+			}
+		})
+	}
+
+	s := &Suppressions{Now: now}
+	if err := s.SetWithComment("gen/models.go:4", "expires 2026-10-01"); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]string{"gen/models.go:4 (expired 2026-10-01)"}, s.Expired()); d != "" {
+		t.Errorf("unexpected expired entries (-expected +got):\n%s", d)
+	}
+
+	for _, v := range []string{"models.go", "gen/models.go:4=", "models.go:x"} {
+		if err := s.Set(v); err == nil {
+			t.Errorf("expected an error setting %q", v)
+		}
+	}
+}
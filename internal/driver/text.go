@@ -0,0 +1,43 @@
+package driver
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// PrintText writes diags to w the way go vet does, except that the message
+// of anything less severe than an error is prefixed by its severity.  If
+// contextLines is nonnegative, it also prints the offending line, plus that
+// many lines of context before and after the line.
+func PrintText(w io.Writer, diags []Diagnostic, contextLines int) error {
+	for _, d := range diags {
+		posn := d.Position()
+		msg := d.Message
+		if d.Severity < Error {
+			msg = d.Severity.String() + ": " + msg
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s\n", posn, msg); err != nil {
+			return err
+		}
+
+		if contextLines < 0 {
+			continue
+		}
+		end := d.Fset.Position(d.End)
+		if !end.IsValid() {
+			end = posn
+		}
+		data, _ := os.ReadFile(posn.Filename)
+		lines := strings.Split(string(data), "\n")
+		for i := posn.Line - contextLines; i <= end.Line+contextLines; i++ {
+			if 1 <= i && i <= len(lines) {
+				if _, err := fmt.Fprintf(w, "%d\t%s\n", i, lines[i-1]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package driver
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// workspacePatterns expands each relative pattern (or ./... if there are
+// none) within every module used by the go.work workspace containing dir.
+// Other patterns, like import paths, are left as is.
+//
+// This is needed because go list resolves relative patterns against a
+// single module, so ./... at the root of a workspace matches nothing.
+func workspacePatterns(dir string, patterns []string) ([]string, error) {
+	cmd := exec.Command("go", "env", "GOWORK")
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go env GOWORK: %w: %s", err, stderr.String())
+	}
+	gowork := strings.TrimSpace(string(out))
+	if gowork == "" || gowork == "off" {
+		return nil, errors.New("not in a go.work workspace")
+	}
+
+	b, err := os.ReadFile(gowork)
+	if err != nil {
+		return nil, err
+	}
+	wf, err := modfile.ParseWork(gowork, b, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	var expanded []string
+	for _, p := range patterns {
+		if !strings.HasPrefix(p, ".") {
+			expanded = append(expanded, p)
+			continue
+		}
+		for _, use := range wf.Use {
+			mod := use.Path
+			if !filepath.IsAbs(mod) {
+				mod = filepath.Join(filepath.Dir(gowork), mod)
+			}
+			expanded = append(expanded, filepath.Join(mod, p))
+		}
+	}
+	return expanded, nil
+}
@@ -0,0 +1,188 @@
+// Package ignore implements the //splinter:ignore directive, which silences
+// the diagnostics within a call expression, and golangci-lint's //nolint,
+// which does the same here, and silences generated and test files.
+package ignore
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Directive is the comment silencing the diagnostics of a call, optionally
+// followed by the reason, as a trailing comment on the line the call ends
+// on or a comment on the line before the one it starts on:
+//
+//	logger.Log(dynamic...) //splinter:ignore the pairs are checked by NewPairs
+//
+//	//splinter:ignore legacy API, see #123
+//	logger.Log(dynamic...)
+const Directive = "//splinter:ignore"
+
+// Nolint is golangci-lint's directive, which is honored where it's bare or
+// names splinter, all, or the analyzer, as in //nolint:splinter,errcheck,
+// so that suppressions written for golangci-lint carry over.
+const Nolint = "//nolint"
+
+// isDirective returns true if text, a comment, is the directive, or a
+// //nolint directive applying to the analyzer named analyzer.
+func isDirective(text, analyzer string) bool {
+	if rest, ok := strings.CutPrefix(text, Directive); ok {
+		return rest == "" || rest[0] == ' ' || rest[0] == '\t'
+	}
+
+	rest, ok := strings.CutPrefix(text, Nolint)
+	if !ok {
+		return false
+	}
+	if rest == "" || rest[0] == ' ' || rest[0] == '\t' {
+		return true
+	}
+	names, ok := strings.CutPrefix(rest, ":")
+	if !ok {
+		return false
+	}
+	if i := strings.IndexAny(names, " \t"); i >= 0 {
+		names = names[:i]
+	}
+	for _, name := range strings.Split(names, ",") {
+		if name == "splinter" || name == "all" || name == analyzer {
+			return true
+		}
+	}
+	return false
+}
+
+type span struct{ pos, end token.Pos }
+
+// Filter returns p, or if any of its files have a directive, a copy of it
+// that drops the diagnostics starting within the calls the directives
+// apply to.
+func Filter(p *analysis.Pass) *analysis.Pass {
+	var spans []span
+	for _, f := range p.Files {
+		var directives []*ast.Comment
+		for _, cg := range f.Comments {
+			for _, c := range cg.List {
+				if isDirective(c.Text, p.Analyzer.Name) {
+					directives = append(directives, c)
+				}
+			}
+		}
+		if len(directives) == 0 {
+			continue
+		}
+
+		// the first position of code on each line, to tell trailing
+		// comments from those on a line of their own
+		code := map[int]token.Pos{}
+		mark := func(pos token.Pos) {
+			line := p.Fset.Position(pos).Line
+			if first, ok := code[line]; !ok || pos < first {
+				code[line] = pos
+			}
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n.(type) {
+			case nil:
+			case *ast.CommentGroup, *ast.Comment:
+				return false
+			default:
+				mark(n.Pos())
+				mark(n.End() - 1)
+			}
+			return true
+		})
+
+		// the lines the silenced calls end on, for trailing directives,
+		// or start on, for the others
+		ends, starts := map[int]bool{}, map[int]bool{}
+		for _, c := range directives {
+			line := p.Fset.Position(c.Slash).Line
+			if first, ok := code[line]; ok && first < c.Slash {
+				ends[line] = true
+			} else {
+				starts[line+1] = true
+			}
+		}
+
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			if ends[p.Fset.Position(call.End()).Line] || starts[p.Fset.Position(call.Pos()).Line] {
+				// calls within it are covered too
+				spans = append(spans, span{call.Pos(), call.End()})
+				return false
+			}
+			return true
+		})
+	}
+	if len(spans) == 0 {
+		return p
+	}
+
+	filtered := *p
+	filtered.Report = func(d analysis.Diagnostic) {
+		for _, s := range spans {
+			if s.pos <= d.Pos && d.Pos < s.end {
+				return
+			}
+		}
+		p.Report(d)
+	}
+	return &filtered
+}
+
+// Generated returns p, or if any of its files are generated, as marked by a
+// "// Code generated ... DO NOT EDIT." comment, a copy of it that drops the
+// diagnostics starting in them.
+func Generated(p *analysis.Pass) *analysis.Pass {
+	generated := map[*token.File]bool{}
+	for _, f := range p.Files {
+		if ast.IsGenerated(f) {
+			generated[p.Fset.File(f.FileStart)] = true
+		}
+	}
+	if len(generated) == 0 {
+		return p
+	}
+
+	filtered := *p
+	filtered.Report = func(d analysis.Diagnostic) {
+		if !generated[p.Fset.File(d.Pos)] {
+			p.Report(d)
+		}
+	}
+	return &filtered
+}
+
+// Tests returns p, or if any of its files are _test.go files, a copy of it
+// that drops the diagnostics starting in them.  An external test package,
+// named with a _test suffix, is returned as is unless external is true,
+// since it uses the package's API like any other importer.
+func Tests(p *analysis.Pass, external bool) *analysis.Pass {
+	if !external && strings.HasSuffix(p.Pkg.Name(), "_test") {
+		return p
+	}
+	tests := map[*token.File]bool{}
+	for _, f := range p.Files {
+		if tf := p.Fset.File(f.FileStart); strings.HasSuffix(tf.Name(), "_test.go") {
+			tests[tf] = true
+		}
+	}
+	if len(tests) == 0 {
+		return p
+	}
+
+	filtered := *p
+	filtered.Report = func(d analysis.Diagnostic) {
+		if !tests[p.Fset.File(d.Pos)] {
+			p.Report(d)
+		}
+	}
+	return &filtered
+}
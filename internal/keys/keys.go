@@ -0,0 +1,138 @@
+// Package keys classifies the expressions passed as keys to pair funcs for
+// the splinter analyzers.
+package keys
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+)
+
+// Kind is the classification of a key expression.
+type Kind int
+
+const (
+	// Unknown keys were not type checked.
+	Unknown Kind = iota
+
+	// Constant keys are constant strings, including conversions that
+	// fold to one (see FoldConversion); these are preferred.
+	Constant
+
+	// Expression keys are non-constant strings; these are not
+	// preferred, but are acceptable.
+	Expression
+
+	// NonStringConstant keys are constants of another type.
+	NonStringConstant
+
+	// NonStringExpression keys are expressions of another type.
+	NonStringExpression
+
+	// StringTypeParam keys are expressions whose type is a type parameter
+	// constrained to strings, like ~string; they are strings in every
+	// instantiation, so callers usually treat them like Expression keys.
+	StringTypeParam
+)
+
+// Classify returns the Kind of e, its type, and its value if it is a
+// Constant.
+func Classify(i *types.Info, e ast.Expr) (k Kind, t types.Type, value string) {
+	typ := i.Types[e]
+
+	if typ.Value != nil { // constant
+		if typ.Value.Kind() != constant.String {
+			return NonStringConstant, typ.Type, ""
+		}
+		return Constant, typ.Type, constant.StringVal(typ.Value)
+	}
+
+	if typ.Type == nil {
+		return Unknown, nil, ""
+	}
+	if v, ok := FoldConversion(i, e); ok {
+		return Constant, typ.Type, v
+	}
+
+	// expression
+	if isString(typ.Type) {
+		return Expression, typ.Type, ""
+	}
+	if tp, ok := typ.Type.(*types.TypeParam); ok && onlyStrings(tp.Constraint()) {
+		return StringTypeParam, typ.Type, ""
+	}
+	return NonStringExpression, typ.Type, ""
+}
+
+// FoldConversion returns the value of e if it's a conversion to a string
+// type of a byte or rune slice conversion of a constant string, like
+// string([]byte("key")), which is constant in all but name: the type checker
+// only folds conversions between constants.
+func FoldConversion(i *types.Info, e ast.Expr) (string, bool) {
+	outer, ok := conversion(i, e)
+	if !ok || !isString(i.Types[e].Type) {
+		return "", false
+	}
+	inner, ok := conversion(i, outer)
+	if !ok {
+		return "", false
+	}
+	slice, ok := i.Types[outer].Type.Underlying().(*types.Slice)
+	if !ok {
+		return "", false
+	}
+	if b, ok := slice.Elem().Underlying().(*types.Basic); !ok || (b.Kind() != types.Byte && b.Kind() != types.Rune) {
+		return "", false
+	}
+	v := i.Types[inner].Value
+	if v == nil || v.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(v), true
+}
+
+// conversion returns the operand of e, if it's a type conversion.
+func conversion(i *types.Info, e ast.Expr) (ast.Expr, bool) {
+	call, ok := ast.Unparen(e).(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 || !i.Types[call.Fun].IsType() {
+		return nil, false
+	}
+	return call.Args[0], true
+}
+
+func isString(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsString != 0
+}
+
+// onlyStrings returns true if every type in the type set of constraint is
+// a string.  The type set is the intersection of those of the embedded
+// elements, so it's enough for one of them to only allow strings.
+func onlyStrings(constraint types.Type) bool {
+	iface, ok := constraint.Underlying().(*types.Interface)
+	if !ok {
+		return false
+	}
+
+	for i := 0; i < iface.NumEmbeddeds(); i++ {
+		switch e := iface.EmbeddedType(i).(type) {
+		case *types.Union:
+			strings := true
+			for j := 0; j < e.Len(); j++ {
+				strings = strings && isString(e.Term(j).Type())
+			}
+			if strings {
+				return true
+			}
+		default:
+			if _, ok := e.Underlying().(*types.Interface); ok {
+				if onlyStrings(e) {
+					return true
+				}
+			} else if isString(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,19 @@
+// Package pkgpattern matches import paths against go list style patterns.
+package pkgpattern
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Regexp converts a go list style pattern, an import path that may contain
+// ... wildcards, to a regexp; like go list, a trailing /... also matches the
+// package without the slash.
+func Regexp(pattern string) *regexp.Regexp {
+	re := regexp.QuoteMeta(pattern)
+	re = strings.ReplaceAll(re, `\.\.\.`, `.*`)
+	if strings.HasSuffix(re, `/.*`) {
+		re = strings.TrimSuffix(re, `/.*`) + `(/.*)?`
+	}
+	return regexp.MustCompile(`^` + re + `$`)
+}
@@ -0,0 +1,28 @@
+// Package ruledoc links the diagnostics of the analyzers to the
+// documentation of their rules, so that tools like gopls and golangci-lint
+// can show where to learn more about a diagnostic.
+package ruledoc
+
+import "golang.org/x/tools/go/analysis"
+
+// URL is the page documenting every rule, with an anchor for each named by
+// the rule.
+const URL = "https://github.com/ZipRecruiter/splinter/blob/main/docs/rules.md"
+
+// Rule returns the URL of the documentation of rule.
+func Rule(rule string) string {
+	return URL + "#" + rule
+}
+
+// Link returns a copy of p that sets the URL of each diagnostic reported
+// without one to the documentation of the rule it's categorized by.
+func Link(p *analysis.Pass) *analysis.Pass {
+	linked := *p
+	linked.Report = func(d analysis.Diagnostic) {
+		if d.URL == "" && d.Category != "" {
+			d.URL = Rule(d.Category)
+		}
+		p.Report(d)
+	}
+	return &linked
+}
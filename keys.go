@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+
+	"github.com/ZipRecruiter/splinter/internal/config"
+	"github.com/ZipRecruiter/splinter/internal/driver"
+	"github.com/ZipRecruiter/splinter/pairs"
+)
+
+// inventory is the JSON form of the keys of a set of packages, as written
+// by `splinter keys`.
+type inventory struct {
+	Keys map[string]*inventoryKey `json:"keys"`
+}
+
+type inventoryKey struct {
+	Uses  int      `json:"uses"`
+	Types []string `json:"types"` // sorted
+}
+
+// keysCmd implements `splinter keys`, which writes the inventory of the
+// constant keys passed in the analyzed packages, from the pairs KeyInventory
+// facts, and `splinter keys diff`, which compares two inventories.
+func keysCmd(analyzers []*analysis.Analyzer, args []string) int {
+	if len(args) > 0 && args[0] == "diff" {
+		return keysDiff(args[1:])
+	}
+
+	fset := flag.NewFlagSet("keys", flag.ExitOnError)
+	fset.Usage = func() {
+		fmt.Fprintf(fset.Output(), "usage: splinter keys [analyzer flags] packages...\n")
+		fmt.Fprintf(fset.Output(), "       splinter keys diff old.json new.json\n\n")
+		fset.PrintDefaults()
+	}
+	heatmap := fset.Bool("heatmap", false, "write the uses of each key per package as CSV rows of key,package,uses instead of the inventory")
+	workspace := fset.Bool("workspace", false, "take stock of every module of the enclosing go.work workspace; relative patterns (default ./...) apply within each module")
+	shard := &driver.Shard{}
+	fset.Var(shard, "shard", "only take stock of shard i of N disjoint shards of the packages, as i/N; splinter merge-reports combines the inventories")
+	analyzerFlags(fset, analyzers)
+	config.Parse(fset, args)
+	fset.Set("inventory", "true")
+
+	pkgs, err := driver.Load(driver.LoadConfig{Tests: true, Workspace: *workspace}, fset.Args()...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter keys: %s\n", err)
+		return 1
+	}
+	graph, err := checker.Analyze(analyzers, shard.Packages(pkgs), nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter keys: %s\n", err)
+		return 1
+	}
+
+	byPkg := pairsFacts[pairs.KeyInventory](roots(graph))
+	if *heatmap {
+		if err := writeHeatmap(os.Stdout, byPkg); err != nil {
+			fmt.Fprintf(os.Stderr, "splinter keys: %s\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	var facts []*pairs.KeyInventory
+	for _, fact := range byPkg {
+		facts = append(facts, fact)
+	}
+	data, err := json.MarshalIndent(mergeInventories(facts), "", "\t")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter keys: %s\n", err)
+		return 1
+	}
+	os.Stdout.Write(append(data, '\n'))
+	return 0
+}
+
+// mergeInventories merges the keys of facts.
+func mergeInventories(facts []*pairs.KeyInventory) inventory {
+	inv := inventory{Keys: map[string]*inventoryKey{}}
+	types := map[string]map[string]bool{}
+	for _, fact := range facts {
+		for _, use := range fact.Keys {
+			k := inv.Keys[use.Key]
+			if k == nil {
+				k = &inventoryKey{Types: []string{}}
+				inv.Keys[use.Key] = k
+				types[use.Key] = map[string]bool{}
+			}
+			k.Uses += use.Uses
+			for t := range use.ValueTypes {
+				if !types[use.Key][t] {
+					types[use.Key][t] = true
+					k.Types = append(k.Types, t)
+				}
+			}
+		}
+	}
+	for _, k := range inv.Keys {
+		sort.Strings(k.Types)
+	}
+	return inv
+}
+
+// writeHeatmap writes the uses of each key in the facts of each package, by
+// import path, to w as CSV, sorted by key and then package, for charting
+// which parts of the code pass which keys the most.
+func writeHeatmap(w io.Writer, facts map[string]*pairs.KeyInventory) error {
+	type cell struct {
+		key, pkg string
+		uses     int
+	}
+	var cells []cell
+	for pkg, fact := range facts {
+		for _, use := range fact.Keys {
+			cells = append(cells, cell{use.Key, pkg, use.Uses})
+		}
+	}
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].key != cells[j].key {
+			return cells[i].key < cells[j].key
+		}
+		return cells[i].pkg < cells[j].pkg
+	})
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"key", "package", "uses"})
+	for _, c := range cells {
+		cw.Write([]string{c.key, c.pkg, strconv.Itoa(c.uses)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// keysDiff implements `splinter keys diff`, which exits with 1 if the
+// inventories differ.
+func keysDiff(args []string) int {
+	if len(args) != 2 {
+		fmt.Fprintf(os.Stderr, "usage: splinter keys diff old.json new.json\n")
+		return 2
+	}
+
+	var invs [2]inventory
+	for i, path := range args {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			err = json.Unmarshal(data, &invs[i])
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "splinter keys diff: %s\n", err)
+			return 2
+		}
+	}
+
+	if printKeysDiff(os.Stdout, invs[0], invs[1]) {
+		return 1
+	}
+	return 0
+}
+
+// printKeysDiff writes the keys removed from, added to and whose value
+// types changed between before and after to w, and returns true if there
+// were any.
+func printKeysDiff(w io.Writer, before, after inventory) bool {
+	changed := false
+	for _, key := range sortedKeys(before.Keys) {
+		if after.Keys[key] == nil {
+			fmt.Fprintf(w, "- %s (%s)\n", key, strings.Join(before.Keys[key].Types, ", "))
+			changed = true
+		}
+	}
+	for _, key := range sortedKeys(after.Keys) {
+		n, o := after.Keys[key], before.Keys[key]
+		switch {
+		case o == nil:
+			fmt.Fprintf(w, "+ %s (%s)\n", key, strings.Join(n.Types, ", "))
+		case strings.Join(o.Types, ",") != strings.Join(n.Types, ","):
+			fmt.Fprintf(w, "~ %s (%s -> %s)\n", key, strings.Join(o.Types, ", "), strings.Join(n.Types, ", "))
+		default:
+			continue
+		}
+		changed = true
+	}
+	return changed
+}
@@ -1,9 +1,105 @@
 package main
 
 import (
-	"golang.org/x/tools/go/analysis/singlechecker"
+	"fmt"
+	"os"
 
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/events"
+	"github.com/ZipRecruiter/splinter/internal/driver"
 	"github.com/ZipRecruiter/splinter/pairs"
 )
 
-func main() { singlechecker.Main(pairs.NewAnalyzer()) }
+func main() {
+	analyzers := []*analysis.Analyzer{
+		pairs.NewAnalyzer(),
+		events.NewAnalyzer(),
+	}
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "fix":
+			os.Exit(fix(analyzers, os.Args[2:]))
+		case "containers":
+			os.Exit(containers(analyzers, os.Args[2:]))
+		case "init":
+			os.Exit(initConfig(os.Args[2:]))
+		case "annotate":
+			os.Exit(annotate(analyzers, os.Args[2:]))
+		case "check-config":
+			os.Exit(checkConfig(analyzers, os.Args[2:]))
+		case "import-golangci":
+			os.Exit(importGolangci(os.Args[2:]))
+		case "migrate-config":
+			os.Exit(migrateConfig(os.Args[2:]))
+		case "keys":
+			os.Exit(keysCmd(analyzers, os.Args[2:]))
+		case "merge-reports":
+			os.Exit(mergeReports(os.Args[2:]))
+		case "sites":
+			os.Exit(sites(analyzers, os.Args[2:]))
+		case "rules":
+			os.Exit(rulesCmd(os.Args[2:]))
+		case "doctor":
+			os.Exit(doctor(analyzers, os.Args[2:]))
+		}
+	}
+
+	os.Exit(run(analyzers, os.Args[1:]))
+}
+
+// fixableRules returns the rules whose diagnostics suggest fixes, in order.
+func fixableRules() []string {
+	var names []string
+	for _, r := range pairs.Rules {
+		if pairs.RuleDocs[r].Fixable {
+			names = append(names, r)
+		}
+	}
+	for _, r := range events.Rules {
+		if events.RuleDocs[r].Fixable {
+			names = append(names, r)
+		}
+	}
+	return names
+}
+
+// warningRules returns the rules whose diagnostics are warnings by default.
+func warningRules() driver.RuleSet {
+	set := driver.RuleSet{}
+	for _, r := range pairs.Rules {
+		if pairs.RuleDocs[r].Warning {
+			set[r] = true
+		}
+	}
+	return set
+}
+
+// ruleCodes returns the stable codes of the rules, by rule.
+func ruleCodes() map[string]string {
+	codes := map[string]string{}
+	for _, r := range pairs.Rules {
+		codes[r] = pairs.RuleDocs[r].Code
+	}
+	for _, r := range events.Rules {
+		codes[r] = events.RuleDocs[r].Code
+	}
+	return codes
+}
+
+// validRules returns an error naming the first of set that isn't a rule or
+// the code of one.
+func validRules(set driver.RuleSet) error {
+	known := map[string]bool{}
+	for r, code := range ruleCodes() {
+		known[r] = true
+		known[code] = true
+	}
+	for r := range set {
+		if !known[r] {
+			return fmt.Errorf("unknown rule %q", r)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// mergeReports implements `splinter merge-reports`, which combines the
+// partial reports of runs over the shards of the packages, as selected by
+// -shard: either the -json reports of splinter, or the key inventories of
+// splinter keys, but not both.
+func mergeReports(args []string) int {
+	fset := flag.NewFlagSet("merge-reports", flag.ExitOnError)
+	fset.Usage = func() {
+		fmt.Fprintf(fset.Output(), "usage: splinter merge-reports [-o file] report.json...\n\n")
+		fset.PrintDefaults()
+	}
+	out := fset.String("o", "", "write the merged report to this file instead of stdout")
+	fset.Parse(args)
+
+	paths := fset.Args()
+	if len(paths) == 0 {
+		fset.Usage()
+		return 2
+	}
+
+	var (
+		reports = map[string]map[string]json.RawMessage{}
+		from    = map[string]string{} // the report of each package
+		invs    []inventory
+	)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "splinter merge-reports: %s\n", err)
+			return 2
+		}
+
+		if inv, ok := readInventory(data); ok {
+			invs = append(invs, inv)
+			continue
+		}
+		var report map[string]map[string]json.RawMessage
+		if err := json.Unmarshal(data, &report); err != nil {
+			fmt.Fprintf(os.Stderr, "splinter merge-reports: %s is neither a -json report nor a key inventory: %s\n", path, err)
+			return 2
+		}
+		for pkg, byAnalyzer := range report {
+			if prev, ok := from[pkg]; ok {
+				fmt.Fprintf(os.Stderr, "splinter merge-reports: package %s is in both %s and %s; were they run with different -shard flags?\n", pkg, prev, path)
+				return 2
+			}
+			from[pkg] = path
+			reports[pkg] = byAnalyzer
+		}
+	}
+	if len(invs) != 0 && len(from) != 0 {
+		fmt.Fprintf(os.Stderr, "splinter merge-reports: can't merge -json reports with key inventories\n")
+		return 2
+	}
+
+	var merged interface{} = reports
+	if len(invs) != 0 {
+		merged = mergeInventoryReports(invs)
+	}
+	data, err := json.MarshalIndent(merged, "", "\t")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter merge-reports: %s\n", err)
+		return 1
+	}
+	data = append(data, '\n')
+
+	if *out == "" {
+		os.Stdout.Write(data)
+	} else if err := os.WriteFile(*out, data, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "splinter merge-reports: %s\n", err)
+		return 1
+	}
+	return 0
+}
+
+// readInventory returns the key inventory in data, and false if it isn't
+// one.  Anything but its keys, like the packages of a -json report, is an
+// error.
+func readInventory(data []byte) (inventory, bool) {
+	var inv inventory
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&inv); err != nil || inv.Keys == nil {
+		return inventory{}, false
+	}
+	return inv, true
+}
+
+// mergeInventoryReports merges the keys of invs, adding up their uses.
+func mergeInventoryReports(invs []inventory) inventory {
+	merged := inventory{Keys: map[string]*inventoryKey{}}
+	types := map[string]map[string]bool{}
+	for _, inv := range invs {
+		for key, k := range inv.Keys {
+			m := merged.Keys[key]
+			if m == nil {
+				m = &inventoryKey{Types: []string{}}
+				merged.Keys[key] = m
+				types[key] = map[string]bool{}
+			}
+			m.Uses += k.Uses
+			for _, t := range k.Types {
+				if !types[key][t] {
+					types[key][t] = true
+					m.Types = append(m.Types, t)
+				}
+			}
+		}
+	}
+	for _, k := range merged.Keys {
+		sort.Strings(k.Types)
+	}
+	return merged
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestShardReports(t *testing.T) {
+	files := map[string]string{"log/log.go": logModule["log/log.go"]}
+	for i := 0; i < 8; i++ {
+		files[fmt.Sprintf("p%d/p.go", i)] = fmt.Sprintf("package p%d\n\nimport \"m/log\"\n\nfunc F(n int) {\n\tlog.Log(\"a\")\n\tlog.Log(n, 1)\n}\n", i)
+	}
+	dir := writeModule(t, files)
+
+	// the policy applies to each shard's report as it does to the whole
+	flags := []string{"-pair-func", "m/log.Log=0", "-json", "-disable", "odd-arity", "-exclude", "p3/**"}
+	whole, _ := runSplinter(t, append(flags, "./...")...)
+
+	var reports []string
+	for i := 1; i <= 3; i++ {
+		out, _ := runSplinter(t, append(flags, "-shard", fmt.Sprintf("%d/3", i), "./...")...)
+		if len(reportCategories(t, out)) == 0 {
+			t.Errorf("shard %d/3 reported nothing", i)
+		}
+		path := filepath.Join(dir, fmt.Sprintf("report-%d.json", i))
+		if err := os.WriteFile(path, []byte(out), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		reports = append(reports, path)
+	}
+	merged, code := captureStdout(t, func() int { return mergeReports(reports) })
+	if code != 0 {
+		t.Fatalf("splinter merge-reports exited with %d", code)
+	}
+
+	expected := reportCategories(t, whole)
+	if _, ok := expected["m/p3"]; ok || len(expected) != 7 {
+		t.Errorf("the policy wasn't applied to the whole report: %v", expected)
+	}
+	if d := cmp.Diff(expected, reportCategories(t, merged)); d != "" {
+		t.Errorf("unexpected merged report (-expected +got):\n%s", d)
+	}
+}
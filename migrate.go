@@ -0,0 +1,112 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ZipRecruiter/splinter/internal/config"
+	"github.com/ZipRecruiter/splinter/pairs"
+)
+
+// migrateConfig implements `splinter migrate-config`, which moves a
+// configuration file to a newer rules version, printing the defaults that
+// change, and optionally pinning the flags they affect to their old values.
+func migrateConfig(args []string) int {
+	fset := flag.NewFlagSet("migrate-config", flag.ExitOnError)
+	fset.Usage = func() {
+		fmt.Fprintf(fset.Output(), "usage: splinter migrate-config [-to version] [-keep-defaults] file\n\n")
+		fset.PrintDefaults()
+	}
+	to := fset.Int("to", pairs.LatestRulesVersion, "the rules version to migrate to")
+	keep := fset.Bool("keep-defaults", false, "set the flags whose defaults change, and that the file doesn't set, to their old defaults, so what's reported doesn't change")
+	fset.Parse(args)
+	if fset.NArg() != 1 {
+		fset.Usage()
+		return 2
+	}
+	path := fset.Arg(0)
+
+	entries, err := config.Read(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter migrate-config: %s\n", err)
+		return 1
+	}
+	from, line := 1, 0
+	set := map[string]bool{}
+	for _, e := range entries {
+		set[e.Flag] = true
+		if e.Flag == "rules-version" {
+			if from, err = strconv.Atoi(e.Value); err != nil {
+				fmt.Fprintf(os.Stderr, "splinter migrate-config: %s:%d: invalid rules-version %q\n", path, e.Line, e.Value)
+				return 1
+			}
+			line = e.Line
+		}
+	}
+	if *to < from || *to > pairs.LatestRulesVersion {
+		fmt.Fprintf(os.Stderr, "splinter migrate-config: can't migrate from rules version %d to %d\n", from, *to)
+		return 2
+	}
+
+	var pins []pairs.DefaultChange
+	changes := pairs.RulesVersionChanges(from, *to)
+	if len(changes) != 0 {
+		fmt.Printf("rules version %d -> %d changes the defaults of:\n", from, *to)
+	}
+	for _, d := range changes {
+		switch {
+		case set[d.Flag]:
+			fmt.Printf("\t%s, which %s sets\n", d, path)
+		case *keep:
+			fmt.Printf("\t%s, kept at %s\n", d, d.Old)
+			pins = append(pins, d)
+		default:
+			fmt.Printf("\t%s\n", d)
+		}
+	}
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter migrate-config: %s\n", err)
+		return 1
+	}
+	migrated := migratedConfig(string(src), filepath.Ext(path) == ".toml", line, *to, pins)
+	if err := os.WriteFile(path, []byte(migrated), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "splinter migrate-config: %s\n", err)
+		return 1
+	}
+	return 0
+}
+
+var rulesVersionLine = regexp.MustCompile(`^(\s*"?rules-version"?\s*[:=]\s*["']?)\d+`)
+
+// migratedConfig returns src, a YAML or TOML configuration file, with its
+// rules-version, on line if it has one, set to version, and the old
+// defaults of pins appended.
+func migratedConfig(src string, toml bool, line, version int, pins []pairs.DefaultChange) string {
+	entry := "%s: %s\n"
+	if toml {
+		entry = "%s = %s\n"
+	}
+
+	lines := strings.SplitAfter(src, "\n")
+	if line > 0 && line <= len(lines) {
+		lines[line-1] = rulesVersionLine.ReplaceAllString(lines[line-1], "${1}"+strconv.Itoa(version))
+	} else {
+		lines = append([]string{fmt.Sprintf(entry, "rules-version", strconv.Itoa(version))}, lines...)
+	}
+
+	migrated := strings.Join(lines, "")
+	if len(pins) != 0 && migrated != "" && !strings.HasSuffix(migrated, "\n") {
+		migrated += "\n"
+	}
+	for _, d := range pins {
+		migrated += fmt.Sprintf(entry, d.Flag, d.Old)
+	}
+	return migrated
+}
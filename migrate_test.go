@@ -0,0 +1,70 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ZipRecruiter/splinter/pairs"
+)
+
+func TestMigratedConfig(t *testing.T) {
+	pins := pairs.RulesVersionChanges(1, 2)
+	tests := []struct {
+		name     string
+		src      string
+		toml     bool
+		line     int
+		pins     []pairs.DefaultChange
+		expected string
+	}{
+		{
+			name:     "unpinned",
+			src:      "pair-func: example.com/log.Log=0\n",
+			expected: "rules-version: 2\npair-func: example.com/log.Log=0\n",
+		},
+		{
+			name:     "pinned",
+			src:      "# our config\nrules-version: 1 # pinned\npair-func: example.com/log.Log=0\n",
+			line:     2,
+			expected: "# our config\nrules-version: 2 # pinned\npair-func: example.com/log.Log=0\n",
+		},
+		{
+			name:     "quoted",
+			src:      "\"rules-version\": '1'\n",
+			line:     1,
+			expected: "\"rules-version\": '2'\n",
+		},
+		{
+			name:     "keep defaults",
+			src:      "rules-version: 1\npair-func: example.com/log.Log=0",
+			line:     1,
+			pins:     pins,
+			expected: "rules-version: 2\npair-func: example.com/log.Log=0\ntyped-nil-values: false\nconverted-keys: false\n",
+		},
+		{
+			name:     "toml",
+			src:      "rules-version = 1\n",
+			toml:     true,
+			line:     1,
+			pins:     pins[:1],
+			expected: "rules-version = 2\ntyped-nil-values = false\n",
+		},
+		{
+			name:     "toml unpinned",
+			src:      "pair-func = [\"example.com/log.Log=0\"]\n",
+			toml:     true,
+			expected: "rules-version = 2\npair-func = [\"example.com/log.Log=0\"]\n",
+		},
+		{
+			name:     "empty",
+			pins:     pins[1:],
+			expected: "rules-version: 2\nconverted-keys: false\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := migratedConfig(test.src, test.toml, test.line, 2, test.pins); got != test.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", test.expected, got)
+			}
+		})
+	}
+}
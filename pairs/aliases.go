@@ -0,0 +1,45 @@
+package pairs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// pathAliases maps the import paths of forks to the canonical paths that
+// selectors are written against, so that a fork is selected by the same
+// selectors as the package it forks.  It is a flag.Value accepting
+// <canonical>=<fork>; packages within the fork map to the packages within
+// the canonical path.
+type pathAliases map[string]string
+
+func (a pathAliases) Set(v string) error {
+	canonical, fork, ok := strings.Cut(v, "=")
+	if !ok || canonical == "" || fork == "" {
+		return fmt.Errorf("invalid alias %q; should be of form <canonical path>=<fork path>", v)
+	}
+	a[fork] = canonical
+	return nil
+}
+
+func (a pathAliases) String() string {
+	var s []string
+	for fork, canonical := range a {
+		s = append(s, canonical+"="+fork)
+	}
+	sort.Strings(s)
+	return strings.Join(s, ",")
+}
+
+// canonical returns the path selectors use for the package at path.
+func (a pathAliases) canonical(path string) string {
+	for fork, canonical := range a {
+		if path == fork {
+			return canonical
+		}
+		if rest, ok := strings.CutPrefix(path, fork+"/"); ok {
+			return canonical + "/" + rest
+		}
+	}
+	return path
+}
@@ -0,0 +1,59 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAliases(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import (
+	"forks/zap"
+	"forks/zap/sugar"
+)
+
+func Foo(l *zap.Logger, id int) {
+	l.Infow("saved", "user_id", id)
+	l.Infow("saved", id, "user_id") // want "arg 1 to method \\(\\*forks/zap.Logger\\) Infow\\(msg string, kv ...interface{}\\) is expression int but should be a constant string"
+	sugar.With("user_id") // want "1 args passed to forks/zap/sugar.With; must be even"
+}
+`,
+		"forks/zap/zap.go": `package zap
+
+type Logger struct{}
+
+func (*Logger) Infow(msg string, kv ...interface{}) {}
+`,
+		"forks/zap/sugar/sugar.go": `package sugar
+
+func With(kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for flag, v := range map[string]string{
+		"pair-func": "go.uber.org/zap.Logger.Infow=1",
+		"alias":     "go.uber.org/zap=forks/zap",
+	} {
+		if err := a.Flags.Set(flag, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := a.Flags.Set("pair-func", "go.uber.org/zap/sugar.With=0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Flags.Set("alias", "go.uber.org/zap"); err == nil {
+		t.Error("expected error for alias without a fork")
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
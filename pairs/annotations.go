@@ -0,0 +1,171 @@
+package pairs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// pairsDirective is the doc comment directive declaring a func or interface
+// method a pair func, optionally followed by offset=<offset>; without it,
+// the pairs are the variadic param.
+const pairsDirective = "//splinter:pairs"
+
+// assumePairDirective is the doc comment directive declaring a type a
+// container of pairs, as if passed to -assume-pair.
+const assumePairDirective = "//splinter:assume-pair"
+
+// AnnotatedFunc is an object fact marking a func, method or interface method
+// whose doc comment declares it a pair func with //splinter:pairs, with the
+// Offset of its pairs, so calls to it are checked in every package that
+// imports it without configuration.
+type AnnotatedFunc struct {
+	Offset int
+}
+
+// AFact implements analysis.Fact.
+func (*AnnotatedFunc) AFact() {}
+
+func (f *AnnotatedFunc) String() string {
+	return fmt.Sprintf("pairs=%d", f.Offset)
+}
+
+// AssumedPair is an object fact marking a type whose doc comment declares
+// it a container of pairs with //splinter:assume-pair, so it's assumed to
+// be one in every package that imports it without configuration.
+type AssumedPair struct{}
+
+// AFact implements analysis.Fact.
+func (*AssumedPair) AFact() {}
+
+func (*AssumedPair) String() string { return "assume-pair" }
+
+// cutDirective returns the rest of text, a comment, after directive, if it's
+// that directive.
+func cutDirective(text, directive string) (string, bool) {
+	rest, ok := strings.CutPrefix(text, directive)
+	if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return "", false
+	}
+	return rest, true
+}
+
+// parsePairsDirective returns the offset given by text, a //splinter:pairs
+// comment of a func with signature sig, if it's the directive.  err is set
+// if it's the directive but is malformed or doesn't fit sig.
+func parsePairsDirective(text string, sig *types.Signature) (offset int, ok bool, err error) {
+	rest, ok := cutDirective(text, pairsDirective)
+	if !ok {
+		return 0, false, nil
+	}
+	if !sig.Variadic() {
+		return 0, true, fmt.Errorf("%s on a func that isn't variadic", pairsDirective)
+	}
+
+	offset = sig.Params().Len() - 1
+	for _, field := range strings.Fields(rest) {
+		v, found := strings.CutPrefix(field, "offset=")
+		if !found {
+			return 0, true, fmt.Errorf("%s: unknown option %q; should be offset=<offset>", pairsDirective, field)
+		}
+		if offset, err = strconv.Atoi(v); err != nil || offset < 0 {
+			return 0, true, fmt.Errorf("%s: invalid offset %q", pairsDirective, v)
+		}
+	}
+	if offset >= sig.Params().Len() {
+		return 0, true, fmt.Errorf("%s: offset %d is past the variadic param, at %d", pairsDirective, offset, sig.Params().Len()-1)
+	}
+	return offset, true, nil
+}
+
+// exportAnnotated exports an AnnotatedFunc fact for each func, method and
+// interface method in the package whose doc comment has a //splinter:pairs
+// directive, and an AssumedPair fact for each type whose doc comment has a
+// //splinter:assume-pair directive, reporting those that are malformed.
+func (c *checker) exportAnnotated(p *analysis.Pass) {
+	assume := func(doc *ast.CommentGroup, id *ast.Ident) {
+		if doc == nil {
+			return
+		}
+		tn, ok := p.TypesInfo.Defs[id].(*types.TypeName)
+		if !ok {
+			return
+		}
+		for _, comment := range doc.List {
+			rest, ok := cutDirective(comment.Text, assumePairDirective)
+			switch {
+			case !ok:
+			case strings.TrimSpace(rest) != "":
+				c.report(p, comment, InvalidDirective, "%s takes no options, but was given %q", assumePairDirective, strings.TrimSpace(rest))
+			default:
+				p.ExportObjectFact(tn, &AssumedPair{})
+			}
+		}
+	}
+
+	export := func(doc *ast.CommentGroup, id *ast.Ident) {
+		if doc == nil {
+			return
+		}
+		fn, ok := p.TypesInfo.Defs[id].(*types.Func)
+		if !ok {
+			return
+		}
+		for _, comment := range doc.List {
+			offset, ok, err := parsePairsDirective(comment.Text, fn.Type().(*types.Signature))
+			switch {
+			case err != nil:
+				c.report(p, comment, InvalidDirective, "%s", err)
+			case ok:
+				p.ExportObjectFact(fn, &AnnotatedFunc{Offset: offset})
+			}
+		}
+	}
+
+	for _, f := range p.Files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncDecl:
+				export(n.Doc, n.Name)
+				return false
+			case *ast.GenDecl:
+				for _, spec := range n.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok {
+						// the doc of a lone spec is the decl's
+						doc := ts.Doc
+						if doc == nil && len(n.Specs) == 1 {
+							doc = n.Doc
+						}
+						assume(doc, ts.Name)
+					}
+				}
+			case *ast.InterfaceType:
+				for _, m := range n.Methods.List {
+					for _, id := range m.Names {
+						export(m.Doc, id)
+					}
+				}
+			}
+			return true
+		})
+	}
+}
+
+// annotatedOffset returns the offset of the pairs of the annotated func
+// called by call, if it calls one.
+func (c *checker) annotatedOffset(p *analysis.Pass, call *ast.CallExpr) (int, bool) {
+	fn, ok := typeutil.Callee(p.TypesInfo, call).(*types.Func)
+	if !ok {
+		return 0, false
+	}
+	var annotated AnnotatedFunc
+	if !p.ImportObjectFact(fn.Origin(), &annotated) {
+		return 0, false
+	}
+	return annotated.Offset, true
+}
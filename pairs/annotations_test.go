@@ -0,0 +1,116 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnnotatedFuncs(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import "a/log"
+
+// local is annotated in the package calling it
+//
+//splinter:pairs
+func local(kv ...interface{}) {} // want local:"pairs=0"
+
+func Foo(l log.Logger, id int) {
+	log.Log("saved", "user_id", id)
+	log.Log("saved", "user_id") // want "2 args passed to a/log.Log; must be even"
+	l.Infow("saved", id, "user_id") // want "arg 1 to method \\(a/log.Logger\\) Infow\\(msg string, kv ...interface{}\\) is expression int but should be a constant string"
+	log.Wrap(nil, "user_id") // want "2 args passed to a/log.Wrap; must be even"
+	local("user_id") // want "1 args passed to a.local; must be even"
+	local("user_id", id)
+}
+`,
+		"a/log/log.go": `package log
+
+// Log logs msg with the pairs.
+//
+//splinter:pairs offset=1
+func Log(msg string, kv ...interface{}) {} // want Log:"pairs=1"
+
+//splinter:pairs
+func Wrap(err error, kv ...interface{}) error { return err } // want Wrap:"pairs=1"
+
+type Logger interface {
+	//splinter:pairs offset=1
+	Infow(msg string, kv ...interface{}) // want Infow:"pairs=1"
+}
+
+//splinter:pairs // want "//splinter:pairs on a func that isn't variadic"
+func Fixed(msg string) {}
+
+//splinter:pairs offset=2 // want "offset 2 is past the variadic param, at 1"
+func Past(msg string, kv ...interface{}) {}
+
+//splinter:pairs start=1 // want "unknown option \"start=1\"; should be offset=<offset>"
+func Unknown(msg string, kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	// no -pair-func needed
+	analysistest.Run(t, dir, NewAnalyzer(), "a")
+}
+
+func TestAssumePairDirective(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import (
+	"a/details"
+	"a/log"
+)
+
+func Foo(p *details.Pairs, g details.Generic[int], o details.Other, id int) {
+	log.Log(p)
+	log.Log(g)
+	log.Log(o) // want "1 args passed to a/log.Log; must be even"
+	log.Log("id", p) // want "arg 1 to a/log.Log is a whitelisted type; should pass one or none"
+}
+`,
+		"a/details/details.go": `package details
+
+// Pairs holds key/value pairs.
+//
+//splinter:assume-pair
+type Pairs struct{} // want Pairs:"assume-pair"
+
+type (
+	//splinter:assume-pair
+	Generic[T any] struct{ v T } // want Generic:"assume-pair"
+
+	Other struct{}
+)
+
+//splinter:assume-pair with options // want "//splinter:assume-pair takes no options, but was given \"with options // want .*\""
+type Bad struct{}
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("pair-func", "a/log.Log=0"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
@@ -0,0 +1,67 @@
+package pairs
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+	analysischecker "golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+// benchmarkRun analyzes a package of many pair func calls, half of them
+// broken, with an analyzer given flags.
+func benchmarkRun(b *testing.B, flags map[string]string) {
+	var src strings.Builder
+	src.WriteString("package a\n\ntype Logger interface{ Log(kv ...interface{}) }\n\nfunc Foo(l Logger, id int, name string) {\n")
+	for i := 0; i < 500; i++ {
+		fmt.Fprintf(&src, "\tl.Log(\"user_id\", id, \"name\", name, \"n\", %d)\n", i)
+		fmt.Fprintf(&src, "\tl.Log(id, \"user_id\", name, \"name\", \"n\")\n")
+	}
+	src.WriteString("}\n")
+
+	dir, cleanup, err := analysistest.WriteFiles(map[string]string{"a/a.go": src.String()})
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer cleanup()
+
+	b.Setenv("GOPATH", dir)
+	b.Setenv("GO111MODULE", "off")
+	b.Setenv("GOFLAGS", "")
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.LoadAllSyntax, Dir: dir + "/src"}, "a")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("pair-func", ".Log=0"); err != nil {
+		b.Fatal(err)
+	}
+	for flag, v := range flags {
+		if err := a.Flags.Set(flag, v); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := analysischecker.Analyze([]*analysis.Analyzer{a}, pkgs, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRun(b *testing.B) {
+	benchmarkRun(b, nil)
+}
+
+func BenchmarkRunWhitelisted(b *testing.B) {
+	benchmarkRun(b, map[string]string{"assume-pair": "a.Logger"})
+}
+
+func BenchmarkRunCoverage(b *testing.B) {
+	benchmarkRun(b, map[string]string{"coverage": "true"})
+}
@@ -0,0 +1,161 @@
+package pairs
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/internal/keys"
+)
+
+// builderKeys records the constant keys added to each builder in a package,
+// keyed by the local variable holding the builder, its heldBuilder if it's
+// held in a field of one or, for a chain of calls on an unnamed builder, the
+// call at the root of the chain.
+type builderKeys map[interface{}]map[string]token.Pos
+
+// heldBuilder identifies a builder held in a field of a local variable, like
+// req.details or a.b.c.builder, by the variable and the path of the fields.
+type heldBuilder struct {
+	v    *types.Var
+	path string
+}
+
+func (c *checker) isBuilderFunc(sels []funcSelector) bool {
+	_, ok := c.builderSelector(sels)
+	return ok
+}
+
+// builderSelector returns the most precise of sels passed to -builder-func.
+func (c *checker) builderSelector(sels []funcSelector) (funcSelector, bool) {
+	for i := len(sels) - 1; i >= 0; i-- {
+		if c.builderFuncs[sels[i]] {
+			return sels[i], true
+		}
+	}
+	return funcSelector{}, false
+}
+
+// pairsOverBuilder reports whether a call selected by sels, and by sel as a
+// pair func, is checked as a pair func rather than as a builder func, so
+// that a call both select is checked once: as whichever is more precise,
+// and as a pair func if they're equally so.
+func (c *checker) pairsOverBuilder(sels []funcSelector, sel funcSelector) bool {
+	b, ok := c.builderSelector(sels)
+	return !ok || slices.Index(sels, sel) >= slices.Index(sels, b)
+}
+
+// builder returns the identity of the builder that call, to a builder func,
+// adds to.  ok is false if the builder can't be tracked, like a package
+// variable, which may be added to by other funcs, or a field more than
+// -receiver-depth fields away from a local variable.
+func (c *checker) builder(p *analysis.Pass, call *ast.CallExpr) (b interface{}, ok bool) {
+	for {
+		s, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+		if !ok {
+			return nil, false
+		}
+
+		switch x := ast.Unparen(s.X).(type) {
+		case *ast.Ident:
+			v, ok := localVar(p, x)
+			return v, ok
+		case *ast.SelectorExpr:
+			return c.heldBuilder(p, x)
+		case *ast.CallExpr:
+			if sels, _, ok := c.callSelectors(p.TypesInfo, x); ok && c.isBuilderFunc(sels) {
+				call = x
+				continue
+			}
+			return x, true
+		default:
+			return nil, false
+		}
+	}
+}
+
+// heldBuilder returns the heldBuilder of the builder in the field x selects,
+// resolving the fields it's selected through one at a time, up to
+// -receiver-depth of them, back to a local variable.
+func (c *checker) heldBuilder(p *analysis.Pass, x *ast.SelectorExpr) (heldBuilder, bool) {
+	var path []string
+	var e ast.Expr = x
+	for s, ok := x, true; ok; s, ok = e.(*ast.SelectorExpr) {
+		if len(path) == c.receiverDepth {
+			return heldBuilder{}, false
+		}
+		if sel, ok := p.TypesInfo.Selections[s]; !ok || sel.Kind() != types.FieldVal {
+			return heldBuilder{}, false
+		}
+		path = append([]string{s.Sel.Name}, path...)
+		e = ast.Unparen(s.X)
+	}
+
+	id, ok := e.(*ast.Ident)
+	if !ok {
+		return heldBuilder{}, false
+	}
+	v, ok := localVar(p, id)
+	if !ok {
+		return heldBuilder{}, false
+	}
+	return heldBuilder{v, strings.Join(path, ".")}, true
+}
+
+// localVar returns the local variable id refers to, if it refers to one.
+func localVar(p *analysis.Pass, id *ast.Ident) (*types.Var, bool) {
+	v, ok := p.TypesInfo.Uses[id].(*types.Var)
+	if !ok || v.Parent() == nil || v.Parent() == v.Pkg().Scope() {
+		return nil, false
+	}
+	return v, true
+}
+
+// builderCorrect checks call, to the builder func name, which adds a single
+// pair.  With -duplicate-keys, constant keys already added to the same
+// builder are reported too.
+func (c *checker) builderCorrect(p *analysis.Pass, name string, call *ast.CallExpr, added builderKeys) {
+	if len(call.Args) != 2 {
+		return
+	}
+
+	// unlike the pairs of a pair func, where string variables are
+	// tolerated, the key of a builder must be a constant: the arg is
+	// usually typed string, so anything else would go unchecked
+	kind, typ, key := c.classify(p, call.Args[0])
+	if kind == keys.Expression || kind == keys.StringTypeParam {
+		c.report(p, call.Args[0], ExpressionKey, "arg 0 to %s is expression %s but should be a constant string",
+			name,
+			types.TypeString(typ, nil),
+		)
+	} else {
+		c.keyCorrect(p, name, 0, call.Args[0], call.Args[1])
+	}
+	c.valueCorrect(p, name, 1, call.Args[1])
+
+	if !c.duplicateKeys || kind != keys.Constant {
+		return
+	}
+	b, ok := c.builder(p, call)
+	if !ok {
+		return
+	}
+
+	key = c.keyAliases.canonical(key)
+	if added[b] == nil {
+		added[b] = map[string]token.Pos{}
+	}
+	if prev, ok := added[b][key]; ok {
+		c.report(p, call.Args[0], DuplicateKey, "key %q passed to %s was already added on line %d",
+			key,
+			name,
+			p.Fset.Position(prev).Line,
+		)
+		return
+	}
+	added[b][key] = call.Args[0].Pos()
+}
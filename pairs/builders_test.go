@@ -0,0 +1,68 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestBuilders(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import "a/details"
+
+var global = details.New()
+
+func Foo(name string) *details.Builder {
+	b := details.New()
+	b.Add("id", 1)
+	b.Add(name, "frew") // want "arg 0 to method \\(\\*a/details.Builder\\) Add\\(k string, v interface{}\\) \\*a/details.Builder is expression string but should be a constant string"
+	b.Add("id", 2) // want "key \"id\" passed to method \\(\\*a/details.Builder\\) Add\\(k string, v interface{}\\) \\*a/details.Builder was already added on line 9"
+
+	details.New().Add("job", 1).Add("job", 2) // want "key \"job\" passed to .* was already added on line 13"
+	details.New().Add("job", 1)
+
+	// builders outside the func can't be tracked
+	global.Add("id", 1)
+	global.Add("id", 2)
+
+	return b
+}
+
+func Bar(b *details.Builder) {
+	b.Add("id", 1)
+	func() {
+		b.Add("id", 2) // want "key \"id\" passed to .* was already added on line 24"
+	}()
+}
+`,
+		"a/details/details.go": `package details
+
+type Builder struct{ kv []interface{} }
+
+func New() *Builder { return &Builder{} }
+
+func (b *Builder) Add(k string, v interface{}) *Builder {
+	b.kv = append(b.kv, k, v)
+	return b
+}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("builder-func", "a/details.Builder.Add"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Flags.Set("duplicate-keys", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
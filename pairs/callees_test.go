@@ -0,0 +1,78 @@
+package pairs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestIgnoreCalleeRules(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import (
+	"a/log"
+	"a/thirdparty/legacy"
+)
+
+func Foo(id int) {
+	log.Log("id") // want "1 args passed to a/log.Log; must be even"
+	log.Log(1, id) // want "arg 0 to a/log.Log is constant int but should be a constant string"
+
+	// legacy.Log takes a trailing message, so arity is ignored but keys
+	// are still checked
+	legacy.Log("id", id, "done")
+	legacy.Log(1, id, "done") // want "arg 0 to a/thirdparty/legacy.Log is constant int but should be a constant string"
+}
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+`,
+		"a/thirdparty/legacy/legacy.go": `package legacy
+
+func Log(kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("pair-func", "a/log.Log=0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Flags.Set("pair-func", "a/thirdparty/legacy.Log=0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Flags.Set("ignore-callee-rules", "a/thirdparty/...=odd-arity,whitelisted-type"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Flags.Set("ignore-callee-rules", "a/thirdparty/legacy=nope"); err == nil {
+		t.Error("expected error for unknown rule")
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
+
+func TestCalleeRulesCodes(t *testing.T) {
+	var r calleeRules
+	if err := r.Set("example.com/legacy/...=SPL001,non-string-key"); err != nil {
+		t.Fatal(err)
+	}
+	expected := ruleSet{OddArity: true, NonStringKey: true}
+	if d := cmp.Diff(expected, r.ignored("example.com/legacy/log")); d != "" {
+		t.Errorf("unexpected ignored rules (-expected +got):\n%s", d)
+	}
+	if got := r.String(); got != "example.com/legacy/...=SPL001,non-string-key" {
+		t.Errorf("String() = %q; expected the value as given", got)
+	}
+
+	if err := r.Set("example.com/legacy=SPL999"); err == nil {
+		t.Error("expected error for unknown code")
+	}
+}
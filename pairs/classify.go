@@ -0,0 +1,92 @@
+package pairs
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/internal/keys"
+)
+
+// ArgClass classifies an arg of a call the analyzer checked.
+type ArgClass int
+
+const (
+	// ArgOther is an arg before the pairs that isn't a message, like a
+	// context.
+	ArgOther ArgClass = iota
+
+	// ArgMessage is an arg of type string before the pairs, like the
+	// message of a log call.
+	ArgMessage
+
+	// ArgKeyConstant is a key that's a constant string.
+	ArgKeyConstant
+
+	// ArgKeyExpression is a key that's anything but a constant string,
+	// whether or not it's reported.
+	ArgKeyExpression
+
+	// ArgValue is the value of a pair.
+	ArgValue
+
+	// ArgContainer holds pairs of its own: an -assume-pair type, or a
+	// slice spread as the pairs.
+	ArgContainer
+)
+
+var argClassNames = []string{"other", "message", "key-constant", "key-expression", "value", "container"}
+
+func (a ArgClass) String() string {
+	if a < 0 || int(a) >= len(argClassNames) {
+		return "unknown"
+	}
+	return argClassNames[a]
+}
+
+// CheckedCalls is the result of the analyzer: the calls of pair funcs and
+// builder funcs it checked in a package, so that tools built on it, like
+// editor plugins rendering key: and val: hints over the pairs, resolve them
+// exactly as it does.
+type CheckedCalls struct {
+	args map[*ast.CallExpr][]ArgClass
+}
+
+// ClassifyArgs returns the class of each arg of call, a call in the
+// package calls is the result for.  ok is false if the analyzer didn't
+// check call.
+func ClassifyArgs(calls *CheckedCalls, call *ast.CallExpr) (classes []ArgClass, ok bool) {
+	if calls == nil {
+		return nil, false
+	}
+	classes, ok = calls.args[call]
+	return classes, ok
+}
+
+// add records the classes of the args of call, whose pairs start at
+// offset.
+func (s *CheckedCalls) add(c *checker, p *analysis.Pass, offset int, call *ast.CallExpr) {
+	classes := make([]ArgClass, len(call.Args))
+	for i, a := range call.Args {
+		switch {
+		case i < offset:
+			if t := p.TypesInfo.TypeOf(a); t != nil {
+				if b, ok := t.Underlying().(*types.Basic); ok && b.Info()&types.IsString != 0 {
+					classes[i] = ArgMessage
+				}
+			}
+		case call.Ellipsis.IsValid() || c.isWhitelisted(p, a):
+			classes[i] = ArgContainer
+		case (i-offset)%2 != 0:
+			classes[i] = ArgValue
+		default:
+			if kind, _, _ := keys.Classify(p.TypesInfo, a); kind == keys.Constant {
+				classes[i] = ArgKeyConstant
+			} else {
+				classes[i] = ArgKeyExpression
+			}
+		}
+	}
+	s.args[call] = classes
+}
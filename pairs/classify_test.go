@@ -0,0 +1,92 @@
+package pairs
+
+import (
+	"go/ast"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestClassifyArgs(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import (
+	"context"
+
+	"a/log"
+)
+
+const key = "id"
+
+func Foo(ctx context.Context, b *log.Builder, f log.Fields, kv []interface{}, k string, id int) {
+	log.Info(ctx, "msg", key, id, k, 1)
+	log.Info(ctx, "msg", f)
+	log.Info(ctx, "msg", kv...)
+	b.Add("id", id)
+	log.Other("id", id)
+}
+`,
+		"a/log/log.go": `package log
+
+import "context"
+
+func Info(ctx context.Context, msg string, kv ...interface{}) {}
+
+func Other(kv ...interface{}) {}
+
+type Fields map[string]interface{}
+
+type Builder struct{}
+
+func (b *Builder) Add(k string, v interface{}) *Builder { return b }
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for _, f := range [][2]string{
+		{"pair-func", "a/log.Info=2"},
+		{"assume-pair", "a/log.Fields"},
+		{"builder-func", "a/log.Builder.Add"},
+	} {
+		if err := a.Flags.Set(f[0], f[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	results := analysistest.Run(t, dir, a, "a")
+	calls := results[0].Result.(*CheckedCalls)
+
+	var got [][]string
+	for _, f := range results[0].Pass.Files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			if classes, ok := ClassifyArgs(calls, call); ok {
+				var s []string
+				for _, c := range classes {
+					s = append(s, c.String())
+				}
+				got = append(got, s)
+			}
+			return true
+		})
+	}
+	expected := [][]string{
+		{"other", "message", "key-constant", "value", "key-expression", "value"},
+		{"other", "message", "container"},
+		{"other", "message", "container"},
+		{"key-constant", "value"},
+	}
+	if d := cmp.Diff(expected, got); d != "" {
+		t.Errorf("unexpected classes (-expected +got):\n%s", d)
+	}
+}
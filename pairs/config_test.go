@@ -0,0 +1,57 @@
+package pairs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestConfig(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import (
+	"a/details"
+	"a/log"
+)
+
+func Foo(p *details.Pairs) {
+	log.Log("a", 1, 2) // want "3 args passed to a/log.Log; must be even"
+	log.Info("msg", "a", 1, 2) // want "4 args passed to a/log.Info; must be even"
+	log.Log(p)
+}
+`,
+		"a/details/details.go": `package details
+
+type Pairs struct{}
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+
+func Info(msg string, kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	path := filepath.Join(t.TempDir(), "splinter.toml")
+	src := "pair-func = [\"a/log.Log=0\", \"a/log.Info=0\"]\nassume-pair = \"a/details.Pairs\"\n"
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// flags after -config override it
+	a := NewAnalyzer()
+	if err := a.Flags.Parse([]string{"-config", path, "-pair-func", "a/log.Info=1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
@@ -0,0 +1,224 @@
+package pairs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/internal/calls"
+	"github.com/ZipRecruiter/splinter/internal/keys"
+)
+
+// ContainerFeed summarizes the calls to one pair func that fed pairs into a
+// whitelisted container, either by returning the container (constructors)
+// or by being one of its methods.
+type ContainerFeed struct {
+	Container string // the whitelisted type, as pkg.Type
+	Callee    string
+
+	Calls        int
+	ConstantKeys int
+	OtherKeys    int
+
+	// ValueTypes counts the values passed by type.
+	ValueTypes map[string]int
+}
+
+// ContainerUsage is a package fact recording the calls in the package that
+// fed whitelisted containers, so drivers can tell whether the "safe"
+// abstraction is actually being used safely.
+type ContainerUsage struct {
+	Feeds []ContainerFeed // sorted by Container then Callee
+}
+
+// AFact implements analysis.Fact.
+func (*ContainerUsage) AFact() {}
+
+func (u *ContainerUsage) String() string {
+	var s []string
+	for _, f := range u.Feeds {
+		s = append(s, fmt.Sprintf("%s<-%s:%d/%d/%d", f.Container, f.Callee, f.Calls, f.ConstantKeys, f.OtherKeys))
+	}
+	return strings.Join(s, " ")
+}
+
+type containerFeeds map[[2]string]*ContainerFeed
+
+// container returns the whitelisted container fed by call, if any.
+func (c *checker) container(p *analysis.Pass, call *ast.CallExpr) (string, bool) {
+	t := p.TypesInfo.TypeOf(call)
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if c.whitelisted(p, t) {
+		named := t.(*types.Named)
+		return calls.PkgPath(named.Obj().Pkg()) + "." + named.Obj().Name(), true
+	}
+
+	if s, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr); ok {
+		if nv, ok := p.TypesInfo.Selections[s]; ok {
+			recv := nv.Recv()
+			if ptr, ok := recv.(*types.Pointer); ok {
+				recv = ptr.Elem()
+			}
+			if c.whitelisted(p, recv) {
+				named := recv.(*types.Named)
+				return calls.PkgPath(named.Obj().Pkg()) + "." + named.Obj().Name(), true
+			}
+		}
+	}
+	return "", false
+}
+
+// add records call, to name with pairs starting at offset, if it feeds a
+// whitelisted container.
+func (f containerFeeds) add(c *checker, p *analysis.Pass, name string, offset int, call *ast.CallExpr) {
+	container, ok := c.container(p, call)
+	if !ok || len(call.Args) <= offset {
+		return
+	}
+	if len(call.Args)-offset == 1 && c.isWhitelisted(p, call.Args[offset]) {
+		return // passing the container along, not feeding it
+	}
+
+	feed := f[[2]string{container, name}]
+	if feed == nil {
+		feed = &ContainerFeed{Container: container, Callee: name, ValueTypes: map[string]int{}}
+		f[[2]string{container, name}] = feed
+	}
+	feed.Calls++
+
+	for i, a := range call.Args[offset:] {
+		if i%2 == 0 {
+			if kind, _, _ := keys.Classify(p.TypesInfo, a); kind == keys.Constant {
+				feed.ConstantKeys++
+			} else {
+				feed.OtherKeys++
+			}
+			continue
+		}
+		if t := p.TypesInfo.TypeOf(a); t != nil {
+			feed.ValueTypes[types.TypeString(types.Default(t), nil)]++
+		}
+	}
+}
+
+// export exports the feeds as a ContainerUsage fact, if there are any.
+func (f containerFeeds) export(p *analysis.Pass) {
+	if len(f) == 0 {
+		return
+	}
+
+	u := &ContainerUsage{}
+	for _, feed := range f {
+		u.Feeds = append(u.Feeds, *feed)
+	}
+	sort.Slice(u.Feeds, func(i, j int) bool {
+		if u.Feeds[i].Container != u.Feeds[j].Container {
+			return u.Feeds[i].Container < u.Feeds[j].Container
+		}
+		return u.Feeds[i].Callee < u.Feeds[j].Callee
+	})
+	p.ExportPackageFact(u)
+}
+
+// containerSpread describes the pairs spread into a pair func when they
+// come from a whitelisted container's accessor, possibly along with raw
+// pairs.
+type containerSpread struct {
+	accessor string // the name of the accessor
+	raw      []ast.Expr
+
+	// appended is the append call when the raw pairs are appended to the
+	// slice returned by the accessor, rather than the other way around.
+	appended *ast.CallExpr
+}
+
+func (c *checker) isContainerAccessor(p *analysis.Pass, e ast.Expr) (string, bool) {
+	call, ok := ast.Unparen(e).(*ast.CallExpr)
+	if !ok {
+		return "", false
+	}
+	sels, name, ok := c.callSelectors(p.TypesInfo, call)
+	if !ok {
+		return "", false
+	}
+	for _, sel := range sels {
+		if c.containerAccessors[sel] {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func isAppend(p *analysis.Pass, call *ast.CallExpr) bool {
+	id, ok := ast.Unparen(call.Fun).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	b, ok := p.TypesInfo.Uses[id].(*types.Builtin)
+	return ok && b.Name() == "append"
+}
+
+// spread returns the pairs spread into call, whose pairs start at offset,
+// if they're one of the forms:
+//
+//	c.Values()...
+//	append(c.Values(), "k", v)...
+//	append([]interface{}{"k", v}, c.Values()...)...
+//
+// where Values is a -container-accessor.
+func (c *checker) spread(p *analysis.Pass, offset int, call *ast.CallExpr) (containerSpread, bool) {
+	if len(c.containerAccessors) == 0 || len(call.Args) != offset+1 {
+		return containerSpread{}, false
+	}
+	arg := ast.Unparen(call.Args[offset])
+
+	if name, ok := c.isContainerAccessor(p, arg); ok {
+		return containerSpread{accessor: name}, true
+	}
+
+	app, ok := arg.(*ast.CallExpr)
+	if !ok || !isAppend(p, app) || len(app.Args) < 2 {
+		return containerSpread{}, false
+	}
+	if !app.Ellipsis.IsValid() {
+		if name, ok := c.isContainerAccessor(p, app.Args[0]); ok {
+			return containerSpread{accessor: name, raw: app.Args[1:], appended: app}, true
+		}
+		return containerSpread{}, false
+	}
+	lit, ok := ast.Unparen(app.Args[0]).(*ast.CompositeLit)
+	if !ok || len(app.Args) != 2 {
+		return containerSpread{}, false
+	}
+	if name, ok := c.isContainerAccessor(p, app.Args[1]); ok {
+		return containerSpread{accessor: name, raw: lit.Elts}, true
+	}
+	return containerSpread{}, false
+}
+
+// spreadCorrect checks the raw pairs spread into a call to name along with
+// a container's pairs, numbering them from the start of the raw pairs, and
+// reports raw pairs appended to the accessor's slice, since append may write
+// them into the container's own backing array.
+func (c *checker) spreadCorrect(p *analysis.Pass, name string, s containerSpread) {
+	if s.appended != nil {
+		c.report(p, s.appended, ContainerSpread, "pairs appended to the slice returned by %s may overwrite the container's pairs; append the container's pairs to the raw ones instead", s.accessor)
+	}
+	if len(s.raw)%2 != 0 {
+		c.report(p, s.raw[len(s.raw)-1], OddArity, "%d raw args spread with the pairs from %s into %s; must be even", len(s.raw), s.accessor, name)
+		return
+	}
+	for i, a := range s.raw {
+		if i%2 != 0 {
+			c.valueCorrect(p, name, i, a)
+			continue
+		}
+		c.keyCorrect(p, name, i, a, s.raw[i+1])
+	}
+}
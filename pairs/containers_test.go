@@ -0,0 +1,132 @@
+package pairs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestContainerUsage(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a // want package:"a/details.Pairs<-a/details.New:2/2/1 a/details.Pairs<-method \\(\\*a/details.Pairs\\) Add\\(kv ...interface{}\\):1/1/0"
+
+import "a/details"
+
+func Foo(key string, err error) {
+	p := details.New("id", 1, "name", "frew")
+	p = details.New(key, err)
+	p.Add("count", 2)
+
+	// passing the container along doesn't feed it
+	q := details.New(p)
+	_ = q
+
+	// neither do pair funcs unrelated to containers
+	details.Log("id", 1)
+}
+`,
+		"a/details/details.go": `package details
+
+type Pairs struct{ kv []interface{} }
+
+func New(kv ...interface{}) *Pairs { return &Pairs{kv} }
+
+func (p *Pairs) Add(kv ...interface{}) { p.kv = append(p.kv, kv...) }
+
+func Log(kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for _, f := range []string{"a/details.New=0", "a/details.Pairs.Add=0", "a/details.Log=0"} {
+		if err := a.Flags.Set("pair-func", f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := a.Flags.Set("assume-pair", "a/details.Pairs"); err != nil {
+		t.Fatal(err)
+	}
+
+	results := analysistest.Run(t, dir, a, "a")
+
+	usage := results[0].Facts[nil][0].(*ContainerUsage)
+	expected := []ContainerFeed{{
+		Container:    "a/details.Pairs",
+		Callee:       "a/details.New",
+		Calls:        2,
+		ConstantKeys: 2,
+		OtherKeys:    1,
+		ValueTypes:   map[string]int{"int": 1, "string": 1, "error": 1},
+	}, {
+		Container:    "a/details.Pairs",
+		Callee:       "method (*a/details.Pairs) Add(kv ...interface{})",
+		Calls:        1,
+		ConstantKeys: 1,
+		ValueTypes:   map[string]int{"int": 1},
+	}}
+	if d := cmp.Diff(expected, usage.Feeds); d != "" {
+		t.Errorf("unexpected feeds (-expected +got):\n%s", d)
+	}
+}
+
+func TestContainerSpread(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import "a/details"
+
+func Foo(p *details.Pairs, kv []interface{}, name string) {
+	details.Log(p.Values()...)
+	details.Log(append([]interface{}{"id", 1}, p.Values()...)...)
+	details.Log(append([]interface{}{"id", 1, "job"}, p.Values()...)...) // want "3 raw args spread with the pairs from method \\(\\*a/details.Pairs\\) Values\\(\\) \\[\\]interface{} into a/details.Log; must be even"
+	details.Log(append([]interface{}{"id", 1, 2, name}, p.Values()...)...) // want "arg 2 to a/details.Log is constant int but should be a constant string"
+	details.Log(append(p.Values(), "id", 1)...) // want "pairs appended to the slice returned by method \\(\\*a/details.Pairs\\) Values\\(\\) \\[\\]interface{} may overwrite the container's pairs; append the container's pairs to the raw ones instead"
+	details.Wrap(nil, append([]interface{}{"id"}, p.Values()...)...) // want "1 raw args spread .*"
+
+	// not from an accessor, so not checked
+	details.Log(kv...)
+	details.Log(append(kv, "id")...)
+}
+`,
+		"a/details/details.go": `package details
+
+type Pairs struct{ kv []interface{} }
+
+func (p *Pairs) Values() []interface{} { return p.kv }
+
+func Log(kv ...interface{}) {}
+
+func Wrap(err error, kv ...interface{}) error { return err }
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for _, f := range []string{"a/details.Log=0", "a/details.Wrap=1"} {
+		if err := a.Flags.Set("pair-func", f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for flag, v := range map[string]string{
+		"assume-pair":        "a/details.Pairs",
+		"container-accessor": "a/details.Pairs.Values",
+	} {
+		if err := a.Flags.Set(flag, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
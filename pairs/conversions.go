@@ -0,0 +1,49 @@
+package pairs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/internal/keys"
+)
+
+// conversionCorrect reports key, the constant at arg i of a call to name,
+// if it's passed through a conversion that doesn't change it, like
+// string([]byte("key")) or string(constant) of a constant of the same type, suggesting a fix that
+// passes the constant itself.
+func (c *checker) conversionCorrect(p *analysis.Pass, name string, i int, a ast.Expr, key string) {
+	call, ok := ast.Unparen(a).(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 || !p.TypesInfo.Types[call.Fun].IsType() {
+		return
+	}
+
+	var replacement string
+	if _, ok := keys.FoldConversion(p.TypesInfo, call); ok {
+		replacement = strconv.Quote(key)
+	} else if tv := p.TypesInfo.Types[call.Args[0]]; tv.Value != nil && types.Identical(tv.Type, p.TypesInfo.TypeOf(call)) {
+		var b strings.Builder
+		if err := format.Node(&b, p.Fset, call.Args[0]); err != nil {
+			return
+		}
+		replacement = b.String()
+	} else {
+		return
+	}
+
+	p.Report(analysis.Diagnostic{
+		Pos:      a.Pos(),
+		End:      a.End(),
+		Category: ConvertedKey,
+		Message:  fmt.Sprintf("key %q (arg %d to %s) is converted needlessly; pass the constant", key, i, name),
+		SuggestedFixes: []analysis.SuggestedFix{{
+			Message:   fmt.Sprintf("Replace with %s", replacement),
+			TextEdits: []analysis.TextEdit{{Pos: a.Pos(), End: a.End(), NewText: []byte(replacement)}},
+		}},
+	})
+}
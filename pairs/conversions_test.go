@@ -0,0 +1,60 @@
+package pairs
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestConvertedKeys(t *testing.T) {
+	src := `package a
+
+import "a/log"
+
+const userKey = "user_id"
+
+type Key string
+
+const requestKey Key = "request_id"
+
+func Foo(id int, b []byte) {
+	log.Log(%s, id) // want "key \"user_id\" \\(arg 0 to a/log.Log\\) is converted needlessly; pass the constant"
+
+	log.Log(%s, id) // want "key \"user_id\" \\(arg 0 to a/log.Log\\) is converted needlessly; pass the constant"
+
+	log.Log(%s, id) // want "key \"user_id\" \\(arg 0 to a/log.Log\\) is converted needlessly; pass the constant"
+
+	log.Log(string(requestKey), id)
+	log.Log(string(b), id)
+	log.Log(id, string([]byte("user_id"))) // want "arg 0 to a/log.Log is expression int but should be a constant string"
+}
+`
+	filemap := map[string]string{
+		"a/a.go":        fmt.Sprintf(src, `string([]byte("user_id"))`, `string([]rune(userKey))`, `string(userKey)`),
+		"a/a.go.golden": fmt.Sprintf(src, `"user_id"`, `"user_id"`, `userKey`),
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("pair-func", "a/log.Log=0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Flags.Set("key", "user_id,request_id"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Flags.Set("converted-keys", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.RunWithSuggestedFixes(t, dir, a, "a")
+}
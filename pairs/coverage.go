@@ -0,0 +1,125 @@
+package pairs
+
+import (
+	"fmt"
+	"go/ast"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// SelectorStats counts the calls in a package matched by one configured
+// selector.
+type SelectorStats struct {
+	Selector string // as passed to -pair-func or -builder-func, minus any offset
+
+	// Checked counts the calls that were checked, and Diagnostics the
+	// diagnostics reported for them.
+	Checked     int
+	Diagnostics int
+
+	// Skipped counts the calls that couldn't be checked: calls spreading
+	// a slice into the pairs, and calls through func values or interfaces
+	// that couldn't be resolved to a func but have the selector's name.
+	Skipped int
+}
+
+// SelectorCoverage is a package fact recording how many calls each
+// configured selector matched in the package, so drivers can show which
+// selectors are doing work and which calls slip through.
+type SelectorCoverage struct {
+	Selectors []SelectorStats // sorted by Selector
+}
+
+// AFact implements analysis.Fact.
+func (*SelectorCoverage) AFact() {}
+
+func (c *SelectorCoverage) String() string {
+	var s []string
+	for _, st := range c.Selectors {
+		s = append(s, fmt.Sprintf("%s:%d/%d/%d", st.Selector, st.Checked, st.Skipped, st.Diagnostics))
+	}
+	return strings.Join(s, " ")
+}
+
+type selectorCoverage map[funcSelector]*SelectorStats
+
+func (s selectorCoverage) stats(sel funcSelector) *SelectorStats {
+	st := s[sel]
+	if st == nil {
+		st = &SelectorStats{Selector: sel.String()}
+		s[sel] = st
+	}
+	return st
+}
+
+// checked records a call checked for sel, and returns a copy of p that
+// counts the diagnostics reported for it.  A nil selectorCoverage, when
+// -coverage is off, records nothing and returns p.
+func (s selectorCoverage) checked(p *analysis.Pass, sel funcSelector) *analysis.Pass {
+	if s == nil {
+		return p
+	}
+	st := s.stats(sel)
+	st.Checked++
+
+	counted := *p
+	counted.Report = func(d analysis.Diagnostic) {
+		st.Diagnostics++
+		p.Report(d)
+	}
+	return &counted
+}
+
+// skipped records a call for sel that couldn't be checked.
+func (s selectorCoverage) skipped(sel funcSelector) {
+	if s == nil {
+		return
+	}
+	s.stats(sel).Skipped++
+}
+
+// unresolved records call, which callSelectors couldn't resolve, as skipped
+// for every configured selector of a func with the same name.
+func (s selectorCoverage) unresolved(c *checker, call *ast.CallExpr) {
+	if s == nil {
+		return
+	}
+	var name string
+	switch f := ast.Unparen(call.Fun).(type) {
+	case *ast.SelectorExpr:
+		name = f.Sel.Name
+	case *ast.Ident:
+		name = f.Name
+	default:
+		return
+	}
+
+	for sel := range c.offsets {
+		if sel.fun == name {
+			s.skipped(sel)
+		}
+	}
+	for sel := range c.builderFuncs {
+		if sel.fun == name {
+			s.skipped(sel)
+		}
+	}
+}
+
+// export exports the stats as a SelectorCoverage fact, if there are any.
+func (s selectorCoverage) export(p *analysis.Pass) {
+	if len(s) == 0 {
+		return
+	}
+
+	cov := &SelectorCoverage{}
+	for _, st := range s {
+		cov.Selectors = append(cov.Selectors, *st)
+	}
+	sort.Slice(cov.Selectors, func(i, j int) bool {
+		return cov.Selectors[i].Selector < cov.Selectors[j].Selector
+	})
+	p.ExportPackageFact(cov)
+}
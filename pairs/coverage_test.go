@@ -0,0 +1,64 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestSelectorCoverage(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a // want package:"a/log.Builder.Add:2/0/1 a/log.Info:1/0/0 a/log.Log:2/2/1"
+
+import "a/log"
+
+type logger struct{ Log func(kv ...interface{}) }
+
+func Foo(kv []interface{}, name string, l logger, b *log.Builder) {
+	log.Log("a", 1)
+	log.Log("a", 1, 2) // want "3 args passed to a/log.Log; must be even"
+	log.Log(kv...)
+	l.Log("a", 1)
+
+	b.Add("a", 1).Add(name, 2) // want "arg 0 to .* is expression string but should be a constant string"
+}
+
+func Bar() {
+	log.Info("msg", "b", 2)
+}
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+
+func Info(msg string, kv ...interface{}) {}
+
+type Builder struct{}
+
+func (b *Builder) Add(k string, v interface{}) *Builder { return b }
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for _, f := range []string{"a/log.Log=0", "a/log.Info=1"} {
+		if err := a.Flags.Set("pair-func", f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for flag, v := range map[string]string{
+		"builder-func": "a/log.Builder.Add",
+		"coverage":     "true",
+	} {
+		if err := a.Flags.Set(flag, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
@@ -0,0 +1,63 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+// TestDeferredCalls checks the args of deferred calls, which are evaluated
+// when the defer statement runs, including those of cleanup helpers that
+// capture an error and take pairs of context, whose pairs come after the
+// error pointer and the func they invoke.
+func TestDeferredCalls(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import "a/cleanup"
+
+type file struct{}
+
+func (file) Close() error { return nil }
+
+func Foo(f file, id int) (err error) {
+	defer cleanup.Capture(&err, f.Close, "path") // want "3 args passed to a/cleanup.Capture; must be even: arg 2, \"path\", is unpaired"
+	defer cleanup.Capture(&err, f.Close, id, "id") // want "arg 2 to a/cleanup.Capture is expression int but should be a constant string"
+	defer cleanup.Capture(&err, f.Close, "id", id)
+	defer cleanup.AppendInvoke(&err, cleanup.Invoke(f.Close, "path")) // want "2 args passed to a/cleanup.Invoke; must be even: arg 1, \"path\", is unpaired"
+	defer func() {
+		cleanup.Capture(&err, f.Close, "id") // want "3 args passed to a/cleanup.Capture; must be even"
+	}()
+	go cleanup.Capture(nil, f.Close, id, "id") // want "arg 2 to a/cleanup.Capture is expression int but should be a constant string"
+	return nil
+}
+`,
+		"a/cleanup/cleanup.go": `package cleanup
+
+// Capture calls do, setting *err to its error if *err is nil, and logging
+// it with the pairs otherwise.
+func Capture(err *error, do func() error, kv ...interface{}) {}
+
+// Invoke returns an invoker calling do, whose error is logged with the
+// pairs.
+func Invoke(do func() error, kv ...interface{}) func() error { return do }
+
+// AppendInvoke calls invoke, appending its error to *err.
+func AppendInvoke(err *error, invoke func() error) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for _, f := range []string{"a/cleanup.Capture=2", "a/cleanup.Invoke=1"} {
+		if err := a.Flags.Set("pair-func", f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	analysistest.Run(t, dir, a, "a")
+}
@@ -0,0 +1,268 @@
+package pairs
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/internal/config"
+)
+
+// discoveredFile is the name of the configuration files -discover-config
+// looks for.
+const discoveredFile = ".splinter.yaml"
+
+// discoveredConfigs caches the entries of the configuration files read for
+// discovery, by path, along with the module roots of directories and the
+// files their go.mod files reference, since every package below a file
+// reads it.
+type discoveredConfigs struct {
+	mu      sync.Mutex
+	entries map[string][]config.Entry // nil if there's no file
+	roots   map[string]string         // "" if not in a module
+	refs    map[string]string         // by module root; "" if none
+}
+
+func newDiscoveredConfigs() *discoveredConfigs {
+	return &discoveredConfigs{
+		entries: map[string][]config.Entry{},
+		roots:   map[string]string{},
+		refs:    map[string]string{},
+	}
+}
+
+func (d *discoveredConfigs) read(path string) ([]config.Entry, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if entries, ok := d.entries[path]; ok {
+		return entries, nil
+	}
+	entries, err := config.Read(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	d.entries[path] = entries
+	return entries, nil
+}
+
+// moduleRoot returns the root of the module containing dir, the nearest
+// directory at or above it with a go.mod file.
+func (d *discoveredConfigs) moduleRoot(dir string) (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	root, ok := d.roots[dir]
+	if !ok {
+		root, _ = config.ModuleRoot(dir)
+		d.roots[dir] = root
+	}
+	return root, root != ""
+}
+
+// referenced returns the path of the configuration file the go.mod file at
+// root references with a splinter:config comment, if any.
+func (d *discoveredConfigs) referenced(root string) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if path, ok := d.refs[root]; ok {
+		return path, nil
+	}
+	path, err := config.Referenced(root)
+	if err != nil {
+		return "", err
+	}
+	d.refs[root] = path
+	return path, nil
+}
+
+// paths returns the paths of the configuration files discovered for the
+// packages in dir, farthest first, whether or not they exist, along with
+// the one of them the go.mod file of its module references, if any, and
+// that go.mod file, "" if it isn't in a module.
+func (d *discoveredConfigs) paths(dir string, discoverConfig bool) (paths []string, ref, gomod string, err error) {
+	root, ok := d.moduleRoot(dir)
+	if !ok {
+		return nil, "", "", nil
+	}
+
+	if ref, err = d.referenced(root); err != nil {
+		return nil, "", "", err
+	} else if ref != "" {
+		paths = append(paths, ref)
+	}
+	if discoverConfig {
+		var walked []string
+		for at := dir; ; at = filepath.Dir(at) {
+			walked = append(walked, filepath.Join(at, discoveredFile))
+			if at == root {
+				break
+			}
+		}
+		for i := len(walked) - 1; i >= 0; i-- {
+			paths = append(paths, walked[i])
+		}
+	}
+	return paths, ref, filepath.Join(root, "go.mod"), nil
+}
+
+// ConfigFiles returns the files the configuration a, an analyzer from
+// NewAnalyzer, discovers for the packages in dir is read from: the go.mod
+// file of their module, the file it references, and with -discover-config
+// the .splinter.yaml files from the module's root down to dir, whether or
+// not they exist.  Drivers caching diagnostics key them by these files too.
+// It returns nil for other analyzers.
+func ConfigFiles(a *analysis.Analyzer, dir string) ([]string, error) {
+	f := a.Flags.Lookup("discover-config")
+	if f == nil {
+		return nil, nil
+	}
+	discoverConfig, _ := strconv.ParseBool(f.Value.String())
+	paths, _, gomod, err := newDiscoveredConfigs().paths(dir, discoverConfig)
+	if err != nil || gomod == "" {
+		return nil, err
+	}
+	return append([]string{gomod}, paths...), nil
+}
+
+// applyModuleConfig sets the flags of fset from the configuration file the
+// go.mod of the module of the working directory references, if any, the
+// way config.Parse applies a -config file, presets first, and returns its
+// path.  Every driver runs in the module it analyzes, as go vet does in the
+// directory of each package, so its file applies whole.
+func applyModuleConfig(fset *flag.FlagSet) (string, error) {
+	path, err := config.ModuleFile(".")
+	if path == "" || err != nil {
+		return "", err
+	}
+	entries, err := config.Read(path)
+	if err != nil {
+		return "", err
+	}
+	for _, presets := range []bool{true, false} {
+		for _, e := range entries {
+			if (e.Flag == "preset") != presets {
+				continue
+			}
+			if err := e.Set(fset); err != nil {
+				return "", fmt.Errorf("%s:%d: %w", path, e.Line, err)
+			}
+		}
+	}
+	return path, nil
+}
+
+// commandLine records the pair funcs and whitelisted types given on the
+// command line, as opposed to by presets or -config files, so that they
+// take precedence over the configuration discovered for each package too.
+type commandLine struct {
+	layer   config.Layer
+	entries []config.Entry
+}
+
+// layeredFlag is a flag.Value recording the values its flag is set to in
+// the command line layer of configuration, as applied by config.Parse.
+type layeredFlag struct {
+	flag.Value
+	name string
+	cmd  *commandLine
+}
+
+func (f *layeredFlag) Set(v string) error {
+	if err := f.Value.Set(v); err != nil {
+		return err
+	}
+	if f.cmd.layer == config.CommandLineLayer {
+		f.cmd.entries = append(f.cmd.entries, config.Entry{Flag: f.name, Value: v})
+	}
+	return nil
+}
+
+func (f *layeredFlag) SetLayer(l config.Layer) { f.cmd.layer = l }
+
+// discover returns c with the pair funcs and whitelisted types of the
+// configuration files of the package of p merged in: the file the go.mod
+// of its module references, unless it's c.moduleConfig, applied whole
+// already, and with -discover-config the .splinter.yaml files from the root
+// of the module down to the package's directory, those nearer the package
+// taking precedence, and those given on the command line taking precedence
+// over all of them.  c itself is returned if there are none, or the package
+// isn't in a module.  The other flags of a file a go.mod references can't
+// be applied to a single module's packages, so they're an error.
+func (c *checker) discover(p *analysis.Pass) (*checker, error) {
+	if len(p.Files) == 0 {
+		return c, nil
+	}
+	dir := filepath.Dir(p.Fset.File(p.Files[0].Pos()).Name())
+	paths, ref, gomod, err := c.discovered.paths(dir, c.discoverConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var merged *checker
+	for _, path := range paths {
+		if path == c.moduleConfig {
+			continue
+		}
+		entries, err := c.discovered.read(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.Flag != "pair-func" && e.Flag != "assume-pair" {
+				if path == ref {
+					return nil, fmt.Errorf("%s:%d: %s applies only when splinter runs in the module of %s; from the configuration of other modules, only pair-func and assume-pair do", path, e.Line, e.Flag, gomod)
+				}
+				continue // other flags can only be set by -config
+			}
+			if merged == nil {
+				merged = c.clone()
+			}
+
+			if e.Flag == "pair-func" {
+				err = merged.offsets.Set(e.Value)
+			} else {
+				err = merged.whitelistedTypes.Set(e.Value)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: invalid value %q for %s: %w", path, e.Line, e.Value, e.Flag, err)
+			}
+		}
+	}
+	if merged == nil {
+		return c, nil
+	}
+	for _, e := range c.cmdline.entries {
+		if e.Flag == "pair-func" {
+			merged.offsets.Set(e.Value)
+		} else {
+			merged.whitelistedTypes.Set(e.Value)
+		}
+	}
+	return merged, nil
+}
+
+// clone returns a copy of c whose pair funcs and whitelisted types can be
+// added to without affecting c.
+func (c *checker) clone() *checker {
+	cc := *c
+	cc.offsets = funcOffset{}
+	for sel, offset := range c.offsets {
+		cc.offsets[sel] = offset
+	}
+	cc.whitelistedTypes = typeWhitelist{}
+	for t := range c.whitelistedTypes {
+		cc.whitelistedTypes[t] = true
+	}
+	return &cc
+}
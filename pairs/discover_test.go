@@ -0,0 +1,287 @@
+package pairs
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/ZipRecruiter/splinter/internal/config"
+)
+
+func TestDiscoverConfig(t *testing.T) {
+	filemap := map[string]string{
+		".splinter.yaml": "pair-func: a/log.Info=1\n", // above the module root
+		"a/go.mod":       "module a\n",
+		"a/.splinter.yaml": `pair-func: a/log.Log=0
+duplicate-keys: true
+`,
+		"a/a.go": `package a
+
+import "a/log"
+
+func Foo() {
+	log.Log("a", 1, 2) // want "3 args passed to a/log.Log; must be even"
+	log.Info("msg", "a", 1, 2)
+}
+`,
+		"a/sub/.splinter.yaml": `pair-func: a/log.Log=1
+assume-pair: a/details.Pairs
+`,
+		"a/sub/sub.go": `package sub
+
+import (
+	"a/details"
+	"a/log"
+)
+
+func Foo(p *details.Pairs) {
+	log.Log("msg", "a", 1)
+	log.Log("msg", "a", 1, 2) // want "4 args passed to a/log.Log; must be even"
+	log.Log("msg", p)
+}
+`,
+		"a/details/details.go": `package details
+
+type Pairs struct{}
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+
+func Info(msg string, kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("discover-config", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, dir, a, "a", "a/sub")
+}
+
+func TestGoModConfig(t *testing.T) {
+	filemap := map[string]string{
+		"a/go.mod": `module a
+
+// splinter:config=tools/splinter.yaml
+`,
+		"a/tools/splinter.yaml": `pair-func: a/log.Log=0
+`,
+		"a/.splinter.yaml": `pair-func: a/log.Info=1
+`,
+		"a/a.go": `package a
+
+import "a/log"
+
+func Foo() {
+	log.Log("a", 1, 2) // want "3 args passed to a/log.Log; must be even"
+	log.Info("msg", "a", 1, 2)
+}
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+
+func Info(msg string, kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	// the file go.mod references is read without -discover-config
+	analysistest.Run(t, dir, NewAnalyzer(), "a")
+}
+
+func TestGoModConfigFlags(t *testing.T) {
+	filemap := map[string]string{
+		"a/go.mod": `module a
+
+// splinter:config=tools/splinter.yaml
+`,
+		"a/tools/splinter.yaml": `pair-func: a/log.Log=0
+duplicate-keys: true
+`,
+		"a/a.go": `package a
+
+import "a/log"
+
+func Foo(b *log.Builder) {
+	log.Log("a", 1, 2) // want "3 args passed to a/log.Log; must be even"
+	b.Add("id", 1)
+	b.Add("id", 2) // want "key \"id\" passed to .* was already added on line 7"
+}
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+
+type Builder struct{}
+
+func (b *Builder) Add(k string, v interface{}) *Builder { return b }
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	// from outside the module, only its pair funcs and whitelisted types
+	// could apply
+	a := NewAnalyzer()
+	if err := a.Flags.Set("builder-func", "a/log.Builder.Add"); err != nil {
+		t.Fatal(err)
+	}
+	rec := &errorRecorder{}
+	analysistest.Run(rec, dir, a, "a", "a/log")
+	expected := `tools/splinter.yaml:2: duplicate-keys applies only when splinter runs in the module of`
+	if errs := strings.Join(rec.errs, "\n"); !strings.Contains(errs, expected) {
+		t.Errorf("expected error containing %q, got:\n%s", expected, errs)
+	}
+
+	// within it, the whole file applies, like a -config file
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(filepath.Join(dir, "src", "a")); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	a = NewAnalyzer()
+	if err := a.Flags.Set("builder-func", "a/log.Builder.Add"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, dir, a, "a")
+}
+
+// errorRecorder is an analysistest.Testing recording the errors reported,
+// for tests expecting the analysis to fail.
+type errorRecorder struct {
+	errs []string
+}
+
+func (r *errorRecorder) Errorf(format string, args ...interface{}) {
+	r.errs = append(r.errs, fmt.Sprintf(format, args...))
+}
+
+func TestConfigFiles(t *testing.T) {
+	dir, cleanup, err := analysistest.WriteFiles(map[string]string{
+		"a/go.mod":              "module a\n\n// splinter:config=tools/splinter.yaml\n",
+		"a/tools/splinter.yaml": "pair-func: a/log.Log=0\n",
+		"a/sub/sub.go":          "package sub\n",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+	root := filepath.Join(dir, "src", "a")
+
+	tests := []struct {
+		discover bool
+		expected []string
+	}{
+		{false, []string{"go.mod", "tools/splinter.yaml"}},
+		{true, []string{"go.mod", "tools/splinter.yaml", ".splinter.yaml", "sub/.splinter.yaml"}},
+	}
+	for _, test := range tests {
+		a := NewAnalyzer()
+		if err := a.Flags.Set("discover-config", strconv.FormatBool(test.discover)); err != nil {
+			t.Fatal(err)
+		}
+		files, err := ConfigFiles(a, filepath.Join(root, "sub"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, f := range files {
+			rel, _ := filepath.Rel(root, f)
+			got = append(got, filepath.ToSlash(rel))
+		}
+		if d := cmp.Diff(test.expected, got); d != "" {
+			t.Errorf("-discover-config=%t: unexpected files (-expected +got):\n%s", test.discover, d)
+		}
+	}
+
+	if files, err := ConfigFiles(&analysis.Analyzer{Name: "other"}, root); err != nil || files != nil {
+		t.Errorf("expected no files for another analyzer, got %v, %v", files, err)
+	}
+}
+
+func TestLayeredConfig(t *testing.T) {
+	filemap := map[string]string{
+		"a/go.mod": `module a
+
+// splinter:config=splinter.yaml
+`,
+		"a/splinter.yaml": `pair-func:
+  - a/log.Log=1
+  - a/log.Info=1
+`,
+		"a/a.go": `package a
+
+import "a/log"
+
+func Foo() {
+	log.Log("a", 1, 2) // want "3 args passed to a/log.Log; must be even"
+	log.Info("msg", "a", 1, 2) // want "4 args passed to a/log.Info; must be even"
+}
+`,
+		"a/sub/.splinter.yaml": `pair-func:
+  - "!a/log.Info"
+  - a/log.Log=1
+`,
+		"a/sub/sub.go": `package sub
+
+import "a/log"
+
+func Foo() {
+	log.Log("a", 1, 2) // want "3 args passed to a/log.Log; must be even"
+	log.Info("msg", "a", 1, 2)
+}
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+
+func Info(msg string, kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	// the command line overrides the offset both the module's and the
+	// directory's configuration give a/log.Log
+	a := NewAnalyzer()
+	fset := flag.NewFlagSet("splinter", flag.ContinueOnError)
+	a.Flags.VisitAll(func(f *flag.Flag) { fset.Var(f.Value, f.Name, f.Usage) })
+	if err := config.Parse(fset, []string{"-discover-config", "-pair-func", "a/log.Log=0"}); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, dir, a, "a", "a/sub")
+}
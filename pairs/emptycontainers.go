@@ -0,0 +1,144 @@
+package pairs
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// emptyContainerCorrect reports a, a whitelisted container passed as the
+// pairs of a call to name, if it's known to be empty: freshly constructed
+// by a composite literal without elements, new, or a constructor taking no
+// args, either right there or in a local variable that's used nowhere else.
+func (c *checker) emptyContainerCorrect(p *analysis.Pass, name string, i int, a ast.Expr) {
+	e := ast.Unparen(a)
+	if id, ok := e.(*ast.Ident); ok {
+		v, ok := p.TypesInfo.Uses[id].(*types.Var)
+		if !ok {
+			return
+		}
+		if e, ok = onlyValue(p, v, id); !ok || e == nil {
+			return
+		}
+	}
+	if !c.isEmptyContainer(p, e) {
+		return
+	}
+
+	c.report(p, a, EmptyContainer, "arg %d to %s is a %s that's never populated, so it attaches no pairs",
+		i,
+		name,
+		types.TypeString(p.TypesInfo.TypeOf(a), types.RelativeTo(p.Pkg)),
+	)
+}
+
+// isEmptyContainer returns true if e constructs an empty whitelisted
+// container.
+func (c *checker) isEmptyContainer(p *analysis.Pass, e ast.Expr) bool {
+	e = ast.Unparen(e)
+	if u, ok := e.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		e = ast.Unparen(u.X)
+	}
+	if !c.isWhitelisted(p, e) {
+		return false
+	}
+
+	switch x := e.(type) {
+	case *ast.CompositeLit:
+		return len(x.Elts) == 0
+	case *ast.CallExpr:
+		if len(x.Args) != 0 {
+			if id, ok := ast.Unparen(x.Fun).(*ast.Ident); ok && id.Name == "new" {
+				_, builtin := p.TypesInfo.Uses[id].(*types.Builtin)
+				return builtin
+			}
+			return false
+		}
+		fn, ok := typeutil.Callee(p.TypesInfo, x).(*types.Func)
+		return ok && fn.Type().(*types.Signature).Recv() == nil
+	}
+	return false
+}
+
+// onlyValue returns the value a local variable v is declared with, if
+// it's used nowhere in its func but at use, so the value is still the one
+// it was declared with when it's used there.  The value is nil if v is
+// declared without one, so it's still the zero value.
+func onlyValue(p *analysis.Pass, v *types.Var, use *ast.Ident) (ast.Expr, bool) {
+	value, body, ok := declaredValue(p, v)
+	if !ok {
+		return nil, false
+	}
+
+	used := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && id != use && p.TypesInfo.Uses[id] == v {
+			used = true
+		}
+		return !used
+	})
+	return value, !used
+}
+
+// declaredValue returns the value a local variable v is declared with, nil
+// if it's declared without one, along with the body of the func declaring
+// it.
+func declaredValue(p *analysis.Pass, v *types.Var) (ast.Expr, ast.Node, bool) {
+	if v.Pkg() != p.Pkg || v.Parent() == nil || v.Parent() == p.Pkg.Scope() {
+		return nil, nil, false
+	}
+
+	var file *ast.File
+	for _, f := range p.Files {
+		if f.Pos() <= v.Pos() && v.Pos() < f.End() {
+			file = f
+		}
+	}
+	if file == nil {
+		return nil, nil, false
+	}
+
+	path, _ := astutil.PathEnclosingInterval(file, v.Pos(), v.Pos())
+	var value ast.Expr
+	var declared bool
+	var body ast.Node
+	for _, n := range path {
+		switch n := n.(type) {
+		case *ast.ValueSpec:
+			if !declared && (len(n.Values) == 0 || len(n.Names) == len(n.Values)) {
+				for j, id := range n.Names {
+					if id.Pos() == v.Pos() {
+						declared = true
+						if len(n.Values) != 0 {
+							value = n.Values[j]
+						}
+					}
+				}
+			}
+		case *ast.AssignStmt:
+			if !declared && n.Tok == token.DEFINE && len(n.Lhs) == len(n.Rhs) {
+				for j, lhs := range n.Lhs {
+					if lhs.Pos() == v.Pos() {
+						declared = true
+						value = n.Rhs[j]
+					}
+				}
+			}
+		case *ast.FuncDecl:
+			body = n.Body
+		case *ast.FuncLit:
+			body = n.Body
+		}
+		if body != nil {
+			break
+		}
+	}
+	if !declared || body == nil {
+		return nil, nil, false
+	}
+	return value, body, true
+}
@@ -0,0 +1,68 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestEmptyContainers(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import (
+	"a/details"
+	"a/log"
+)
+
+func Foo(id int) {
+	log.Log(details.NewPairs()) // want "arg 0 to a/log.Log is a \\*a/details.Pairs that's never populated, so it attaches no pairs"
+	log.Log(&details.Pairs{}) // want "arg 0 to a/log.Log is a \\*a/details.Pairs that's never populated"
+	log.Log(new(details.Pairs)) // want "arg 0 to a/log.Log is a \\*a/details.Pairs that's never populated"
+
+	stub := details.NewPairs()
+	log.Log(stub) // want "arg 0 to a/log.Log is a \\*a/details.Pairs that's never populated"
+
+	filled := details.NewPairs()
+	filled.Add("user_id", id)
+	log.Log(filled)
+
+	log.Log(details.Of("user_id", id))
+	log.Log(&details.Pairs{KV: []interface{}{"user_id", id}})
+}
+`,
+		"a/details/details.go": `package details
+
+type Pairs struct{ KV []interface{} }
+
+func NewPairs() *Pairs { return &Pairs{} }
+
+func Of(kv ...interface{}) *Pairs { return &Pairs{KV: kv} }
+
+func (p *Pairs) Add(kv ...interface{}) { p.KV = append(p.KV, kv...) }
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for flag, v := range map[string]string{
+		"pair-func":        "a/log.Log=0",
+		"assume-pair":      "a/details.Pairs",
+		"empty-containers": "true",
+	} {
+		if err := a.Flags.Set(flag, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
@@ -0,0 +1,50 @@
+package pairs
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/ZipRecruiter/splinter/internal/config"
+)
+
+// The environment variables configuring the analyzer, for drivers like go
+// vet -vettool and gopls that make passing flags through hard.
+const (
+	// EnvConfig names a configuration file, as taken by -config.
+	EnvConfig = "SPLINTER_CONFIG"
+
+	// EnvPairFuncs lists pair funcs, as taken by -pair-func, separated
+	// by commas or spaces.
+	EnvPairFuncs = "SPLINTER_PAIR_FUNCS"
+
+	// EnvAssumePairs lists types, as taken by -assume-pair, separated by
+	// commas or spaces.
+	EnvAssumePairs = "SPLINTER_ASSUME_PAIRS"
+)
+
+// applyEnv sets the flags of fset from the environment, as read by getenv:
+// first from the file EnvConfig names, then from the lists of EnvPairFuncs
+// and EnvAssumePairs.
+func applyEnv(fset *flag.FlagSet, getenv func(string) string) error {
+	if path := getenv(EnvConfig); path != "" {
+		if err := config.Load(path, fset); err != nil {
+			return fmt.Errorf("%s: %w", EnvConfig, err)
+		}
+	}
+
+	for _, env := range []struct{ name, flag string }{
+		{EnvPairFuncs, "pair-func"},
+		{EnvAssumePairs, "assume-pair"},
+	} {
+		values := strings.FieldsFunc(getenv(env.name), func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t' || r == '\n'
+		})
+		for _, v := range values {
+			if err := fset.Set(env.flag, v); err != nil {
+				return fmt.Errorf("%s: invalid value %q for %s: %w", env.name, v, env.flag, err)
+			}
+		}
+	}
+	return nil
+}
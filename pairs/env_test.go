@@ -0,0 +1,67 @@
+package pairs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestEnv(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import (
+	"a/details"
+	"a/log"
+)
+
+func Foo(p details.Pairs) {
+	log.Log("a", 1, 2) // want "3 args passed to a/log.Log; must be even"
+	log.Info("msg", "a", 1, 2) // want "4 args passed to a/log.Info; must be even"
+	log.Warn("msg", "a", 1, 2) // want "4 args passed to a/log.Warn; must be even"
+	log.Log(p)
+}
+`,
+		"a/details/details.go": `package details
+
+type Pairs struct{}
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+
+func Info(msg string, kv ...interface{}) {}
+
+func Warn(msg string, kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	path := filepath.Join(t.TempDir(), "splinter.yaml")
+	if err := os.WriteFile(path, []byte("pair-func: a/log.Warn=1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvConfig, path)
+	t.Setenv(EnvPairFuncs, "a/log.Log=0, a/log.Info=1")
+	t.Setenv(EnvAssumePairs, "a/details.Pairs")
+
+	analysistest.Run(t, dir, NewAnalyzer(), "a")
+}
+
+func TestEnvErrors(t *testing.T) {
+	t.Setenv(EnvPairFuncs, "a/log.Log")
+
+	a := NewAnalyzer()
+	_, err := a.Run(nil)
+	if err == nil || !strings.Contains(err.Error(), EnvPairFuncs+`: invalid value "a/log.Log" for pair-func`) {
+		t.Errorf("run = %v; expected an error naming %s", err, EnvPairFuncs)
+	}
+}
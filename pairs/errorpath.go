@@ -0,0 +1,83 @@
+package pairs
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+func (c *checker) isErrorPathFunc(sels []funcSelector) bool {
+	for _, sel := range sels {
+		if c.errorPathFuncs[sel] {
+			return true
+		}
+	}
+	return false
+}
+
+// checkedError returns the error variable compared to nil by cond, as in
+// err != nil.
+func checkedError(i *types.Info, cond ast.Expr) (*types.Var, bool) {
+	b, ok := ast.Unparen(cond).(*ast.BinaryExpr)
+	if !ok || b.Op != token.NEQ {
+		return nil, false
+	}
+
+	x, y := ast.Unparen(b.X), ast.Unparen(b.Y)
+	if isNil(i, x) {
+		x, y = y, x
+	}
+	id, ok := x.(*ast.Ident)
+	if !ok || !isNil(i, y) {
+		return nil, false
+	}
+	v, ok := i.Uses[id].(*types.Var)
+	if !ok || !isError(v.Type()) {
+		return nil, false
+	}
+	return v, true
+}
+
+func isNil(i *types.Info, e ast.Expr) bool {
+	tv, ok := i.Types[e]
+	return ok && tv.IsNil()
+}
+
+// errorPathCorrect reports call, to name in file f, if it's in the body of
+// an if err != nil block and none of its args use err.  Only the innermost
+// such block counts, and func literals are a boundary.
+func (c *checker) errorPathCorrect(p *analysis.Pass, name string, f *ast.File, call *ast.CallExpr) {
+	path, _ := astutil.PathEnclosingInterval(f, call.Pos(), call.End())
+
+	var err *types.Var
+	for i, n := range path {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return
+		}
+		if s, ok := n.(*ast.IfStmt); ok && i > 0 && path[i-1] == s.Body {
+			if v, ok := checkedError(p.TypesInfo, s.Cond); ok {
+				err = v
+				break
+			}
+		}
+	}
+	if err == nil {
+		return
+	}
+
+	used := false
+	for _, a := range call.Args {
+		ast.Inspect(a, func(n ast.Node) bool {
+			if id, ok := n.(*ast.Ident); ok && p.TypesInfo.Uses[id] == err {
+				used = true
+			}
+			return !used
+		})
+	}
+	if !used {
+		c.report(p, call, UnattachedError, "%s logs in an error path without attaching %s", name, err.Name())
+	}
+}
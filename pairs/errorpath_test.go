@@ -0,0 +1,70 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestErrorPath(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import "a/log"
+
+func save() error { return nil }
+
+func Foo(id string) {
+	err := save()
+	if err != nil {
+		log.Log("msg", "saving failed", "id", id) // want "a/log.Log logs in an error path without attaching err"
+		log.Log("msg", "saving failed", "err", err)
+		log.Log("msg", "saving failed", "err", err.Error())
+		log.Info("saving failed")
+		if id != "" {
+			log.Log("id", id) // want "a/log.Log logs in an error path without attaching err"
+		}
+		go func() {
+			log.Log("id", id)
+		}()
+	} else {
+		log.Log("id", id)
+	}
+
+	if closeErr := save(); nil != closeErr {
+		log.Log("err", err) // want "a/log.Log logs in an error path without attaching closeErr"
+	}
+	if err == nil {
+		log.Log("id", id)
+	}
+	if id != "" {
+		log.Log("id", id)
+	}
+}
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+
+func Info(msg string, kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for _, f := range []string{"a/log.Log=0", "a/log.Info=1"} {
+		if err := a.Flags.Set("pair-func", f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := a.Flags.Set("error-path-func", "a/log.Log"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
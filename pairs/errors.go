@@ -0,0 +1,94 @@
+package pairs
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/internal/calls"
+)
+
+var errorType = types.Universe.Lookup("error").Type()
+
+func isError(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	if b, ok := t.(*types.Basic); ok && b.Kind() == types.UntypedNil {
+		return false
+	}
+	return types.Implements(t, errorType.Underlying().(*types.Interface))
+}
+
+// errorsCorrect reports errors passed in the pairs of call, to the wrap func
+// name.  If the func has an error param before offset, any error in the
+// pairs should have been passed there (after errors.Join, if need be);
+// otherwise only one error may be passed in the pairs.
+func (c *checker) errorsCorrect(p *analysis.Pass, name string, offset int, call *ast.CallExpr) {
+	if len(call.Args) <= offset {
+		return
+	}
+
+	slot := false
+	if sig, ok := p.TypesInfo.TypeOf(call.Fun).(*types.Signature); ok {
+		for i := 0; i < offset && i < sig.Params().Len(); i++ {
+			if types.Identical(sig.Params().At(i).Type(), errorType) {
+				slot = true
+			}
+		}
+	}
+
+	var errs []ast.Expr
+	for i, a := range call.Args[offset:] {
+		if i%2 != 0 && isError(p.TypesInfo.TypeOf(a)) {
+			errs = append(errs, a)
+		}
+	}
+
+	switch {
+	case slot:
+		for _, e := range errs {
+			c.report(p, e, MultipleErrors, "error passed in the pairs of %s; pass it as the error arg, combining errors with errors.Join", name)
+		}
+	case len(errs) > 1:
+		c.report(p, errs[1], MultipleErrors, "%d errors passed in the pairs of %s; combine them with errors.Join", len(errs), name)
+	}
+}
+
+// wrapsWithVerb reports whether e wraps an error with the %w verb, either
+// by being a call to fmt.Errorf or by being a constant format string.
+func wrapsWithVerb(i *types.Info, e ast.Expr) bool {
+	e = ast.Unparen(e)
+	if call, ok := e.(*ast.CallExpr); ok {
+		callee, ok := calls.Resolve(i, call)
+		if !ok || callee.Pkg != "fmt" || callee.Fun != "Errorf" || len(call.Args) == 0 {
+			return false
+		}
+		e = call.Args[0]
+	}
+
+	tv, ok := i.Types[e]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return false
+	}
+	return strings.Contains(constant.StringVal(tv.Value), "%w")
+}
+
+// mixedWrapCorrect reports calls to the wrap func name that both wrap an
+// error with %w, in one of the args before offset, and pass pairs, for APIs
+// where the two are alternative ways of adding context.
+func (c *checker) mixedWrapCorrect(p *analysis.Pass, name string, offset int, call *ast.CallExpr) {
+	if len(call.Args) <= offset {
+		return
+	}
+
+	for _, a := range call.Args[:offset] {
+		if wrapsWithVerb(p.TypesInfo, a) {
+			c.report(p, a, MixedWrap, "%s wraps with %%w and also takes pairs; add the context one way, not both", name)
+			return
+		}
+	}
+}
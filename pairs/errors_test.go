@@ -0,0 +1,121 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestMultipleErrors(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import (
+	"a/errors"
+	"os"
+)
+
+type myErr struct{}
+
+func (*myErr) Error() string { return "" }
+
+func Foo(err, closeErr error, pathErr *os.PathError) {
+	_ = errors.Wrap(err, "saving", "id", 1)
+	_ = errors.Wrap(err, "saving", "cause", closeErr) // want "error passed in the pairs of a/errors.Wrap; pass it as the error arg, combining errors with errors.Join"
+	_ = errors.Wrap(err, "saving", "path", pathErr) // want "error passed in the pairs of a/errors.Wrap; pass it as the error arg, combining errors with errors.Join"
+	_ = errors.Wrap(err, "saving", "nothing", nil)
+
+	_ = errors.New("saving", "err", err)
+	_ = errors.New("saving", "err", err, "close", closeErr) // want "2 errors passed in the pairs of a/errors.New; combine them with errors.Join"
+	_ = errors.New("saving", "err", err, "mine", &myErr{}) // want "2 errors passed in the pairs of a/errors.New; combine them with errors.Join"
+
+	// not configured as a wrap func
+	errors.Log("err", err, "close", closeErr)
+}
+`,
+		"a/errors/errors.go": `package errors
+
+func Wrap(err error, msg string, kv ...interface{}) error { return err }
+
+func New(msg string, kv ...interface{}) error { return nil }
+
+func Log(kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for _, f := range []string{"a/errors.Wrap=2", "a/errors.New=1", "a/errors.Log=0"} {
+		if err := a.Flags.Set("pair-func", f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range []string{"a/errors.Wrap", "a/errors.New"} {
+		if err := a.Flags.Set("wrap-func", f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
+
+func TestMixedWrap(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import (
+	"a/errors"
+	"fmt"
+)
+
+const format = "saving %s: %w"
+
+func Foo(err error, id string) {
+	_ = errors.Wrap(err, "saving", "id", id)
+	_ = errors.Wrap(fmt.Errorf("saving %s: %w", id, err), "saving", "id", id) // want "a/errors.Wrap wraps with %w and also takes pairs; add the context one way, not both"
+	_ = errors.Wrap((fmt.Errorf(format, id, err)), "saving", "id", id) // want "a/errors.Wrap wraps with %w and also takes pairs; add the context one way, not both"
+	_ = errors.Wrap(fmt.Errorf("saving %s: %w", id, err), "saving")
+	_ = errors.Wrap(fmt.Errorf("saving %s: %v", id, err), "saving", "id", id)
+
+	_ = errors.Wrapf(err, "saving %w", "id", id) // want "a/errors.Wrapf wraps with %w and also takes pairs; add the context one way, not both"
+	_ = errors.Wrapf(err, "saving", "id", id)
+
+	// not configured as exclusive
+	_ = errors.Annotate(fmt.Errorf("saving %s: %w", id, err), "id", id)
+}
+`,
+		"a/errors/errors.go": `package errors
+
+func Wrap(err error, msg string, kv ...interface{}) error { return err }
+
+func Wrapf(err error, format string, kv ...interface{}) error { return err }
+
+func Annotate(err error, kv ...interface{}) error { return err }
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for _, f := range []string{"a/errors.Wrap=2", "a/errors.Wrapf=2", "a/errors.Annotate=1"} {
+		if err := a.Flags.Set("pair-func", f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range []string{"a/errors.Wrap", "a/errors.Wrapf"} {
+		if err := a.Flags.Set("exclusive-wrap-func", f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
@@ -0,0 +1,67 @@
+package pairs_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/txtar"
+
+	"github.com/ZipRecruiter/splinter/pairs"
+	"github.com/ZipRecruiter/splinter/pairs/pairstest"
+)
+
+func TestExamples(t *testing.T) {
+	pairstest.RunGolden(t, "testdata/examples")
+}
+
+// TestExamplesCoverage checks that every rule is reported by, and every
+// preset set by, some example.
+func TestExamplesCoverage(t *testing.T) {
+	archives, err := filepath.Glob("testdata/examples/*.txtar")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules := map[string]bool{}
+	presets := map[string]bool{}
+	for _, archive := range archives {
+		ar, err := txtar.ParseFile(archive)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range strings.Split(string(ar.Comment), "\n") {
+			if p, ok := strings.CutPrefix(strings.TrimSpace(line), "-preset="); ok {
+				presets[p] = true
+			}
+		}
+		for _, f := range ar.Files {
+			if f.Name != "golden" {
+				continue
+			}
+			for _, line := range strings.Split(string(f.Data), "\n") {
+				// file:line:col: rule: message
+				if fields := strings.SplitN(line, ": ", 3); len(fields) == 3 {
+					rules[fields[1]] = true
+				}
+			}
+		}
+	}
+
+	for _, rule := range pairs.Rules {
+		if !rules[rule] {
+			t.Errorf("no example reports %s", rule)
+		}
+	}
+	for _, p := range pairs.Presets {
+		if !presets[p.Name] {
+			t.Errorf("no example sets -preset=%s", p.Name)
+		}
+	}
+}
+
+// TestOverlaps checks that a call several selectors match, of pair funcs
+// and builder funcs, is reported once.
+func TestOverlaps(t *testing.T) {
+	pairstest.RunGolden(t, "testdata/overlaps")
+}
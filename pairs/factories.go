@@ -0,0 +1,177 @@
+package pairs
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// loggerFactories maps the funcs declared in a package that return a pair
+// func, like a helper returning base.Log or a func literal spreading its
+// pairs into it, to the offset of the pairs of the func they return.
+type loggerFactories map[*types.Func]int
+
+// findFactories returns the logger factories declared in the package: funcs
+// returning a single func whose last param is ...interface{}, where every
+// return statement returns either a pair func taking its pairs there or a
+// func literal passing them on to one.
+func (c *checker) findFactories(p *analysis.Pass) loggerFactories {
+	factories := loggerFactories{}
+	for _, f := range p.Files {
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Body == nil {
+				continue
+			}
+			fn, ok := p.TypesInfo.Defs[fd.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+			results := fn.Type().(*types.Signature).Results()
+			if results.Len() != 1 {
+				continue
+			}
+			sig, ok := results.At(0).Type().Underlying().(*types.Signature)
+			if !ok {
+				continue
+			}
+			want, ok := pairsParam(sig)
+			if !ok {
+				continue
+			}
+
+			returns := 0
+			agree := true
+			ast.Inspect(fd.Body, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.FuncLit:
+					return false // its returns are its own
+				case *ast.ReturnStmt:
+					returns++
+					if len(n.Results) != 1 {
+						agree = false
+					} else if offset, ok := c.returnedOffset(p, n.Results[0]); !ok || offset != want {
+						agree = false
+					}
+				}
+				return agree
+			})
+			if returns != 0 && agree {
+				factories[fn] = want
+			}
+		}
+	}
+	return factories
+}
+
+// returnedOffset returns the offset of the pairs of e, a func returned by a
+// possible logger factory, if it's a pair func value or a func literal
+// spreading its ...interface{} param into the pairs of a pair func.
+func (c *checker) returnedOffset(p *analysis.Pass, e ast.Expr) (int, bool) {
+	e = ast.Unparen(e)
+	lit, ok := e.(*ast.FuncLit)
+	if !ok {
+		// a func value, checked as though it were called
+		call := &ast.CallExpr{Fun: e}
+		sels, _, ok := c.callSelectors(p.TypesInfo, call)
+		if !ok {
+			return 0, false
+		}
+		_, offset, _, ok := c.pairOffset(p, sels, call)
+		return offset, ok
+	}
+
+	sig, ok := p.TypesInfo.TypeOf(lit).(*types.Signature)
+	if !ok {
+		return 0, false
+	}
+	offset, ok := pairsParam(sig)
+	if !ok {
+		return 0, false
+	}
+	pairs := sig.Params().At(offset)
+
+	spread := false
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || !call.Ellipsis.IsValid() || spread {
+			return !spread
+		}
+		id, ok := ast.Unparen(call.Args[len(call.Args)-1]).(*ast.Ident)
+		if !ok || p.TypesInfo.Uses[id] != pairs {
+			return true
+		}
+		sels, _, ok := c.callSelectors(p.TypesInfo, call)
+		if !ok {
+			return true
+		}
+		if _, o, _, ok := c.pairOffset(p, sels, call); ok && o == len(call.Args)-1 {
+			spread = true
+		}
+		return !spread
+	})
+	return offset, spread
+}
+
+// factoryCall returns the offset of the pairs of call, and the name to
+// report it by, if it calls a func returned by one of factories, either
+// directly or through a local variable holding it that's never assigned
+// again.
+func (f loggerFactories) factoryCall(p *analysis.Pass, call *ast.CallExpr) (int, string, bool) {
+	if len(f) == 0 {
+		return 0, "", false
+	}
+
+	fun := ast.Unparen(call.Fun)
+	if id, ok := fun.(*ast.Ident); ok {
+		v, ok := p.TypesInfo.Uses[id].(*types.Var)
+		if !ok {
+			return 0, "", false
+		}
+		value, body, ok := declaredValue(p, v)
+		if !ok || value == nil || reassigned(p, v, body) {
+			return 0, "", false
+		}
+		fun = ast.Unparen(value)
+	}
+
+	made, ok := fun.(*ast.CallExpr)
+	if !ok {
+		return 0, "", false
+	}
+	fn, ok := typeutil.Callee(p.TypesInfo, made).(*types.Func)
+	if !ok {
+		return 0, "", false
+	}
+	offset, ok := f[fn]
+	if !ok {
+		return 0, "", false
+	}
+	return offset, "the func returned by " + fn.FullName(), true
+}
+
+// reassigned returns true if v is assigned anywhere in body, or has its
+// address taken, so it may not hold the value it's declared with.
+func reassigned(p *analysis.Pass, v *types.Var, body ast.Node) bool {
+	is := func(e ast.Expr) bool {
+		id, ok := ast.Unparen(e).(*ast.Ident)
+		return ok && p.TypesInfo.Uses[id] == v
+	}
+
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				found = found || is(lhs)
+			}
+		case *ast.UnaryExpr:
+			found = found || n.Op == token.AND && is(n.X)
+		}
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,72 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestLoggerFactories(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import "a/log"
+
+func makeLogger(base *log.Logger) func(...interface{}) {
+	return base.Log
+}
+
+func withPrefix(base *log.Logger, prefix string) func(string, ...interface{}) {
+	return func(msg string, kv ...interface{}) {
+		base.Info(prefix+msg, kv...)
+	}
+}
+
+func notAFactory(base *log.Logger) func(...interface{}) {
+	return func(kv ...interface{}) {
+		base.Log(append(kv, "extra")...)
+	}
+}
+
+func Foo(base *log.Logger, other func(...interface{})) {
+	l := makeLogger(base)
+	l("a", 1)
+	l("a", 1, 2) // want "3 args passed to the func returned by a.makeLogger; must be even"
+	makeLogger(base)("a", 1, 2) // want "3 args passed to the func returned by a.makeLogger; must be even"
+
+	info := withPrefix(base, "job: ")
+	info("started", "a", 1)
+	info("started", 1, 2) // want "arg 1 to the func returned by a.withPrefix is constant int but should be a constant string"
+
+	notAFactory(base)("a", 1, 2)
+
+	reassigned := makeLogger(base)
+	reassigned = other
+	reassigned("a", 1, 2)
+}
+`,
+		"a/log/log.go": `package log
+
+type Logger struct{}
+
+func (*Logger) Log(kv ...interface{}) {}
+
+func (*Logger) Info(msg string, kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for _, f := range []string{"a/log.Logger.Log=0", "a/log.Logger.Info=1"} {
+		if err := a.Flags.Set("pair-func", f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
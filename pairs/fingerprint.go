@@ -0,0 +1,30 @@
+package pairs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"time"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Fingerprint returns a hash of the effective configuration of a, an
+// analyzer from NewAnalyzer, or any other analyzer configured only by its
+// flags.  It changes whenever the value of any flag does, including those
+// set by presets, so drivers can key caches by it and report which policy
+// a run enforced.  With -key-sunset set, it also changes daily, as the
+// sunset keys it reports do.
+func Fingerprint(a *analysis.Analyzer) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n", a.Name)
+	a.Flags.VisitAll(func(f *flag.Flag) {
+		fmt.Fprintf(h, "-%s=%q\n", f.Name, f.Value)
+		// the effect of -key-sunset changes with the date
+		if s, ok := f.Value.(keySunsets); ok {
+			fmt.Fprintf(h, "%s\n", s.effective(time.Now()))
+		}
+	})
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
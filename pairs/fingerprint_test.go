@@ -0,0 +1,29 @@
+package pairs
+
+import "testing"
+
+func TestFingerprint(t *testing.T) {
+	a, b := NewAnalyzer(), NewAnalyzer()
+	if Fingerprint(a) != Fingerprint(b) {
+		t.Error("expected analyzers with the same flags to have the same fingerprint")
+	}
+
+	for _, v := range []string{".Log=0", ".Info=1"} {
+		if err := a.Flags.Set("pair-func", v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if Fingerprint(a) == Fingerprint(b) {
+		t.Error("expected a pair func to change the fingerprint")
+	}
+
+	// the order flags are set in doesn't matter
+	for _, v := range []string{".Info=1", ".Log=0"} {
+		if err := b.Flags.Set("pair-func", v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if Fingerprint(a) != Fingerprint(b) {
+		t.Error("expected the same pair funcs in another order to have the same fingerprint")
+	}
+}
@@ -0,0 +1,152 @@
+package pairs
+
+import (
+	"errors"
+	"go/ast"
+	"go/types"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/internal/calls"
+	"github.com/ZipRecruiter/splinter/internal/config"
+	"github.com/ZipRecruiter/splinter/internal/pkgpattern"
+)
+
+// protoPattern is the pattern of -forbid-value-type matching generated
+// protobuf messages, whatever their package.
+const protoPattern = "proto"
+
+// forbiddenType is a pattern of types whose values mustn't be logged whole.
+type forbiddenType struct {
+	pattern string
+	ptr     bool           // only pointers to the type
+	pkg     *regexp.Regexp // nil for protoPattern
+	typ     string         // * for any type in the package
+}
+
+// forbiddenTypes are the types whose values mustn't be passed as values.
+// It is a flag.Value accepting [*]<pkg>.<type>, where the package may
+// contain ... wildcards and the type may be * for any type of the package,
+// or proto for generated protobuf messages.  Without the leading *, both
+// the type and pointers to it match.
+type forbiddenTypes []forbiddenType
+
+var forbiddenTypeMatcher = regexp.MustCompile(`^(\*?)(.*?)\.([^\./]+|\*)$`)
+
+func (f *forbiddenTypes) Set(v string) error {
+	v, remove := config.Removal(v)
+	if remove {
+		for i := 0; i < len(*f); i++ {
+			if (*f)[i].pattern == v {
+				*f = append((*f)[:i], (*f)[i+1:]...)
+				i--
+			}
+		}
+		return nil
+	}
+
+	if v == protoPattern {
+		*f = append(*f, forbiddenType{pattern: v})
+		return nil
+	}
+	m := forbiddenTypeMatcher.FindStringSubmatch(v)
+	if len(m) != 4 || m[2] == "" {
+		return errors.New("invalid type pattern; should be of form [*]<pkg>.<type> or " + protoPattern)
+	}
+	*f = append(*f, forbiddenType{pattern: v, ptr: m[1] == "*", pkg: pkgpattern.Regexp(m[2]), typ: m[3]})
+	return nil
+}
+
+func (f *forbiddenTypes) String() string {
+	if f == nil {
+		return ""
+	}
+	var s []string
+	for _, t := range *f {
+		s = append(s, t.pattern)
+	}
+	return strings.Join(s, ",")
+}
+
+// match returns the pattern matching typ, if any.
+func (f forbiddenTypes) match(typ types.Type) (string, bool) {
+	ptr, isPtr := typ.(*types.Pointer)
+	if isPtr {
+		typ = ptr.Elem()
+	}
+	named, ok := types.Unalias(typ).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return "", false
+	}
+
+	for _, t := range f {
+		if t.pkg == nil {
+			if isProtoMessage(named) {
+				return t.pattern, true
+			}
+			continue
+		}
+		if t.ptr && !isPtr {
+			continue
+		}
+		if (t.typ == "*" || t.typ == named.Obj().Name()) && t.pkg.MatchString(calls.PkgPath(named.Obj().Pkg())) {
+			return t.pattern, true
+		}
+	}
+	return "", false
+}
+
+// isProtoMessage returns true if named is a generated protobuf message,
+// whose pointer has a ProtoReflect method.
+func isProtoMessage(named *types.Named) bool {
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return false
+	}
+	sel := types.NewMethodSet(types.NewPointer(named)).Lookup(named.Obj().Pkg(), "ProtoReflect")
+	return sel != nil
+}
+
+// maxSuggestedFields is how many fields forbiddenValueCorrect suggests.
+const maxSuggestedFields = 3
+
+// forbiddenValueCorrect reports a, the value at arg i of a call to name, if
+// its type is forbidden, suggesting some of its exported fields instead.
+func (c *checker) forbiddenValueCorrect(p *analysis.Pass, name string, i int, a ast.Expr) {
+	typ := p.TypesInfo.TypeOf(a)
+	if typ == nil {
+		return
+	}
+	if _, ok := c.forbiddenTypes.match(typ); !ok {
+		return
+	}
+
+	var fields []string
+	if s, ok := typ.Underlying().(*types.Struct); ok {
+		fields = exportedFields(s)
+	} else if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+		if s, ok := ptr.Elem().Underlying().(*types.Struct); ok {
+			fields = exportedFields(s)
+		}
+	}
+
+	msg := "arg %d to %s is a whole %s, which can be large or sensitive; log specific fields of it instead"
+	args := []interface{}{i, name, types.TypeString(typ, types.RelativeTo(p.Pkg))}
+	if len(fields) != 0 {
+		msg += ", like %s"
+		args = append(args, strings.Join(fields, ", "))
+	}
+	c.report(p, a, ForbiddenValue, msg, args...)
+}
+
+// exportedFields returns the first few exported fields of s, as selectors.
+func exportedFields(s *types.Struct) []string {
+	var fields []string
+	for i := 0; i < s.NumFields() && len(fields) < maxSuggestedFields; i++ {
+		if f := s.Field(i); f.Exported() && !f.Embedded() {
+			fields = append(fields, "."+f.Name())
+		}
+	}
+	return fields
+}
@@ -0,0 +1,75 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestForbiddenValueTypes(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import (
+	"net/http"
+
+	"a/log"
+	"a/pb"
+	"a/secret"
+)
+
+func Foo(r *http.Request, resp http.Response, u *pb.User, k secret.Key) {
+	log.Log("method", r.Method, "url", r.URL.String())
+	log.Log("request", r) // want "arg 1 to a/log.Log is a whole \\*net/http.Request, which can be large or sensitive; log specific fields of it instead, like .Method, .URL, .Proto"
+	log.Log("response", resp) // not a pointer
+	log.Log("user", u) // want "arg 1 to a/log.Log is a whole \\*a/pb.User, which can be large or sensitive; log specific fields of it instead, like .Name, .Email"
+	log.Log("key", k) // want "arg 1 to a/log.Log is a whole a/secret.Key, which can be large or sensitive; log specific fields of it instead"
+	log.Log("user_id", u.Id())
+}
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+`,
+		"a/pb/pb.go": `package pb
+
+type User struct {
+	state int
+	Name  string
+	Email string
+}
+
+func (u *User) ProtoReflect() int { return 0 }
+
+func (u *User) Id() int64 { return 0 }
+`,
+		"a/secret/secret.go": `package secret
+
+type Key string
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("pair-func", "a/log.Log=0"); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []string{"*net/http.Request", "*net/http.Response", protoPattern, "a/secret/....*", "a/nope.Thing", "!a/nope.Thing"} {
+		if err := a.Flags.Set("forbid-value-type", v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := a.Flags.Lookup("forbid-value-type").Value.String(); got != "*net/http.Request,*net/http.Response,proto,a/secret/....*" {
+		t.Errorf("forbid-value-type = %q; expected the removed pattern to be gone", got)
+	}
+	if err := a.Flags.Set("forbid-value-type", "Request"); err == nil {
+		t.Error("expected an error for a type without a package")
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
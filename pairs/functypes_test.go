@@ -0,0 +1,50 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestFuncTypes(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import "a/log"
+
+type Service struct {
+	logf log.LogFunc
+}
+
+func Foo(f log.LogFunc, s Service, id int) {
+	f("user_id", id)
+	f(id, "user_id") // want "arg 0 to a/log.LogFunc is expression int but should be a constant string"
+	s.logf("user_id") // want "1 args passed to a/log.LogFunc; must be even"
+	f.Log(id, "user_id") // want "arg 0 to method \\(a/log.LogFunc\\) Log\\(kv ...interface{}\\) is expression int but should be a constant string"
+	_ = log.LogFunc(func(kv ...interface{}) {})
+}
+`,
+		"a/log/log.go": `package log
+
+// LogFunc adapts a func to a logger.
+type LogFunc func(kv ...interface{})
+
+func (f LogFunc) Log(kv ...interface{}) { f(kv...) }
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for _, v := range []string{"a/log.LogFunc=0", ".Log=0"} {
+		if err := a.Flags.Set("pair-func", v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysistest.Run(t, dir, a, "a", "a/log")
+}
@@ -0,0 +1,125 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestStringTypeParamKeys(t *testing.T) {
+	src := `package a
+
+func Log(kv ...interface{}) {}
+
+type Keyish interface{ ~string }
+
+type Named interface {
+	Keyish
+	Name() string
+}
+
+func Generic[K ~string, S Keyish, N Named, U ~string | ~int, A any](k K, s S, n N, u U, a A) {
+	Log(k, 1)
+	Log(s, 1)
+	Log(n, 1)
+	Log(u, 1) // want "arg 0 to a.Log is expression U but should be a constant string"
+	Log(a, 1) // want "arg 0 to a.Log is expression A but should be a constant string"
+}
+`
+	strict := `package b
+
+func Log(kv ...interface{}) {}
+
+func Generic[K ~string](k K) {
+	Log(k, 1) // want "arg 0 to b.Log is expression K but should be a constant string"
+}
+`
+	dir, cleanup, err := analysistest.WriteFiles(map[string]string{"a/a.go": src, "b/b.go": strict})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("pair-func", "a.Log=0"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, dir, a, "a")
+
+	a = NewAnalyzer()
+	if err := a.Flags.Set("pair-func", "b.Log=0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Flags.Set("string-type-param-keys", "false"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, dir, a, "b")
+}
+
+func TestGenericHelpers(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import "a/log"
+
+type Logger interface{ Log(kv ...interface{}) }
+
+func logIt[L interface{ Log(...any) }](l L, kv ...any) { // want logIt:"helper=1"
+	l.Log(kv...)
+	l.Log("id") // want "1 args passed to method \\(L\\) Log\\(...any\\); must be even"
+}
+
+func logNamed[L Logger](l L, msg string, kv ...interface{}) { // want logNamed:"helper=2"
+	l.Log(append([]interface{}{"msg", msg}, kv...)...)
+	l.Log(kv...)
+}
+
+func notForwarded[L Logger](l L, kv ...interface{}) {
+	l.Log("n", len(kv))
+}
+
+func Foo(l *log.Logger) {
+	logIt(l, "id", 1)
+	logIt(l, "id") // want "2 args passed to a.logIt; must be even"
+	logNamed(l, "msg", "id") // want "3 args passed to a.logNamed; must be even"
+	notForwarded(l, "id")
+}
+`,
+		"b/b.go": `package b
+
+import (
+	"a"
+	"a/log"
+)
+
+func Foo(l *log.Logger) {
+	a.LogIt(l, 1, 2) // want "arg 1 to a.LogIt is constant int but should be a constant string"
+}
+`,
+		// before a.go, so LogIt is found before the helper it calls
+		"a/0export.go": `package a
+
+func LogIt[L interface{ Log(...any) }](l L, kv ...any) { // want LogIt:"helper=1"
+	logIt(l, kv...)
+}
+`,
+		"a/log/log.go": `package log
+
+type Logger struct{}
+
+func (*Logger) Log(kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("pair-func", ".Log=0"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, dir, a, "a", "b")
+}
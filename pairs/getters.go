@@ -0,0 +1,108 @@
+package pairs
+
+import (
+	"go/ast"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/internal/keys"
+)
+
+// WrittenKeys is a package fact recording the constant keys passed to pair
+// funcs and builder funcs in the package, so that keys read back by getter
+// funcs in packages importing it can be checked against them.
+type WrittenKeys struct {
+	Keys []string // sorted
+}
+
+// AFact implements analysis.Fact.
+func (*WrittenKeys) AFact() {}
+
+func (w *WrittenKeys) String() string { return strings.Join(w.Keys, " ") }
+
+// keyRead is a constant key read by a getter func.
+type keyRead struct {
+	name string // of the getter
+	arg  ast.Expr
+	key  string
+}
+
+// writtenKeys collects the keys written in a package and the keys read back
+// in it.  A nil *writtenKeys, when there are no getter funcs, collects
+// nothing.
+type writtenKeys struct {
+	written map[string]bool
+	reads   []keyRead
+}
+
+// add records the constant keys among pairs, ignoring a trailing key.
+func (w *writtenKeys) add(c *checker, p *analysis.Pass, pairs []ast.Expr) {
+	if w == nil {
+		return
+	}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if kind, _, k := c.classify(p, pairs[i]); kind == keys.Constant {
+			w.written[c.keyAliases.canonical(k)] = true
+		}
+	}
+}
+
+// read records the key read by call, to the getter func selected by sels
+// and reported as name, if it's a constant.
+func (w *writtenKeys) read(c *checker, p *analysis.Pass, name string, sels []funcSelector, call *ast.CallExpr) {
+	if w == nil {
+		return
+	}
+	for i := len(sels) - 1; i >= 0; i-- {
+		offset, ok := c.getterFuncs[sels[i]]
+		if !ok {
+			continue
+		}
+		if offset < len(call.Args) {
+			if kind, _, k := c.classify(p, call.Args[offset]); kind == keys.Constant {
+				w.reads = append(w.reads, keyRead{name: name, arg: call.Args[offset], key: k})
+			}
+		}
+		return
+	}
+}
+
+// report exports the keys written in the package as a WrittenKeys fact, and
+// reports the keys read that aren't written in the package or any of its
+// dependencies.
+func (w *writtenKeys) report(c *checker, p *analysis.Pass) {
+	if w == nil {
+		return
+	}
+
+	if len(w.written) != 0 {
+		fact := &WrittenKeys{}
+		for k := range w.written {
+			fact.Keys = append(fact.Keys, k)
+		}
+		sort.Strings(fact.Keys)
+		p.ExportPackageFact(fact)
+	}
+
+	if len(w.reads) == 0 {
+		return
+	}
+	written := map[string]bool{}
+	for k := range w.written {
+		written[k] = true
+	}
+	for _, f := range p.AllPackageFacts() {
+		if dep, ok := f.Fact.(*WrittenKeys); ok {
+			for _, k := range dep.Keys {
+				written[c.keyAliases.canonical(k)] = true
+			}
+		}
+	}
+	for _, r := range w.reads {
+		if !written[c.keyAliases.canonical(r.key)] {
+			c.report(p, r.arg, UnwrittenKey, "key %q read by %s is never written by a pair func in this package or its dependencies", r.key, r.name)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestGetterFuncs(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a // want package:"local"
+
+import (
+	"a/b"
+	"a/details"
+	"a/log"
+)
+
+const key = "user_id"
+
+func Foo(err error, name string) {
+	b.Bar()
+	log.Log("local", 1)
+
+	details.Value(err, "user_id")
+	details.Value(err, key)
+	details.Value(err, "local")
+	details.Value(err, "id") // from a builder in b
+	details.Value(err, name)
+	details.Value(err, "user_di") // want "key \"user_di\" read by a/details.Value is never written by a pair func in this package or its dependencies"
+}
+`,
+		"a/b/b.go": `package b
+
+import (
+	"a/details"
+	"a/log"
+)
+
+func Bar() {
+	log.Log("user_id", 1)
+	new(details.Builder).Add("id", 2)
+}
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+`,
+		"a/details/details.go": `package details
+
+func Value(err error, key string) interface{} { return nil }
+
+type Builder struct{}
+
+func (b *Builder) Add(k string, v interface{}) *Builder { return b }
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for flag, v := range map[string]string{
+		"pair-func":    "a/log.Log=0",
+		"builder-func": "a/details.Builder.Add",
+		"getter-func":  "a/details.Value=1",
+	} {
+		if err := a.Flags.Set(flag, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
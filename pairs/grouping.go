@@ -0,0 +1,52 @@
+package pairs
+
+import (
+	"fmt"
+	"go/ast"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// grouped returns p, or with -group-by-call a copy of it that holds back
+// the diagnostics reported for call, along with a func that reports them,
+// those of the same rule combined into one diagnostic spanning call.
+func (c *checker) grouped(p *analysis.Pass, call *ast.CallExpr) (*analysis.Pass, func()) {
+	if !c.groupByCall {
+		return p, func() {}
+	}
+
+	var diags []analysis.Diagnostic
+	held := *p
+	held.Report = func(d analysis.Diagnostic) { diags = append(diags, d) }
+
+	return &held, func() {
+		byRule := map[string][]analysis.Diagnostic{}
+		var rules []string
+		for _, d := range diags {
+			if byRule[d.Category] == nil {
+				rules = append(rules, d.Category)
+			}
+			byRule[d.Category] = append(byRule[d.Category], d)
+		}
+
+		for _, rule := range rules {
+			ds := byRule[rule]
+			if len(ds) == 1 {
+				p.Report(ds[0])
+				continue
+			}
+
+			g := analysis.Diagnostic{
+				Pos:      call.Pos(),
+				End:      call.End(),
+				Category: rule,
+				Message:  fmt.Sprintf("%s (and %d more in this call)", ds[0].Message, len(ds)-1),
+			}
+			for _, d := range ds {
+				g.Related = append(g.Related, analysis.RelatedInformation{Pos: d.Pos, End: d.End, Message: d.Message})
+				g.SuggestedFixes = append(g.SuggestedFixes, d.SuggestedFixes...)
+			}
+			p.Report(g)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package pairs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestGroupByCall(t *testing.T) {
+	src := `package a
+
+import "a/log"
+
+func Foo(id int, name string) {
+	log.Log(%s, id, %s, name, "user.id", id) // want "key \"UserID\" \\(arg 0 to a/log.Log\\) is not snake case \\(and 2 more in this call\\)"
+	log.Log(%s, id, 1, name) // want "key \"JobID\" \\(arg 0 to a/log.Log\\) is not snake case" "arg 2 to a/log.Log is constant int but should be a constant string"
+	log.Log("user_id", log.Keys(%s, 1, "b"), "a") // want "3 args passed to a/log.Keys; must be even" "key \"NestedKey\" \\(arg 0 to a/log.Keys\\) is not snake case" "3 args passed to a/log.Log; must be even"
+}
+`
+	filemap := map[string]string{
+		"a/a.go":        fmt.Sprintf(src, `"UserID"`, `"UserName"`, `"JobID"`, `"NestedKey"`),
+		"a/a.go.golden": fmt.Sprintf(src, `"user_id"`, `"user_name"`, `"job_id"`, `"nested_key"`),
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+
+func Keys(kv ...interface{}) []string { return nil }
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for _, f := range []string{"a/log.Log=0", "a/log.Keys=0"} {
+		if err := a.Flags.Set("pair-func", f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for flag, v := range map[string]string{
+		"key-case":      "snake",
+		"group-by-call": "true",
+	} {
+		if err := a.Flags.Set(flag, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results := analysistest.RunWithSuggestedFixes(t, dir, a, "a")
+	var related []int
+	for _, d := range results[0].Diagnostics {
+		related = append(related, len(d.Related))
+	}
+	// each also points at the declaration of the pair func
+	if d := cmp.Diff([]int{4, 1, 1, 1, 1, 1}, related); d != "" {
+		t.Errorf("unexpected related entries (-expected +got):\n%s", d)
+	}
+}
@@ -0,0 +1,106 @@
+package pairs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// HelperFunc is an object fact marking a generic func that spreads its
+// variadic ...interface{} param, at Offset, into the pairs of a pair func,
+// like a helper generic over a logger constraint:
+//
+//	func logIt[L interface{ Log(...any) }](l L, kv ...any) { l.Log(kv...) }
+//
+// Calls to it are checked as calls to a pair func in every package that
+// imports it.
+type HelperFunc struct {
+	Offset int
+}
+
+// AFact implements analysis.Fact.
+func (*HelperFunc) AFact() {}
+
+func (f *HelperFunc) String() string {
+	return fmt.Sprintf("helper=%d", f.Offset)
+}
+
+// exportHelpers exports a HelperFunc fact for each generic func declared in
+// the package whose last param is ...interface{} and which spreads it into
+// the pairs of a pair func, which the methods of the constraints of its type
+// params can be.
+func (c *checker) exportHelpers(p *analysis.Pass) {
+	type candidate struct {
+		fn     *types.Func
+		body   *ast.BlockStmt
+		offset int
+	}
+	var candidates []candidate
+	for _, f := range p.Files {
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Body == nil || fd.Type.TypeParams == nil {
+				continue
+			}
+			fn, ok := p.TypesInfo.Defs[fd.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+			if offset, ok := pairsParam(fn.Type().(*types.Signature)); ok {
+				candidates = append(candidates, candidate{fn, fd.Body, offset})
+			}
+		}
+	}
+
+	// until no more are found, since helpers may spread their pairs into
+	// other helpers
+	for found := true; found; {
+		found = false
+		for i := 0; i < len(candidates); i++ {
+			cand := candidates[i]
+			kv := cand.fn.Type().(*types.Signature).Params().At(cand.offset)
+
+			spreads := false
+			ast.Inspect(cand.body, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok || !call.Ellipsis.IsValid() {
+					return !spreads
+				}
+				if id, ok := ast.Unparen(call.Args[len(call.Args)-1]).(*ast.Ident); !ok || p.TypesInfo.Uses[id] != kv {
+					return true
+				}
+				sels, _, ok := c.callSelectors(p.TypesInfo, call)
+				if !ok {
+					return true
+				}
+				if _, o, _, ok := c.pairOffset(p, sels, call); ok && o == len(call.Args)-1 {
+					spreads = true
+				}
+				return !spreads
+			})
+			if spreads {
+				p.ExportObjectFact(cand.fn, &HelperFunc{Offset: cand.offset})
+				candidates = append(candidates[:i], candidates[i+1:]...)
+				i--
+				found = true
+			}
+		}
+	}
+}
+
+// helperOffset returns the offset of the pairs of the helper called by call,
+// if it calls one.
+func (c *checker) helperOffset(p *analysis.Pass, call *ast.CallExpr) (int, bool) {
+	fn, ok := typeutil.Callee(p.TypesInfo, call).(*types.Func)
+	if !ok {
+		return 0, false
+	}
+	var helper HelperFunc
+	if !p.ImportObjectFact(fn.Origin(), &helper) {
+		return 0, false
+	}
+	return helper.Offset, true
+}
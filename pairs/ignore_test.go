@@ -0,0 +1,225 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestIgnoreDirective(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import "a/log"
+
+func Foo(key interface{}, id int) {
+	log.Log(key, id) //splinter:ignore keys come from the legacy schema
+	log.Log(key, id) // want "arg 0 to a/log.Log is expression interface{} but should be a constant string"
+
+	//splinter:ignore legacy API
+	log.Log("id", id,
+		"name")
+	log.Log("id") // want "1 args passed to a/log.Log; must be even"
+
+	log.Log(
+		"id",
+	) //splinter:ignore
+
+	log.Info(log.Log("id")) //splinter:ignore covers the calls within
+
+	// splinter:ignore isn't the directive with a space
+	log.Log("id") // want "1 args passed to a/log.Log; must be even"
+	log.Log("id") //splinter:ignored isn't either // want "1 args passed to a/log.Log; must be even"
+}
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) string { return "" }
+
+func Info(msg string, kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("pair-func", "a/log.Log=0"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
+
+func TestNolint(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import "a/log"
+
+func Foo() {
+	log.Log("id") //nolint
+	log.Log("id") //nolint:splinter // migrated from golangci-lint
+	log.Log("id") //nolint:errcheck,pairs
+	log.Log("id") //nolint:all
+
+	//nolint:splinter
+	log.Log("id")
+
+	log.Log("id") //nolint:errcheck // want "1 args passed to a/log.Log; must be even"
+	log.Log("id") //nolintfoo // want "1 args passed to a/log.Log; must be even"
+}
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("pair-func", "a/log.Log=0"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
+
+func TestGenerated(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import "a/log"
+
+func Foo() {
+	log.Log("id") // want "1 args passed to a/log.Log; must be even"
+}
+`,
+		"a/mock.go": `// Code generated by MockGen. DO NOT EDIT.
+
+package a
+
+import "a/log"
+
+func Mock() {
+	log.Log("id")
+}
+`,
+		"b/b.go": `package b
+
+import "a/log"
+
+func Foo() {
+	log.Log("id") // want "1 args passed to a/log.Log; must be even"
+}
+`,
+		"b/mock.go": `// Code generated by MockGen. DO NOT EDIT.
+
+package b
+
+import "a/log"
+
+func Mock() {
+	log.Log("id") // want "1 args passed to a/log.Log; must be even"
+}
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("pair-func", "a/log.Log=0"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, dir, a, "a")
+
+	if err := a.Flags.Set("check-generated", "true"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, dir, a, "b")
+}
+
+func TestSkipTests(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import "a/log"
+
+func Foo() {
+	log.Log("id") // want "1 args passed to a/log.Log; must be even"
+}
+`,
+		"a/a_test.go": `package a
+
+import "a/log"
+
+func helper() {
+	log.Log("id")
+}
+`,
+		"a/x_test.go": `package a_test
+
+import "a/log"
+
+func helper() {
+	log.Log("id") // want "1 args passed to a/log.Log; must be even"
+}
+`,
+		"b/b_test.go": `package b
+
+import "a/log"
+
+func helper() {
+	log.Log("id")
+}
+`,
+		"b/x_test.go": `package b_test
+
+import "a/log"
+
+func helper() {
+	log.Log("id")
+}
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("pair-func", "a/log.Log=0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Flags.Set("skip-tests", "true"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, dir, a, "a")
+
+	if err := a.Flags.Set("skip-tests", "all"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, dir, a, "b")
+}
@@ -0,0 +1,74 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestImportAliases(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import (
+	"a/b"
+	bb "a/b"
+	. "a/b"
+	_ "a/c"
+	log "a/other/b"
+)
+
+func Foo() {
+	b.X("", "frew") // want "2 args passed to a/b.X; must be even"
+	bb.X("", "frew") // want "2 args passed to a/b.X; must be even"
+	X("", "frew") // want "2 args passed to a/b.X; must be even"
+	X[int](0, "frew") // want "2 args passed to a/b.X; must be even"
+
+	// same name and func, different package
+	log.X("", "frew")
+}
+`,
+		// the aliases are swapped relative to a.go
+		"a/swapped.go": `package a
+
+import (
+	b "a/other/b"
+	log "a/b"
+)
+
+func Bar() {
+	b.X("", "frew")
+	log.X("", "frew") // want "2 args passed to a/b.X; must be even"
+}
+`,
+		"a/b/b.go": `package b
+
+func X[T any](a T, inputs ...interface{}) {}
+
+func Y() {
+	X("", "frew") // want "2 args passed to a/b.X; must be even"
+}
+`,
+		"a/c/c.go": `package c
+
+func X(a string, inputs ...interface{}) {}
+`,
+		"a/other/b/b.go": `package b
+
+func X(a string, inputs ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("pair-func", "a/b.X=1"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, dir, a, "a", "a/b")
+}
@@ -34,6 +34,43 @@ func TestFuncOffset(t *testing.T) {
 			if d := cmp.Diff(test.out, f); d != "" {
 				t.Errorf("unexpected result (-expected +got):\n%s", d)
 			}
+			if got := f.String(); got != test.in {
+				t.Errorf("String() = %q; expected %q", got, test.in)
+			}
+		})
+	}
+}
+
+func TestFuncSet(t *testing.T) {
+	type Test struct {
+		in  string
+		out funcSet
+		err string
+	}
+
+	tests := []Test{
+		{".Inc", funcSet{funcSelector{fun: "Inc"}: true}, ""},
+		{"database/sql.Rows.Close", funcSet{funcSelector{pkg: "database/sql", typ: "Rows", fun: "Close"}: true}, ""},
+		{"sync/atomic.AddInt64", funcSet{funcSelector{pkg: "sync/atomic", fun: "AddInt64"}: true}, ""},
+		{"wrong", nil, "invalid func; should be of form [pkg[.type]].<func>"},
+		{".Log=0", nil, "invalid func; should be of form [pkg[.type]].<func>"},
+	}
+
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			s := funcSet{}
+			if err := s.Set(test.in); err != nil {
+				if d := cmp.Diff(test.err, err.Error()); d != "" {
+					t.Errorf("unexpected error (-expected +got):\n%s", d)
+				}
+				return
+			}
+			if d := cmp.Diff(test.out, s); d != "" {
+				t.Errorf("unexpected result (-expected +got):\n%s", d)
+			}
+			if got := s.String(); got != test.in {
+				t.Errorf("String() = %q; expected %q", got, test.in)
+			}
 		})
 	}
 }
@@ -0,0 +1,81 @@
+package pairs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/internal/keys"
+)
+
+// KeyUse summarizes the uses of one constant key in a package.
+type KeyUse struct {
+	Key  string
+	Uses int
+
+	// ValueTypes counts the values passed with the key by type.
+	ValueTypes map[string]int
+}
+
+// KeyInventory is a package fact recording the constant keys passed to pair
+// funcs and builder funcs in the package, so drivers can take stock of the
+// keys a codebase logs.
+type KeyInventory struct {
+	Keys []KeyUse // sorted by Key
+}
+
+// AFact implements analysis.Fact.
+func (*KeyInventory) AFact() {}
+
+func (inv *KeyInventory) String() string {
+	var s []string
+	for _, k := range inv.Keys {
+		s = append(s, fmt.Sprintf("%s:%d", k.Key, k.Uses))
+	}
+	return strings.Join(s, " ")
+}
+
+type keyInventory map[string]*KeyUse
+
+// add records the pair of key and value, if key is a constant string.
+func (inv keyInventory) add(p *analysis.Pass, key, value ast.Expr) {
+	kind, _, k := keys.Classify(p.TypesInfo, key)
+	if kind != keys.Constant {
+		return
+	}
+
+	use := inv[k]
+	if use == nil {
+		use = &KeyUse{Key: k, ValueTypes: map[string]int{}}
+		inv[k] = use
+	}
+	use.Uses++
+	if t := p.TypesInfo.TypeOf(value); t != nil {
+		use.ValueTypes[types.TypeString(types.Default(t), nil)]++
+	}
+}
+
+// addPairs records the pairs of pairs, ignoring a trailing key.
+func (inv keyInventory) addPairs(p *analysis.Pass, pairs []ast.Expr) {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		inv.add(p, pairs[i], pairs[i+1])
+	}
+}
+
+// export exports the keys as a KeyInventory fact, if there are any.
+func (inv keyInventory) export(p *analysis.Pass) {
+	if len(inv) == 0 {
+		return
+	}
+
+	fact := &KeyInventory{}
+	for _, use := range inv {
+		fact.Keys = append(fact.Keys, *use)
+	}
+	sort.Slice(fact.Keys, func(i, j int) bool { return fact.Keys[i].Key < fact.Keys[j].Key })
+	p.ExportPackageFact(fact)
+}
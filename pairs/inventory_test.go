@@ -0,0 +1,58 @@
+package pairs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestKeyInventory(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a // want package:"id:3 job:1 name:1"
+
+import "a/log"
+
+const keyName = "name"
+
+func Foo(b *log.Builder, key string, id int) {
+	log.Log("id", id, "job", "engineer")
+	log.Log("id", "frew", keyName, "frew", key, 1)
+	log.Log("id") // want "1 args passed to a/log.Log; must be even"
+	b.Add("id", 2.5)
+}
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+
+type Builder struct{}
+
+func (b *Builder) Add(k string, v interface{}) *Builder { return b }
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for flag, v := range map[string]string{
+		"pair-func":    "a/log.Log=0",
+		"builder-func": "a/log.Builder.Add",
+		"inventory":    "true",
+	} {
+		if err := a.Flags.Set(flag, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results := analysistest.Run(t, dir, a, "a")
+
+	inv := results[0].Facts[nil][0].(*KeyInventory)
+	if d := cmp.Diff(map[string]int{"int": 1, "string": 1, "float64": 1}, inv.Keys[0].ValueTypes); d != "" {
+		t.Errorf("unexpected value types of id (-expected +got):\n%s", d)
+	}
+}
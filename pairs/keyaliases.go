@@ -0,0 +1,89 @@
+package pairs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/internal/config"
+)
+
+// keyAliases maps the deprecated spellings of keys to the keys they're
+// aliases of, for a migration between spellings.  It is a flag.Value
+// accepting <deprecated>=<key>.
+type keyAliases map[string]string
+
+func (k keyAliases) Set(v string) error {
+	if v, ok := config.Removal(v); ok {
+		if i := strings.IndexByte(v, '='); i >= 0 {
+			v = v[:i]
+		}
+		delete(k, v)
+		return nil
+	}
+
+	deprecated, key, ok := strings.Cut(v, "=")
+	if !ok || deprecated == "" || key == "" {
+		return fmt.Errorf("invalid key alias %q; should be of form <deprecated>=<key>", v)
+	}
+	if deprecated == key {
+		return fmt.Errorf("invalid key alias %q; a key can't be an alias of itself", v)
+	}
+	if _, ok := k[key]; ok {
+		return fmt.Errorf("invalid key alias %q; %q is itself deprecated", v, key)
+	}
+	for other, canonical := range k {
+		if canonical == deprecated {
+			return fmt.Errorf("invalid key alias %q; %q is an alias of it", v, other)
+		}
+	}
+	k[deprecated] = key
+	return nil
+}
+
+func (k keyAliases) String() string {
+	var s []string
+	for deprecated, key := range k {
+		s = append(s, deprecated+"="+key)
+	}
+	sort.Strings(s)
+	return strings.Join(s, ",")
+}
+
+// canonical returns the key that key is an alias of, or key if it isn't
+// one.
+func (k keyAliases) canonical(key string) string {
+	if canonical, ok := k[key]; ok {
+		return canonical
+	}
+	return key
+}
+
+// deprecatedKeyCorrect reports key, the constant at arg i of a call to name,
+// which is a deprecated alias of canonical.  If it's a literal, a fix
+// replacing it is suggested.
+func (c *checker) deprecatedKeyCorrect(p *analysis.Pass, name string, i int, a ast.Expr, key, canonical string) {
+	d := analysis.Diagnostic{
+		Pos:      a.Pos(),
+		End:      a.End(),
+		Category: DeprecatedKey,
+		Message:  fmt.Sprintf("key %q (arg %d to %s) is a deprecated alias of %q", key, i, name, canonical),
+	}
+
+	if lit, ok := ast.Unparen(a).(*ast.BasicLit); ok && lit.Kind == token.STRING {
+		quoted := strconv.Quote(canonical)
+		if strings.HasPrefix(lit.Value, "`") && !strings.Contains(canonical, "`") {
+			quoted = "`" + canonical + "`"
+		}
+		d.SuggestedFixes = []analysis.SuggestedFix{{
+			Message:   fmt.Sprintf("Replace key with %q", canonical),
+			TextEdits: []analysis.TextEdit{{Pos: lit.Pos(), End: lit.End(), NewText: []byte(quoted)}},
+		}}
+	}
+	p.Report(d)
+}
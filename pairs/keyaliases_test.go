@@ -0,0 +1,73 @@
+package pairs
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestKeyAliases(t *testing.T) {
+	src := `package a // want package:"span_id trace_id"
+
+import "a/log"
+
+const traceKey = "traceID"
+
+func Foo(b *log.Builder, id, other int) {
+	log.Log("trace_id", id)
+	log.Log(%s, id) // want "key \"traceID\" \\(arg 0 to a/log.Log\\) is a deprecated alias of \"trace_id\""
+	log.Log(traceKey, id) // want "key \"traceID\" \\(arg 0 to a/log.Log\\) is a deprecated alias of \"trace_id\""
+
+	// the same key to the duplicate and consistency checks
+	b.Add("trace_id", id)
+	b.Add(%s, other) // want "key \"traceID\" \\(arg 0 to .*\\) is a deprecated alias of \"trace_id\"" "key \"trace_id\" passed to .* was already added on line 13"
+	log.Log("trace_id", id, %s, id) // want "key \"traceID\" \\(arg 2 to a/log.Log\\) is a deprecated alias of \"trace_id\""
+	log.Log("trace_id", id, "span_id", id) // want "arg 3 to a/log.Log is id, the same value as arg 1 under another key"
+	log.Get("traceID")
+	log.Get("user_id") // want "key \"user_id\" read by a/log.Get is never written"
+}
+`
+	filemap := map[string]string{
+		"a/a.go":        fmt.Sprintf(src, `"traceID"`, "`traceID`", `"traceID"`),
+		"a/a.go.golden": fmt.Sprintf(src, `"trace_id"`, "`trace_id`", `"trace_id"`),
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+
+func Get(key string) interface{} { return nil }
+
+type Builder struct{}
+
+func (b *Builder) Add(key string, value interface{}) *Builder { return b }
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for _, f := range []struct{ name, value string }{
+		{"pair-func", "a/log.Log=0"},
+		{"builder-func", "a/log.Builder.Add"},
+		{"getter-func", "a/log.Get=0"},
+		{"duplicate-keys", "true"},
+		{"repeated-values", "true"},
+		{"key-case", "snake"},
+		{"key-alias", "traceID=trace_id"},
+	} {
+		if err := a.Flags.Set(f.name, f.value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, v := range []string{"trace_id=traceID", "trace_id=trace_id", "traceID", "spanID=traceID"} {
+		if err := a.Flags.Set("key-alias", v); err == nil {
+			t.Errorf("expected error for -key-alias %s", v)
+		}
+	}
+
+	analysistest.RunWithSuggestedFixes(t, dir, a, "a")
+}
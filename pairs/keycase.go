@@ -0,0 +1,161 @@
+package pairs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// keyCase is the canonical casing of keys.  It is a flag.Value accepting
+// snake, kebab or camel.
+type keyCase string
+
+var keyCasePatterns = map[keyCase]*regexp.Regexp{
+	"snake": regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`),
+	"kebab": regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`),
+	"camel": regexp.MustCompile(`^[a-z][a-z0-9]*([A-Z][a-z0-9]*)*$`),
+}
+
+func (k *keyCase) Set(v string) error {
+	if _, ok := keyCasePatterns[keyCase(v)]; !ok {
+		return fmt.Errorf("invalid key case %q; should be snake, kebab or camel", v)
+	}
+	*k = keyCase(v)
+	return nil
+}
+
+func (k *keyCase) String() string { return string(*k) }
+
+// convert returns key in casing k.  ok is false if key can't be split into
+// words unambiguously.
+func (k keyCase) convert(key string) (string, bool) {
+	words, ok := splitWords(key)
+	if !ok {
+		return "", false
+	}
+
+	switch k {
+	case "snake":
+		return strings.Join(words, "_"), true
+	case "kebab":
+		return strings.Join(words, "-"), true
+	case "camel":
+		for i := 1; i < len(words); i++ {
+			words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+		}
+		return strings.Join(words, ""), true
+	}
+	return "", false
+}
+
+// splitWords splits key into lower case words at underscores, dashes and
+// changes of case, treating runs of upper case letters as acronyms (so
+// UserID is user and id, and HTTPServer is http and server).  ok is false
+// if key has other characters, empty words, or an acronym followed by a
+// lone s, which could be a plural (IDs) or the start of a word.
+func splitWords(key string) (words []string, ok bool) {
+	var word []rune
+	flush := func() bool {
+		if len(word) == 0 {
+			return false
+		}
+		words = append(words, strings.ToLower(string(word)))
+		word = nil
+		return true
+	}
+
+	runes := []rune(key)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-':
+			if !flush() {
+				return nil, false
+			}
+		case unicode.IsUpper(r):
+			prevLower := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]))
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			prevUpper := i > 0 && unicode.IsUpper(runes[i-1])
+			if prevUpper && nextLower && i+2 == len(runes) && runes[i+1] == 's' {
+				return nil, false
+			}
+			if prevLower || (prevUpper && nextLower) {
+				flush()
+			}
+			word = append(word, r)
+		case unicode.IsLower(r) || unicode.IsDigit(r):
+			word = append(word, r)
+		default:
+			return nil, false
+		}
+	}
+	if !flush() {
+		return nil, false
+	}
+	return words, true
+}
+
+// regexpFlag is a flag.Value holding a compiled regexp.
+type regexpFlag struct{ *regexp.Regexp }
+
+func (r *regexpFlag) Set(v string) error {
+	re, err := regexp.Compile(v)
+	if err != nil {
+		return err
+	}
+	r.Regexp = re
+	return nil
+}
+
+func (r *regexpFlag) String() string {
+	if r.Regexp == nil {
+		return ""
+	}
+	return r.Regexp.String()
+}
+
+// keyPattern returns the pattern constant keys must match, if any.
+func (c *checker) keyPattern() *regexp.Regexp {
+	if c.keyRegexp.Regexp != nil {
+		return c.keyRegexp.Regexp
+	}
+	return keyCasePatterns[c.keyCase]
+}
+
+// keyPatternCorrect reports key, the constant at arg i of a call to name,
+// if it doesn't match the configured pattern.  If a key case is configured
+// and the key is a literal, a fix converting it is suggested.
+func (c *checker) keyPatternCorrect(p *analysis.Pass, name string, i int, a ast.Expr, key string) {
+	pattern := c.keyPattern()
+	if pattern == nil || pattern.MatchString(key) {
+		return
+	}
+
+	d := analysis.Diagnostic{
+		Pos:      a.Pos(),
+		End:      a.End(),
+		Category: KeyPattern,
+		Message:  fmt.Sprintf("key %q (arg %d to %s) does not match the key pattern %s", key, i, name, pattern),
+	}
+	if c.keyRegexp.Regexp == nil {
+		d.Message = fmt.Sprintf("key %q (arg %d to %s) is not %s case", key, i, name, c.keyCase)
+	}
+
+	lit, isLit := ast.Unparen(a).(*ast.BasicLit)
+	if fixed, ok := c.keyCase.convert(key); c.keyCase != "" && isLit && lit.Kind == token.STRING && ok && pattern.MatchString(fixed) {
+		quoted := strconv.Quote(fixed)
+		if strings.HasPrefix(lit.Value, "`") {
+			quoted = "`" + fixed + "`"
+		}
+		d.SuggestedFixes = []analysis.SuggestedFix{{
+			Message:   fmt.Sprintf("Rename key to %q", fixed),
+			TextEdits: []analysis.TextEdit{{Pos: lit.Pos(), End: lit.End(), NewText: []byte(quoted)}},
+		}}
+	}
+	p.Report(d)
+}
@@ -0,0 +1,117 @@
+package pairs
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestKeyCase(t *testing.T) {
+	src := `package a
+
+import "a/log"
+
+const userKey = "UserKey"
+
+func Foo(id int) {
+	log.Log("user_id", id)
+	log.Log(%s, id) // want "key \"UserID\" \\(arg 0 to a/log.Log\\) is not snake case"
+	log.Log(%s, id) // want "key \"HTTPServer\" \\(arg 0 to a/log.Log\\) is not snake case"
+	log.Log(%s, id) // want "key \"request-path\" \\(arg 0 to a/log.Log\\) is not snake case"
+
+	// ambiguous, or not a literal, so no fix
+	log.Log("userIDs", id) // want "key \"userIDs\" \\(arg 0 to a/log.Log\\) is not snake case"
+	log.Log("user.id", id) // want "key \"user.id\" \\(arg 0 to a/log.Log\\) is not snake case"
+	log.Log(userKey, id) // want "key \"UserKey\" \\(arg 0 to a/log.Log\\) is not snake case"
+}
+`
+	filemap := map[string]string{
+		"a/a.go":        fmt.Sprintf(src, `"UserID"`, "`HTTPServer`", `"request-path"`),
+		"a/a.go.golden": fmt.Sprintf(src, `"user_id"`, "`http_server`", `"request_path"`),
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("pair-func", "a/log.Log=0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Flags.Set("key-case", "snake"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.RunWithSuggestedFixes(t, dir, a, "a")
+}
+
+func TestKeyPattern(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import "a/log"
+
+func Foo(id int) {
+	log.Log("app.user_id", id)
+	log.Log("user_id", id) // want "key \"user_id\" \\(arg 0 to a/log.Log\\) does not match the key pattern \\^app\\\\."
+}
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("pair-func", "a/log.Log=0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Flags.Set("key-pattern", `^app\.`); err != nil {
+		t.Fatal(err)
+	}
+	// the fix would be user-id, which doesn't match, so none is offered
+	if err := a.Flags.Set("key-case", "kebab"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
+
+func TestKeyCaseConvert(t *testing.T) {
+	cases := []struct {
+		key   string
+		to    keyCase
+		fixed string
+		ok    bool
+	}{
+		{"UserID", "snake", "user_id", true},
+		{"userId", "snake", "user_id", true},
+		{"HTTPServer", "kebab", "http-server", true},
+		{"user_id", "camel", "userId", true},
+		{"page2Count", "snake", "page2_count", true},
+		{"userIDs", "snake", "", false},
+		{"user__id", "snake", "", false},
+		{"user id", "snake", "", false},
+		{"", "snake", "", false},
+	}
+
+	for _, c := range cases {
+		fixed, ok := c.to.convert(c.key)
+		if fixed != c.fixed || ok != c.ok {
+			t.Errorf("convert(%q) to %s = %q, %v; expected %q, %v", c.key, c.to, fixed, ok, c.fixed, c.ok)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package pairs
+
+import (
+	"fmt"
+	"go/ast"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/internal/calls"
+	"github.com/ZipRecruiter/splinter/internal/config"
+	"github.com/ZipRecruiter/splinter/internal/keys"
+)
+
+// levelNames lists the levels pair funcs can log at, lowest first.
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+// funcLevels maps pair funcs to the level they log at.  It is a flag.Value
+// accepting [pkg[.type]].<func>=<level>.
+type funcLevels map[funcSelector]string
+
+func (l funcLevels) Set(v string) error {
+	if v, ok := config.Removal(v); ok {
+		if i := strings.LastIndexByte(v, '='); i >= 0 {
+			v = v[:i]
+		}
+		sel, err := calls.ParseSelector(v)
+		if err != nil {
+			return err
+		}
+		delete(l, newFuncSelector(sel))
+		return nil
+	}
+
+	i := strings.LastIndexByte(v, '=')
+	if i < 0 {
+		return fmt.Errorf("invalid level %q; should be of form [pkg[.type]].<func>=<level>", v)
+	}
+	sel, err := calls.ParseSelector(v[:i])
+	if err != nil {
+		return err
+	}
+	level := v[i+1:]
+	found := false
+	for _, name := range levelNames {
+		found = found || level == name
+	}
+	if !found {
+		return fmt.Errorf("unknown level %q; should be one of %s", level, strings.Join(levelNames, ", "))
+	}
+
+	l[newFuncSelector(sel)] = level
+	return nil
+}
+
+func (l funcLevels) String() string {
+	var s []string
+	for sel, level := range l {
+		s = append(s, sel.String()+"="+level)
+	}
+	sort.Strings(s)
+	return strings.Join(s, ",")
+}
+
+// level returns the level of the func selected by sels, as returned by
+// callSelectors, if any; the most precise selector with a level wins.
+func (l funcLevels) level(sels []funcSelector) string {
+	for i := len(sels) - 1; i >= 0; i-- {
+		if level, ok := l[sels[i]]; ok {
+			return level
+		}
+	}
+	return ""
+}
+
+// leveled returns the selectors of pairFuncs, which are of the form taken by
+// -pair-func, annotated in the form taken by -level with the level their
+// names start with, like Infow or DebugContext; funcs whose names start with
+// no level, like With, are left out.
+func leveled(pairFuncs []string) []string {
+	prefixes := []struct{ prefix, level string }{
+		{"Trace", "debug"},
+		{"Debug", "debug"},
+		{"Info", "info"},
+		{"Warn", "warn"},
+		{"Error", "error"},
+		{"DPanic", "error"},
+		{"Panic", "error"},
+		{"Fatal", "error"},
+	}
+
+	var v []string
+	for _, f := range pairFuncs {
+		sel := f[:strings.LastIndexByte(f, '=')]
+		name := sel[strings.LastIndexByte(sel, '.')+1:]
+		for _, p := range prefixes {
+			if strings.HasPrefix(name, p.prefix) {
+				v = append(v, sel+"="+p.level)
+				break
+			}
+		}
+	}
+	return v
+}
+
+// debugKeysCorrect reports the constant keys among args, the pairs of a call
+// to name starting at arg offset, that are debug-only, since name logs at
+// level, above debug.
+func (c *checker) debugKeysCorrect(p *analysis.Pass, name, level string, offset int, args []ast.Expr) {
+	for i := 0; i < len(args); i += 2 {
+		if kind, _, key := c.classify(p, args[i]); kind == keys.Constant && c.debugKeys[key] {
+			c.report(p, args[i], DebugKey, "debug-only key %q (arg %d to %s) is logged at %s level", key, i+offset, name, level)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package pairs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestDebugKeys(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import "a/log"
+
+const dump = "request_dump"
+
+func Foo(l *log.Logger, b *log.Builder) {
+	l.Debug("msg", "request_dump", 1, "id", 2)
+	l.Info("msg", "id", 2)
+	l.Info("msg", dump, 1) // want "debug-only key \"request_dump\" \\(arg 1 to method \\(\\*a/log.Logger\\) Info\\(msg string, kv ...interface{}\\)\\) is logged at info level"
+	l.Error("msg", "id", 2, "sql", 3) // want "debug-only key \"sql\" \\(arg 3 to method \\(\\*a/log.Logger\\) Error\\(msg string, kv ...interface{}\\)\\) is logged at error level"
+	l.Log("request_dump", 1) // no level
+	b.Add("sql", 3) // want "debug-only key \"sql\" \\(arg 0 to method \\(\\*a/log.Builder\\) Add\\(k string, v interface{}\\) \\*a/log.Builder\\) is logged at warn level"
+}
+`,
+		"a/log/log.go": `package log
+
+type Logger struct{}
+
+func (l *Logger) Debug(msg string, kv ...interface{}) {}
+func (l *Logger) Info(msg string, kv ...interface{})  {}
+func (l *Logger) Error(msg string, kv ...interface{}) {}
+func (l *Logger) Log(kv ...interface{})               {}
+
+type Builder struct{}
+
+func (b *Builder) Add(k string, v interface{}) *Builder { return b }
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for _, f := range []string{"a/log.Logger.Debug=1", "a/log.Logger.Info=1", "a/log.Logger.Error=1", "a/log.Logger.Log=0"} {
+		if err := a.Flags.Set("pair-func", f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, l := range []string{"a/log.Logger.Debug=debug", "a/log.Logger.Info=info", "a/log.Logger.Error=error", "a/log.Builder.Add=warn"} {
+		if err := a.Flags.Set("level", l); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for flag, v := range map[string]string{
+		"builder-func": "a/log.Builder.Add",
+		"debug-key":    "request_dump,sql",
+		"key":          "id",
+	} {
+		if err := a.Flags.Set(flag, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysistest.Run(t, dir, a, "a")
+
+	if err := a.Flags.Set("level", "a/log.Logger.Info=verbose"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
+
+func TestLeveled(t *testing.T) {
+	got := leveled([]string{"log/slog.Logger.DebugContext=2", "log/slog.Logger.With=0", "go.uber.org/zap.SugaredLogger.DPanicw=1"})
+	expected := []string{"log/slog.Logger.DebugContext=debug", "go.uber.org/zap.SugaredLogger.DPanicw=error"}
+	if d := cmp.Diff(expected, got); d != "" {
+		t.Errorf("unexpected levels (-expected +got):\n%s", d)
+	}
+}
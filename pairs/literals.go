@@ -0,0 +1,110 @@
+package pairs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// literalKeys records where each key passed as a string literal is used in
+// a package.
+type literalKeys map[string][]*ast.BasicLit
+
+// add records k if it's a string literal.
+func (l literalKeys) add(p *analysis.Pass, k ast.Expr) {
+	lit, ok := ast.Unparen(k).(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return
+	}
+	tv, ok := p.TypesInfo.Types[lit]
+	if !ok || tv.Value == nil {
+		return
+	}
+	key := constant.StringVal(tv.Value)
+	l[key] = append(l[key], lit)
+}
+
+// report reports the keys used as literals more than once, at their first
+// use, listing all of the uses.
+func (l literalKeys) report(c *checker, p *analysis.Pass) {
+	keys := make([]string, 0, len(l))
+	for key, uses := range l {
+		if len(uses) > 1 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		uses := l[key]
+		sort.Slice(uses, func(i, j int) bool {
+			pi, pj := p.Fset.Position(uses[i].Pos()), p.Fset.Position(uses[j].Pos())
+			if pi.Filename != pj.Filename {
+				return pi.Filename < pj.Filename
+			}
+			return pi.Offset < pj.Offset
+		})
+
+		var posns []string
+		var related []analysis.RelatedInformation
+		for _, lit := range uses {
+			posn := p.Fset.Position(lit.Pos())
+			posns = append(posns, fmt.Sprintf("%s:%d", filepath.Base(posn.Filename), posn.Line))
+			related = append(related, analysis.RelatedInformation{Pos: lit.Pos(), End: lit.End(), Message: fmt.Sprintf("%q used here", key)})
+		}
+
+		advice := "declare it as a constant"
+		if name, ok := c.keyConstant(p, key); ok {
+			advice = "use the constant " + name
+		} else if c.keysPackage != "" {
+			advice += " in " + c.keysPackage
+		}
+
+		p.Report(analysis.Diagnostic{
+			Pos:      uses[0].Pos(),
+			End:      uses[0].End(),
+			Category: RepeatedKey,
+			Message:  fmt.Sprintf("key %q is used as a literal %d times (%s); %s", key, len(uses), strings.Join(posns, ", "), advice),
+			Related:  related,
+		})
+	}
+}
+
+// keyConstant returns the name of a string constant with value key, declared
+// in the package being analyzed or the -keys-package, if one is imported.
+func (c *checker) keyConstant(p *analysis.Pass, key string) (string, bool) {
+	if name, ok := stringConstant(p.Pkg.Scope(), key, false); ok {
+		return name, true
+	}
+	for _, imp := range p.Pkg.Imports() {
+		if imp.Path() != c.keysPackage {
+			continue
+		}
+		if name, ok := stringConstant(imp.Scope(), key, true); ok {
+			return imp.Name() + "." + name, true
+		}
+	}
+	return "", false
+}
+
+// stringConstant returns the name of the first string constant in scope
+// with value v, only considering exported constants if exported is set.
+func stringConstant(scope *types.Scope, v string, exported bool) (string, bool) {
+	for _, name := range scope.Names() {
+		if exported && !token.IsExported(name) {
+			continue
+		}
+		k, ok := scope.Lookup(name).(*types.Const)
+		if ok && k.Val().Kind() == constant.String && constant.StringVal(k.Val()) == v {
+			return name, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,74 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestRepeatedKeys(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import (
+	"a/keys"
+	"a/log"
+)
+
+const jobKey = "job"
+
+func Foo(id int, b *log.Builder) {
+	log.Log("id", id) // want "key \"id\" is used as a literal 3 times \\(a.go:11, a.go:12, b.go:6\\); declare it as a constant in a/keys"
+	log.Log("id", id, "job", 1) // want "key \"job\" is used as a literal 2 times \\(a.go:12, b.go:7\\); use the constant jobKey"
+	log.Log("name", keys.UserID, jobKey, 2) // want "key \"name\" is used as a literal 2 times \\(a.go:13, b.go:8\\); use the constant keys.Name"
+}
+`,
+		"a/b.go": `package a
+
+import "a/log"
+
+func Bar(b *log.Builder) {
+	b.Add("id", 1)
+	b.Add("job", 2)
+	log.Log("name", 3, "once", 4)
+}
+`,
+		"a/keys/keys.go": `package keys
+
+const (
+	UserID = "user_id"
+	Name   = "name"
+
+	id = "id"
+)
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+
+type Builder struct{}
+
+func (b *Builder) Add(k string, v interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for flag, v := range map[string]string{
+		"pair-func":     "a/log.Log=0",
+		"builder-func":  "a/log.Builder.Add",
+		"repeated-keys": "true",
+		"keys-package":  "a/keys",
+	} {
+		if err := a.Flags.Set(flag, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
@@ -0,0 +1,64 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestNotPairFuncs(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import (
+	"a/log"
+	"a/metrics"
+)
+
+func Foo(l *log.Logger, r *metrics.Recorder) {
+	l.Log("a", 1, 2) // want "3 args passed to method \\(\\*a/log.Logger\\) Log\\(kv ...interface{}\\); must be even"
+	r.Log("latency", 1, 2)
+
+	l.Emit("a", 1, 2) // want "3 args passed to method \\(\\*a/log.Logger\\) Emit\\(kv ...interface{}\\); must be even"
+	r.Emit("latency", 1, 2)
+}
+`,
+		"a/log/log.go": `package log
+
+type Logger struct{}
+
+func (*Logger) Log(kv ...interface{}) {}
+
+func (*Logger) Emit(kv ...interface{}) {}
+`,
+		"a/metrics/metrics.go": `package metrics
+
+type Recorder struct{}
+
+func (*Recorder) Log(name string, values ...float64) {}
+
+func (*Recorder) Emit(name string, values ...float64) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for _, f := range []string{".Log=0", "a/log.Logger.Emit=0"} {
+		if err := a.Flags.Set("pair-func", f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the most precise selector wins, so Logger.Emit is still checked
+	for _, f := range []string{"a/metrics.Recorder.Log", ".Emit"} {
+		if err := a.Flags.Set("not-pair-func", f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
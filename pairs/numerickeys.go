@@ -0,0 +1,64 @@
+package pairs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/internal/keys"
+)
+
+// numericKey matches keys that are only a number, like "404", which are
+// almost always a value quoted into the key's place.
+var numericKey = regexp.MustCompile(`^[+-]?[0-9]+(?:\.[0-9]+)?$`)
+
+// keyLike matches the values that look like keys, absent -key-pattern and
+// -key-case.
+var keyLike = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// numericKeyCorrect reports key, the numeric constant at arg i of a call to
+// name.  If value, the arg after it, is a constant that looks like a key, a
+// fix swapping the two is suggested.
+func (c *checker) numericKeyCorrect(p *analysis.Pass, name string, i int, a ast.Expr, key string, value ast.Expr) {
+	d := analysis.Diagnostic{
+		Pos:      a.Pos(),
+		End:      a.End(),
+		Category: NumericKey,
+		Message:  fmt.Sprintf("key %q (arg %d to %s) is a number; is it a value passed in the key's place?", key, i, name),
+	}
+
+	if value != nil && c.looksLikeKey(p, value) {
+		var k, v strings.Builder
+		if format.Node(&k, p.Fset, a) == nil && format.Node(&v, p.Fset, value) == nil {
+			d.SuggestedFixes = []analysis.SuggestedFix{{
+				Message: "Swap the key and the value",
+				TextEdits: []analysis.TextEdit{
+					{Pos: a.Pos(), End: a.End(), NewText: []byte(v.String())},
+					{Pos: value.Pos(), End: value.End(), NewText: []byte(k.String())},
+				},
+			}}
+		}
+	}
+	p.Report(d)
+}
+
+// looksLikeKey returns true if e is a constant string that isn't a number
+// and would pass as a key: it's in the vocabulary, or matches the key
+// pattern, or absent one, looks like an identifier.
+func (c *checker) looksLikeKey(p *analysis.Pass, e ast.Expr) bool {
+	kind, _, v := c.classify(p, e)
+	if kind != keys.Constant || numericKey.MatchString(v) {
+		return false
+	}
+	if c.vocabulary[v] {
+		return true
+	}
+	if pattern := c.keyPattern(); pattern != nil {
+		return pattern.MatchString(v)
+	}
+	return keyLike.MatchString(v)
+}
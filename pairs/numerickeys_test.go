@@ -0,0 +1,91 @@
+package pairs
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestNumericKeys(t *testing.T) {
+	src := `package a
+
+import "a/log"
+
+func Foo(b *log.Builder, status, id int) {
+	log.Log("status", status)
+	log.Log(%s) // want "key \"404\" \\(arg 0 to a/log.Log\\) is a number; is it a value passed in the key's place\\?"
+	log.Log("id", id, %s) // want "key \"1.5\" \\(arg 2 to a/log.Log\\) is a number"
+	b.Add(%s) // want "key \"-1\" \\(arg 0 to .*\\) is a number"
+
+	// no fix without a value that looks like a key
+	log.Log("404", id) // want "key \"404\" \\(arg 0 to a/log.Log\\) is a number"
+	log.Log("404", "500") // want "key \"404\" \\(arg 0 to a/log.Log\\) is a number"
+	log.Log("404", "not found") // want "key \"404\" \\(arg 0 to a/log.Log\\) is a number"
+}
+`
+	filemap := map[string]string{
+		"a/a.go":        fmt.Sprintf(src, `"404", "status"`, `"1.5", "ratio"`, `"-1", "offset"`),
+		"a/a.go.golden": fmt.Sprintf(src, `"status", "404"`, `"ratio", "1.5"`, `"offset", "-1"`),
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+
+type Builder struct{}
+
+func (b *Builder) Add(key string, value interface{}) *Builder { return b }
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for _, f := range []struct{ name, value string }{
+		{"pair-func", "a/log.Log=0"},
+		{"builder-func", "a/log.Builder.Add"},
+		{"numeric-keys", "true"},
+	} {
+		if err := a.Flags.Set(f.name, f.value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	analysistest.RunWithSuggestedFixes(t, dir, a, "a")
+}
+
+func TestNumericKeysRulesVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+	}{
+		// version 2 predates the rule
+		{"2", ""},
+		{"3", ` // want "key \"404\" \\(arg 0 to a/log.Log\\) is a number"`},
+	}
+	for _, test := range tests {
+		t.Run(test.version, func(t *testing.T) {
+			dir, cleanup, err := analysistest.WriteFiles(map[string]string{
+				"a/a.go":       "package a\n\nimport \"a/log\"\n\nfunc Foo() {\n\tlog.Log(\"404\", \"status\")" + test.want + "\n}\n",
+				"a/log/log.go": "package log\n\nfunc Log(kv ...interface{}) {}\n",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer cleanup()
+
+			a := NewAnalyzer()
+			for _, f := range []struct{ name, value string }{
+				{"pair-func", "a/log.Log=0"},
+				{"rules-version", test.version},
+			} {
+				if err := a.Flags.Set(f.name, f.value); err != nil {
+					t.Fatal(err)
+				}
+			}
+			analysistest.Run(t, dir, a, "a")
+		})
+	}
+}
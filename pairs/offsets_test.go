@@ -0,0 +1,56 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestOffsetPrecision(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import (
+	"a/log"
+	"a/log.v2"
+)
+
+type Other struct{}
+
+func (Other) Log(kv ...interface{}) {}
+
+func Foo(l *log.Logger, o Other, id int) {
+	l.Log("saved", "user_id", id) // want "method \\(\\*a/log.Logger\\) Log\\(msg string, kv ...interface{}\\) matches both a/log.Logger.Log=1 and .Log=0; checking at the offset of the more precise selector"
+	l.Log("saved", id, "user_id") // want "matches both" "arg 1 to method \\(\\*a/log.Logger\\) Log\\(msg string, kv ...interface{}\\) is expression int but should be a constant string"
+	o.Log("user_id", id)
+	o.Log(id, "user_id") // want "arg 0 to method \\(a.Other\\) Log\\(kv ...interface{}\\) is expression int but should be a constant string"
+	logv2.Log("saved", "user_id") // want "2 args passed to a/log.v2.Log; must be even"
+}
+`,
+		"a/log/log.go": `package log
+
+type Logger struct{}
+
+func (*Logger) Log(msg string, kv ...interface{}) {}
+`,
+		"a/log.v2/log.go": `package logv2
+
+func Log(msg string, kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for _, v := range []string{".Log=0", "a/log.Logger.Log=1", "a/log.v2.Log=1"} {
+		if err := a.Flags.Set("pair-func", v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
@@ -6,10 +6,22 @@ A missing value is an error:
 
 	logger.Log("name", "frew", "job", "engineer", "age") // missing value
 
+It's reported at the arg that begins the broken pairing, named in the
+message: the first in a key's place that isn't a string, where a key was
+likely left out, or else the last, a key left without a value.  The pairs
+either side of it are still checked as if it weren't there, so the other
+problems of the call are reported along with it.
+
 A non-string is also an error:
                                      		// missing key
 	logger.Log("message", "successful!",                    3)
 
+With -numeric-keys, which rules version 3 turns on, so is a key that's only
+a number, which is almost always a value quoted into the key's place; when
+the value after it looks like a key, the fix swaps the two:
+
+	logger.Log("404", "status") // fixed to logger.Log("status", "404")
+
 The sole analyzer (from NewAnalyzer) in this package takes a -pair-flag
 flag that can define any number of the following:
 
@@ -26,49 +38,600 @@ start pairs at 0:
 
 	-pair-func go.zr.org/common/go/errors/details.Pairs.AddPairs=0
 
+The -config flag sets the flags of the analyzer from a YAML file (or, if
+its name ends in .toml, a TOML file) mapping flag names to values, with a
+list setting a repeated flag once per element; flags after it override the
+file:
+
+	pair-func:
+	  - .Log=0
+	  - go.zr.org/common/go/errors.Wrap=2
+	assume-pair: go.zr.org/common/go/errors/details.Pairs
+
+Where flags are hard to pass through, as with go vet -vettool or gopls, the
+environment can configure the analyzer instead: SPLINTER_CONFIG names a
+configuration file, and SPLINTER_PAIR_FUNCS and SPLINTER_ASSUME_PAIRS list
+values of -pair-func and -assume-pair, separated by commas or spaces.  They
+apply when the analyzer is created, before any flags:
+
+	SPLINTER_PAIR_FUNCS=example.com/log.Log=0,example.com/log.Info=1
+
+NewAnalyzerFromSettings configures an analyzer from the settings block
+golangci-lint passes a custom linter, with the same names and values.
+
+The result of the analyzer is a *CheckedCalls, and ClassifyArgs returns the
+class of each arg of a call it checked, as a message, a constant or other
+key, a value or a container, so that an editor plugin requiring the analyzer
+can render hints like key: and val: over the pairs with the same resolution
+of pair funcs and offsets.
+
+A module can reference a configuration file from its go.mod, relative to
+the root of the module, which applies whatever the driver, be it go vet,
+gopls or splinter:
+
+	// splinter:config=tools/splinter.yaml
+
+An analyzer created within the module applies the whole file, as
+config.Parse does after the -config files.  For the packages of other
+modules, its pair-func and assume-pair entries are merged into the flags
+for each package, and any other entry is an error.
+
+With -discover-config, the pair-func and assume-pair entries of the
+.splinter.yaml files in each package's directory and its parents, up to the
+root of its module, are merged into the flags for that package, those nearer
+the package taking precedence, so each module of a workspace can carry its
+own configuration.
+
+A value with a leading ! removes what an earlier one added to -pair-func,
+-not-pair-func and the other flags taking selectors, -assume-pair, -key and
+-backend, as in a directory's .splinter.yaml dropping a func its module's
+configuration adds:
+
+	pair-func: ["!go.zr.org/common/go/errors.Wrap"]
+
+When flags are parsed with config.Parse, as splinter does, presets apply
+first, then -config files and the file the module's go.mod references, then
+the configuration files discovered for each package, then the rest of the
+command line, each layer overriding those before it.
+
+The defaults of some flags change between versions of the rules, listed in
+RulesVersions; -rules-version pins the version a configuration was written
+against, 1 unless it's given, so upgrading doesn't change those defaults
+until the configuration says so.  Nothing else is pinned: fixes to rules,
+presets and new diagnostics of the rules already on come with an upgrade.
+It applies only to the flags not set explicitly, wherever they're set, and a
+pinned version behind the latest is warned about.  Version 2 turns on
+-typed-nil-values and -converted-keys, and version 3 -numeric-keys:
+
+	-rules-version 3
+
+The -preset flag sets the pair funcs (and related flags) for the pair-style
+APIs of popular libraries, listed in Presets:
+
+	-preset slog,zap-sugar
+
+Presets other than slog can be left out of a build with the splinter_minimal
+tag, or one at a time with splinter_no_<preset>, like splinter_no_zerolog.
+
+zerolog takes no pairs, so its preset checks the field methods of its events
+and contexts, like Str, as builder funcs adding a single pair.
+
+The slog preset, DefaultPreset, is applied by default, so that the standard
+library's logger is checked without any configuration.  -no-defaults drops
+it, for a fully explicit configuration, unless it's named by -preset too.
+
+Some funcs take their pairs at more than one offset, like a logger whose
+message is optional.  The -pair-shape flag adds an alternative offset to a
+pair func, and each call is checked at the lowest offset it fits, either with
+no pairs or with an even number of args after it, all of whose keys are
+strings:
+
+	-pair-func example.com/log.Log=0 -pair-shape example.com/log.Log=1
+
+	log.Log("msg")           // checked at 1
+	log.Log("id", 1)         // checked at 0
+	log.Log("msg", "id", 1)  // checked at 1
+
+Deferred calls and go statements are checked like any other call, their
+args being evaluated where the statement is.  Cleanup helpers capturing the
+error of a deferred func, in the style of errcapture, are pair funcs with
+their pairs after the error pointer and the func they invoke:
+
+	-pair-func example.com/cleanup.Capture=2
+
+	defer cleanup.Capture(&err, f.Close, "path", path)
+
+Generated code often wraps a logger in shims of its own, like the
+interceptors generated for gRPC services.  The -generated-shim flag takes a
+regexp matched against the "Code generated ... DO NOT EDIT." comment of each
+file; funcs, methods and interface methods declared in matching files whose
+last param is ...interface{} are pair funcs with their pairs there, and are
+marked with a ShimFunc object fact so that calls in other packages are
+checked too:
+
+	-generated-shim protoc-gen-go-logging
+
+Diagnostics in generated files themselves, like mocks and protobuf glue,
+aren't reported, since they can't be fixed there; -check-generated reports
+them too.
+
+Tests often log with throwaway keys that needn't be held to the same rigor.
+-skip-tests drops the diagnostics in the _test.go files of a package, and
+-skip-tests=all those of its external test package too, which is otherwise
+checked since it uses the package's API like any other importer:
+
+	-skip-tests=all
+
+Library authors can declare their own funcs, methods and interface methods
+pair funcs with a //splinter:pairs directive in the doc comment, giving the
+offset of the pairs unless they're the variadic param.  They're marked with
+an AnnotatedFunc object fact, so calls in every package importing them are
+checked with no -pair-func; a -not-pair-func still exempts them, and
+directives that are malformed or don't fit the func are reported as
+invalid-directive:
+
+	//splinter:pairs offset=1
+	func Log(msg string, kv ...interface{})
+
+Likewise, a //splinter:assume-pair directive in the doc comment of a type
+declares it a container of pairs, as if passed to -assume-pair, marking it
+with an AssumedPair object fact:
+
+	//splinter:assume-pair
+	type Pairs struct{ kv []interface{} }
+
+Selectors are matched against the import path of the package declaring the
+func, so aliased and dot imports at the call site are checked the same as
+plain ones.  Unexported funcs and types can be selected too; funcs and types
+declared in an external test package (package foo_test) are selected by the
+path of the package under test, so a test helper is matched by the same
+selector whichever kind of test file declares it:
+
+	-pair-func go.zr.org/common/go/errors.logPairs=0
+
+The same goes for the types passed to -assume-pair, and for types declared
+only for tests, like a logger in an export_test.go file, which the external
+test package reaches through the test variant of the package.  Facts are
+exported by each variant separately, so external test packages have their
+own CallSites and KeyInventory facts, under the path ending in _test.
+
+The -alias flag maps the import path of a fork to the path of the package
+it forks, so selectors written against the canonical path, including those
+of presets, -assume-pair and -ignore-callee-rules, match the fork and the
+packages within it too:
+
+	-alias go.uber.org/zap=example.com/forks/zap
+
+Values of a named func type, like an adapter type LogFunc
+func(...interface{}), are selected by the name of the type, as if it were a
+package func, whether they're called directly or through a field; its
+methods are selected like any other:
+
+	-pair-func example.com/log.LogFunc=0 -pair-func example.com/log.LogFunc.Log=0
+
+Funcs in the analyzed package that return a pair func, like a helper
+returning base.Log or a func literal spreading its ...interface{} param into
+one, are logger factories; calls of the funcs they return are checked too,
+either directly or through a local variable that's never assigned again:
+
+	info := withPrefix(logger, "job: ")
+	info("started", "id", id)
+
+When a method call matches both a selector for any method of its name and
+one for the method of its receiver type, the receiver type's offset is used,
+and if the two offsets differ the call is reported, since the configuration
+is at odds with itself.  A method promoted through embedded fields is also
+selected by each type it's promoted through, from the one declaring it
+outwards, so a selector for a logger's Log method matches calls through the
+structs wrapping it, and a -not-pair-func for a wrapper exempts them.  A
+func in a package whose path ends in an element with a dot, like
+gopkg.in/yaml.v3, is selected the same way as any other:
+
+	-pair-func example.com/log.v2.Log=1
+
+The -not-pair-func flag exempts the funcs it selects from the pair funcs, so
+a type whose Log method doesn't take pairs can be left out of a generous
+selector.  As with offsets, the most precise selector matching a call wins:
+
+	-pair-func .Log=0 -not-pair-func example.com/metrics.Recorder.Log
+
+However many selectors match a call, it's checked once.  A call matching
+both a -pair-func and a -builder-func selector is checked as whichever is
+more precise, and as a pair func if they're equally precise.
+
+The diagnostics about a call carry related information pointing at the
+declaration of the pair func or builder func, naming the selector that made
+it one, which editors show alongside them.
+
 The other flag defined by this package is -assume-pair flag, which users can
 use to define type "safe" for passing around.  The idea is that you'd define
 all methods on the type as pair funcs; this means you are passing around the
 value instead of a raw slice of interfaces, which could get modified in
 surprising ways by users.
+
+With -empty-containers, a whitelisted container passed as the pairs while
+still as constructed (an empty composite literal, new(T), or a call to a
+constructor taking no args, directly or through a local variable used nowhere
+else) is reported, since it attaches no pairs:
+
+	logger.Log(details.NewPairs()) // flagged
+
+The -container-accessor flag takes selectors of methods of whitelisted types
+that return their pairs as a slice.  Spreading one into a pair func is
+accepted, along with raw pairs in the forms below, whose raw pairs are checked
+(and numbered from the first raw arg in diagnostics); appending the raw pairs
+to the accessor's slice is reported too, since append may write them into the
+container's backing array:
+
+	-container-accessor go.zr.org/common/go/errors/details.Pairs.Values
+
+	logger.Log(pairs.Values()...)
+	logger.Log(append([]interface{}{"id", id}, pairs.Values()...)...)
+	logger.Log(append(pairs.Values(), "id", id)...) // flagged
+
+Calls to pair funcs that feed a whitelisted type, either by returning it or by
+being one of its methods, are recorded in a ContainerUsage package fact, which
+drivers can summarize to show whether the keys going into the type are
+constants and what types the values are.
+
+The -ignore-callee-rules flag ignores some rules for calls to funcs declared
+in packages matching a go list style pattern, for third-party APIs whose shape
+intentionally breaks a rule while their keys should still be governed:
+
+	-ignore-callee-rules example.com/vendored/...=odd-arity
+
+Rules can be given by their codes, like SPL001 for odd-arity.  When
+odd-arity is ignored, the keys of calls with an odd number of args are still
+checked.
+
+A //splinter:ignore directive, optionally followed by a reason, silences the
+diagnostics within a single call, as a trailing comment on the line the call
+ends on or as a comment on the line before the one it starts on:
+
+	logger.Log(legacyKey(field), v) //splinter:ignore keys come from the v1 schema
+
+golangci-lint's //nolint directive is honored the same way when it's bare or
+names splinter, all or the analyzer, like //nolint:splinter,errcheck.
+
+Calls spreading a slice into the pairs, like logger.Log(kv...), can't be
+checked and are skipped, unless the slice is known to be nil or empty, like
+nil, []interface{}{} or a local variable declared without a value and used
+nowhere else, in which case there are no pairs to get wrong.
+
+With -coverage, the analyzer exports a SelectorCoverage package fact
+counting, for each selector, the calls it checked, the calls it skipped
+(including calls through func values or interfaces with the selector's name,
+which can't be resolved) and the diagnostics reported, so drivers can show
+how well the configuration fits the code.
+
+With -group-by-call, diagnostics of the same rule in one call to a pair func
+are reported as one, spanning the call, with each of them as related
+information and all of their suggested fixes, so a call with many bad keys is
+one line of output rather than many.
+
+With -inventory, the analyzer exports a KeyInventory package fact of the
+constant keys passed to pair funcs and builder funcs, with the types of their
+values, which splinter keys dumps and compares across releases.  With
+-sites, it exports a CallSites package fact of every call it checked, with
+the callee, the number of pairs and the keys, which splinter sites lists.
+
+Keys whose type is a type parameter constrained to strings, like ~string, are
+strings in every instantiation, so generic helpers passing them are treated
+like any other string expression; -string-type-param-keys=false reports them
+as non-string expressions instead.
+
+Calls to the methods of a type parameter's constraint are matched by the
+selectors of the interface declaring the method, or only by the generous
+selector, like .Log, if it's unnamed.  A generic func spreading its last
+...interface{} param into the pairs of such a call, or any other pair func,
+is a pair func itself, marked with a HelperFunc object fact so that calls
+to it are checked in every package importing it:
+
+	func logIt[L interface{ Log(...any) }](l L, kv ...any) { l.Log(kv...) }
+
+	logIt(logger, "id") // flagged, with -pair-func .Log=0
+
+Opt-in rules
+
+The -side-effect-func flag takes selectors of the same form as -pair-func,
+minus the offset, and reports values that call any of them:
+
+	-side-effect-func database/sql.Rows.Close -side-effect-func .Inc
+
+	logger.Log("closed", rows.Close()) // flagged
+
+Values passed to a pair func are evaluated even when the logger drops the
+line for being below the configured level, and the order of evaluation
+relative to the rest of the call is easy to misread, so side effects in that
+position are a hazard.
+
+With -repeated-values, a variable (or a field of one) passed as the value
+of two adjacent pairs with different keys is reported, since the second key
+is often a copy-paste of the first that wasn't updated:
+
+	logger.Log("req_id", id, "trace_id", id) // flagged
+
+With -sorted-keys, a call whose constant keys aren't in lexical order is
+reported at the first key out of order, for teams that sort long log lines to
+keep them reviewable.  If every key is a constant and no value calls a func
+or receives from a channel, whose order sorting would change, a fix sorts
+the pairs:
+
+	logger.Log("user_id", id, "org_id", org) // flagged, fixed to "org_id", org, "user_id", id
+
+With -typed-nil-values, values that are pointers known to be nil are
+reported: conversions of nil, and local variables declared without a value
+and never assigned.  Passed as an interface{}, such a pointer isn't a nil
+interface, so it renders as <nil> while comparing unequal to nil, which often
+hides a bug:
+
+	var cause *MyError
+	logger.Log("cause", cause) // flagged
+
+The -wrap-func flag marks pair funcs as error wrappers, and reports errors
+passed in their pairs: if the func has an error param before the pairs, any
+error in the pairs belongs there (combined with errors.Join if need be),
+otherwise only one error may be passed:
+
+	-pair-func go.zr.org/common/go/errors.Wrap=2 -wrap-func go.zr.org/common/go/errors.Wrap
+
+	errors.Wrap(err, "saving", "cause", closeErr) // flagged
+
+Some error APIs document wrapping with %w and passing pairs as alternative
+ways of adding context.  The -exclusive-wrap-func flag takes selectors of such
+pair funcs, and reports calls that do both, whether with a %w format string
+among the args before the pairs or with an fmt.Errorf call there:
+
+	-exclusive-wrap-func go.zr.org/common/go/errors.Wrap
+
+	errors.Wrap(fmt.Errorf("saving %s: %w", id, err), "saving", "id", id) // flagged
+
+The -error-path-func flag takes selectors of pair funcs, typically loggers,
+and reports calls to them in the body of an if err != nil block that don't
+pass err in any arg, since a failure logged without its error is hard to
+act on:
+
+	-error-path-func .Log
+
+	if err != nil {
+		logger.Log("msg", "saving failed") // flagged
+	}
+
+The -key-pattern flag takes a regexp that constant keys must match, and the
+-key-case flag (snake, kebab or camel) takes a canonical casing that they must
+be in.  With -key-case, keys passed as string literals that can be converted
+unambiguously get a suggested fix rewriting them, so that -fix can migrate a
+codebase to the convention:
+
+	-key-case snake
+
+	logger.Log("UserID", id) // flagged, fixed to "user_id"
+
+Keys with characters other than letters, digits, underscores and dashes, or
+ending in an acronym followed by s (like IDs), are reported without a fix.
+If both flags are given the key must match -key-pattern, and the fix is only
+offered when the converted key matches it.
+
+Conversions of constants the type checker doesn't fold, like
+string([]byte("key")), are constant keys all the same.  With
+-converted-keys, constant keys passed through a conversion that doesn't
+change them are reported, with a fix passing the constant itself:
+
+	-converted-keys
+
+	logger.Log(string([]byte("user_id")), id) // flagged, fixed to "user_id"
+
+The -key flag builds a vocabulary of known keys; once it has any, constant
+keys that aren't in it are reported:
+
+	-key user_id,request_id -key trace_id
+
+While a codebase migrates from one spelling of a key to another, -key-alias
+declares the deprecated spelling an alias of the new one.  -duplicate-keys,
+-repeated-values and -getter-func treat the two as the same key, and uses of
+the deprecated spelling are reported as deprecated-key, with a fix replacing
+literals, instead of being checked against -key, -key-pattern and -key-case:
+
+	-key-alias traceID=trace_id
+
+Keys planned for removal from the log schema can be given a sunset date with
+-key-sunset.  From that date their uses are reported as sunset-key, a rule
+whose RuleDoc marks it as a warning, and once -sunset-grace-days have passed,
+as expired-key, an error.  Sunset keys count as known to -key:
+
+	-key-sunset user_name=2026-12-01 -sunset-grace-days 60
+
+Keys are often declared as an enum of integer constants whose String
+method is generated by stringer.  With -stringer-keys, such constants are
+accepted as keys, and checked by -key, -key-pattern, -duplicate-keys and
+-backend as their String value, which is resolved from the generated code
+when its constants are a single run of consecutive values; otherwise they are
+treated like string expressions.  The types are marked with a StringerKeys
+object fact, so keys of types declared in other packages are resolved too:
+
+	-stringer-keys
+
+	logger.Log(field.UserID, id) // checked as "UserID"
+
+The -backend flag annotates pair funcs with the profile of the backend they
+log to (zap, slog or gokit), and keys colliding with the fields that backend
+adds to every entry are reported, like caller or stacktrace for zap:
+
+	-backend go.uber.org/zap.SugaredLogger.Infow=zap
+
+	sugar.Infow("failed", "caller", name) // flagged
+
+The presets annotate their pair funcs with the matching profile.
+
+Logging a whole request, response or protobuf message can produce huge
+entries and leak sensitive fields.  Values whose types match a pattern given
+to -forbid-value-type are reported, suggesting some of their exported fields
+instead; the pattern is [*]<pkg>.<type>, matching pointers only with the
+leading *, where the package may contain ... and the type may be * for any
+type of the package, or proto for any generated protobuf message:
+
+	-forbid-value-type '*net/http.Request' -forbid-value-type proto
+
+	logger.Log("request", r) // flagged, suggesting .Method, .URL, .Proto
+
+Some keys carry high-volume diagnostic data that belongs in debug logs only.
+The -debug-key flag marks such keys, which count as known to -key, and
+-level annotates pair funcs and builder funcs with the level they log at
+(debug, info, warn or error); debug-only keys passed to funcs above debug are
+reported:
+
+	-debug-key request_dump -level example.com/log.Logger.Info=info
+
+	logger.Info("done", "request_dump", dump) // flagged
+
+The slog and zap-sugar presets set the levels of their funcs.
+
+Builders
+
+Some APIs take pairs through repeated two-argument calls rather than a
+variadic one.  The -builder-func flag takes selectors of such funcs; the first
+arg is checked as a key and the second as a value, like the pairs of a pair
+func:
+
+	-builder-func go.zr.org/common/go/errors/details.Builder.Add
+
+	b.Add("id", id).Add(name, "frew") // name flagged unless it's a constant
+
+With -duplicate-keys, constant keys added to the same builder more than once
+in a func are reported too.  A builder is tracked while it's held in a local
+variable, in a field of one, however deeply nested, as in req.meta.details,
+or chained from an unnamed one; builders in package variables are not, nor
+are those more than -receiver-depth fields (16 by default) away from the
+variable.
+
+Getters
+
+Keys written into the pairs are often read back out, as in
+details.Value(err, "user_id").  The -getter-func flag takes such funcs with
+the index of their key arg, and constant keys they read that no pair func or
+builder func writes are reported, since a typo there silently reads nothing:
+
+	-getter-func go.zr.org/common/go/errors/details.Value=1
+
+	details.Value(err, "user_di") // flagged unless some pair func writes it
+
+The keys written in each package are exported as a WrittenKeys package fact,
+so a read is checked against the keys written in its package and every
+package it imports, directly or not; keys written only by packages importing
+the reader's package aren't seen.
+
+Repeated keys
+
+With -repeated-keys, string literal keys used more than once in a package are
+reported at their first use, listing every use, since a key repeated by hand
+is one typo away from splitting a field in two.  Such keys should be declared
+as constants, in the package itself or in the package named by -keys-package;
+if a constant with the same value already exists there, the diagnostic names
+it:
+
+	-repeated-keys -keys-package go.zr.org/common/go/logkeys
+
+Examples
+
+The txtar archives in testdata/examples exercise every rule and preset, each
+with the flags it needs and the diagnostics expected of it.  The RunGolden
+func of package pairstest runs a directory of such archives as subtests, so
+teams can pin the behavior of their own configurations the same way; setting
+SPLINTER_UPDATE_GOLDEN=1 rewrites the expected diagnostics instead.
 */
 package pairs
 
 import (
 	"errors"
 	"flag"
+	"fmt"
 	"go/ast"
-	"go/constant"
 	"go/types"
+	"os"
+	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/ast/astutil"
+
+	"github.com/ZipRecruiter/splinter/internal/calls"
+	"github.com/ZipRecruiter/splinter/internal/config"
+	"github.com/ZipRecruiter/splinter/internal/ignore"
+	"github.com/ZipRecruiter/splinter/internal/keys"
+	"github.com/ZipRecruiter/splinter/internal/ruledoc"
 )
 
 type funcSelector struct{ pkg, typ, fun string }
 
-type funcOffset map[funcSelector]int
+func newFuncSelector(s calls.Selector) funcSelector {
+	return funcSelector{pkg: s.Pkg, typ: s.Typ, fun: s.Fun}
+}
+
+func (s funcSelector) String() string {
+	return calls.Selector{Pkg: s.pkg, Typ: s.typ, Fun: s.fun}.String()
+}
 
-var funcOffsetMatcher = regexp.MustCompile(`^(?:(.*?)(?:\.([^\./]+))?)?\.([^\.]+)=(\d+)$`)
+type funcOffset map[funcSelector]int
 
 func (o funcOffset) Set(v string) error {
-	m := funcOffsetMatcher.FindStringSubmatch(v)
-	if len(m) != 5 {
-		return errors.New("invalid func offset; should be of form [pkg[.type]].<func>=<offset>")
+	if v, ok := config.Removal(v); ok {
+		if i := strings.LastIndexByte(v, '='); i >= 0 {
+			v = v[:i]
+		}
+		sel, err := calls.ParseSelector(v)
+		if err != nil {
+			return err
+		}
+		delete(o, newFuncSelector(sel))
+		return nil
 	}
-	val, err := strconv.Atoi(m[4])
+
+	sel, offset, err := calls.ParseOffset(v)
 	if err != nil {
 		return err
 	}
 
-	o[funcSelector{pkg: m[1], typ: m[2], fun: m[3]}] = val
+	o[newFuncSelector(sel)] = offset
 	return nil
 }
 
 func (o funcOffset) String() string {
-	return "Woo"
+	var s []string
+	for sel, offset := range o {
+		s = append(s, sel.String()+"="+strconv.Itoa(offset))
+	}
+	sort.Strings(s)
+	return strings.Join(s, ",")
+}
+
+type funcSet map[funcSelector]bool
+
+func (s funcSet) Set(v string) error {
+	v, remove := config.Removal(v)
+	sel, err := calls.ParseSelector(v)
+	if err != nil {
+		return err
+	}
+
+	if remove {
+		delete(s, newFuncSelector(sel))
+	} else {
+		s[newFuncSelector(sel)] = true
+	}
+	return nil
+}
+
+func (s funcSet) String() string {
+	var v []string
+	for sel := range s {
+		v = append(v, sel.String())
+	}
+	sort.Strings(v)
+	return strings.Join(v, ",")
 }
 
 type whitelistableType struct{ pkg, typ string }
@@ -78,173 +641,606 @@ type typeWhitelist map[whitelistableType]bool
 var typeWhitelistMatcher = regexp.MustCompile(`^(.*?)\.([^\./]+)$`)
 
 func (w typeWhitelist) Set(v string) error {
+	v, remove := config.Removal(v)
 	m := typeWhitelistMatcher.FindStringSubmatch(v)
 	if len(m) != 3 {
 		return errors.New("invalid type whitelist; should be of form <pkg>.<type>")
 	}
 
-	w[whitelistableType{pkg: m[1], typ: m[2]}] = true
+	if remove {
+		delete(w, whitelistableType{pkg: m[1], typ: m[2]})
+	} else {
+		w[whitelistableType{pkg: m[1], typ: m[2]}] = true
+	}
 	return nil
 }
 
 func (w typeWhitelist) String() string {
-	return "Woo"
+	var s []string
+	for t := range w {
+		s = append(s, t.pkg+"."+t.typ)
+	}
+	sort.Strings(s)
+	return strings.Join(s, ",")
+}
+
+// checker holds the configuration of a single analyzer; the flags defined
+// in NewAnalyzer write directly into its fields.
+type checker struct {
+	offsets            funcOffset
+	notPairFuncs       funcSet
+	shapes             funcShapes
+	whitelistedTypes   typeWhitelist
+	sideEffects        funcSet
+	forbiddenTypes     forbiddenTypes
+	wrapFuncs          funcSet
+	exclusiveWraps     funcSet
+	errorPathFuncs     funcSet
+	containerAccessors funcSet
+	keyRegexp          regexpFlag
+	keyCase            keyCase
+	builderFuncs       funcSet
+	getterFuncs        funcOffset
+	duplicateKeys      bool
+	receiverDepth      int
+	calleeRules        calleeRules
+	repeatedKeys       bool
+	keysPackage        string
+	stringTypeParams   bool
+	vocabulary         vocabulary
+	debugKeys          vocabulary
+	keyAliases         keyAliases
+	sunsets            keySunsets
+	sunsetGrace        int
+	levels             funcLevels
+	backends           backendProfiles
+	coverage           bool
+	typedNils          bool
+	groupByCall        bool
+	inventory          bool
+	sites              bool
+	shimGenerators     regexpFlag
+	checkGenerated     bool
+	skipTests          skipTestsFlag
+	stringerKeys       bool
+	convertedKeys      bool
+	numericKeys        bool
+	repeatedValues     bool
+	sortedKeys         bool
+	aliases            pathAliases
+	emptyContainers    bool
+	discoverConfig     bool
+	discovered         *discoveredConfigs
+	cmdline            *commandLine
+	rulesVersion       *rulesVersionFlag
+	warnOnce           *sync.Once // the warning of rulesVersion
+	moduleConfig       string     // applied by NewAnalyzer, if any
+	configErr          error      // from applying it and the environment
 }
 
 // NewAnalyzer returns a fresh pairs analyzer.
 func NewAnalyzer() *analysis.Analyzer {
 	fset := flag.NewFlagSet("pairs", flag.ContinueOnError)
 
-	offsets := funcOffset{}
-	whitelistedTypes := typeWhitelist{}
+	c := &checker{
+		offsets:            funcOffset{},
+		notPairFuncs:       funcSet{},
+		shapes:             funcShapes{},
+		whitelistedTypes:   typeWhitelist{},
+		sideEffects:        funcSet{},
+		wrapFuncs:          funcSet{},
+		exclusiveWraps:     funcSet{},
+		errorPathFuncs:     funcSet{},
+		containerAccessors: funcSet{},
+		builderFuncs:       funcSet{},
+		getterFuncs:        funcOffset{},
+		vocabulary:         vocabulary{},
+		debugKeys:          vocabulary{},
+		keyAliases:         keyAliases{},
+		sunsets:            keySunsets{},
+		levels:             funcLevels{},
+		backends:           backendProfiles{},
+		aliases:            pathAliases{},
+		discovered:         newDiscoveredConfigs(),
+		cmdline:            &commandLine{},
+		warnOnce:           &sync.Once{},
+	}
 
-	fset.Var(offsets, "pair-func", "validate this func")
-	fset.Var(whitelistedTypes, "assume-pair", "assume this type is safe")
+	fset.Var(&config.Flag{FlagSet: fset}, "config", "set flags from this YAML (or, ending in .toml, TOML) file; later flags override it")
+	fset.BoolVar(&c.discoverConfig, "discover-config", false, "merge the pair-func and assume-pair entries of the "+discoveredFile+" files from each package's directory up to its module root")
+	c.rulesVersion = &rulesVersionFlag{fset: fset}
+	fset.Var(c.rulesVersion, "rules-version", fmt.Sprintf("apply the defaults of this version of the rules, 1 to %d, to the flags not set explicitly", LatestRulesVersion))
+	presets := &presetFlag{fset: fset}
+	fset.Var(presets, "preset", "comma separated presets configuring the pair funcs of popular libraries: "+presetNames())
+	fset.Var(&noDefaultsFlag{presets: presets}, "no-defaults", "don't apply the "+DefaultPreset+" preset that's otherwise applied by default, for a fully explicit configuration")
+	fset.Var(&layeredFlag{c.offsets, "pair-func", c.cmdline}, "pair-func", "validate this func")
+	fset.Var(c.notPairFuncs, "not-pair-func", "don't validate this func, though a less precise -pair-func selects it")
+	fset.Var(c.shapes, "pair-shape", "another offset the pairs of a pair func can start at, as [pkg[.type]].<func>=<offset>; each call is checked at whichever offset it fits")
+	fset.Var(c.aliases, "alias", "select the packages of a fork with the selectors of the package it forks, as <canonical path>=<fork path>")
+	fset.Var(&layeredFlag{c.whitelistedTypes, "assume-pair", c.cmdline}, "assume-pair", "assume this type is safe")
+	fset.BoolVar(&c.emptyContainers, "empty-containers", false, "report -assume-pair containers passed as the pairs while still empty, as constructed")
+	fset.Var(c.containerAccessors, "container-accessor", "check pair func calls spreading the pairs this method of an -assume-pair type returns along with raw pairs")
+	fset.Var(c.sideEffects, "side-effect-func", "report values that call this func")
+	fset.Var(&c.forbiddenTypes, "forbid-value-type", "report values of types matching this pattern, as [*]<pkg>.<type> (the package may contain ... and the type may be *) or "+protoPattern+" for protobuf messages, which should be logged by field")
+	fset.BoolVar(&c.repeatedValues, "repeated-values", false, "report a variable passed as the value of two adjacent pairs with different keys")
+	fset.BoolVar(&c.sortedKeys, "sorted-keys", false, "report calls whose constant keys aren't in lexical order, suggesting a fix sorting the pairs")
+	fset.Var(&versionedBool{value: &c.typedNils}, "typed-nil-values", "report values that are nil pointers, which aren't nil interfaces")
+	fset.Var(c.wrapFuncs, "wrap-func", "report errors passed in the pairs of this pair func")
+	fset.Var(c.exclusiveWraps, "exclusive-wrap-func", "report calls to this pair func that both wrap an error with %w and pass pairs")
+	fset.Var(c.errorPathFuncs, "error-path-func", "report calls to this pair func in an if err != nil block that don't pass err")
+	fset.Var(&c.keyRegexp, "key-pattern", "report constant keys not matching this regexp")
+	fset.Var(&c.keyCase, "key-case", "report constant keys not in this case (snake, kebab or camel), suggesting a fix")
+	fset.Var(c.builderFuncs, "builder-func", "validate this func as adding a single key/value pair")
+	fset.Var(c.getterFuncs, "getter-func", "report constant keys this func reads back, as [pkg[.type]].<func>=<key arg index>, that no pair func or builder func in the package or its dependencies writes")
+	fset.Var(&c.calleeRules, "ignore-callee-rules", "ignore rules for calls to funcs in matching packages, as pattern=rule[,rule], where a rule may be given by its code")
+	fset.BoolVar(&c.duplicateKeys, "duplicate-keys", false, "report keys added more than once to the same builder in a func")
+	fset.IntVar(&c.receiverDepth, "receiver-depth", 16, "the most fields, as in a.b.c.builder, that -duplicate-keys resolves a builder through to the local variable holding it")
+	fset.Var(c.backends, "backend", "report keys colliding with the fields added by the backend a pair func logs to, as [pkg[.type]].<func>=<profile> ("+profileNames()+")")
+	fset.Var(&versionedBool{value: &c.convertedKeys}, "converted-keys", "report constant keys passed through a conversion that doesn't change them, like string([]byte(\"key\"))")
+	fset.Var(&versionedBool{value: &c.numericKeys}, "numeric-keys", "report constant keys that are only a number, suggesting a fix swapping them with their values when those look like keys")
+	fset.BoolVar(&c.stringerKeys, "stringer-keys", false, "accept constants of types whose String method stringer generated as keys, checking their String values")
+	fset.Var(c.vocabulary, "key", "a known key (or comma separated keys); when any are given, constant keys not among them are reported")
+	fset.Var(c.debugKeys, "debug-key", "a debug-only key (or comma separated keys), reported when passed to a pair func whose -level is above debug")
+	fset.Var(c.keyAliases, "key-alias", "a deprecated spelling of a key, as <deprecated>=<key>; the duplicate and consistency checks treat the two as one key, and uses of the deprecated one are reported with a fix")
+	fset.Var(c.sunsets, "key-sunset", "the date a key is sunset on, as <key>=<YYYY-MM-DD>; its uses are reported from then on, as warnings until -sunset-grace-days later and as errors after")
+	fset.IntVar(&c.sunsetGrace, "sunset-grace-days", 30, "the days after its -key-sunset date that the uses of a key are warnings rather than errors")
+	fset.Var(c.levels, "level", "the level a pair func logs at, as [pkg[.type]].<func>=<level> ("+strings.Join(levelNames, ", ")+")")
+	fset.BoolVar(&c.stringTypeParams, "string-type-param-keys", true, "treat keys of type parameters constrained to strings, like ~string, as string expressions")
+	fset.BoolVar(&c.repeatedKeys, "repeated-keys", false, "report string literal keys used more than once in a package")
+	fset.StringVar(&c.keysPackage, "keys-package", "", "import path of the package where -repeated-keys suggests declaring key constants")
+	fset.Var(&c.shimGenerators, "generated-shim", "check the ...interface{} param of funcs declared in generated files whose \"Code generated\" comment matches this regexp as pairs")
+	fset.Var(&c.skipTests, "skip-tests", "don't report diagnostics in _test.go files; with =all, in external test packages either")
+	fset.BoolVar(&c.checkGenerated, "check-generated", false, "report diagnostics in generated files, with a \"Code generated ... DO NOT EDIT.\" comment, which are skipped otherwise")
+	fset.BoolVar(&c.groupByCall, "group-by-call", false, "report the diagnostics of the same rule in one call as one diagnostic, with the rest as related information")
+	fset.BoolVar(&c.coverage, "coverage", false, "export a SelectorCoverage fact counting the calls each selector matched")
+	fset.BoolVar(&c.inventory, "inventory", false, "export a KeyInventory fact of the constant keys passed in the package and the types of their values")
+	fset.BoolVar(&c.sites, "sites", false, "export a CallSites fact of every call to a pair func or builder func checked in the package")
 
-	// Same comment as on argsCorrect below. --fREW 2020-01-18
-	isWhitelisted := func(p *analysis.Pass, e ast.Expr) bool {
-		typ := p.TypesInfo.Types[e]
-		if p, ok := typ.Type.(*types.Pointer); ok {
-			if named, ok := p.Elem().(*types.Named); ok {
-				if whitelistedTypes[whitelistableType{pkg: named.Obj().Pkg().Path(), typ: named.Obj().Name()}] {
-					return true
-				}
-			}
+	if err := presets.apply(DefaultPreset); err != nil {
+		panic(err)
+	}
+	c.moduleConfig, c.configErr = applyModuleConfig(fset)
+	if c.configErr == nil {
+		c.configErr = applyEnv(fset, os.Getenv)
+	}
+
+	return &analysis.Analyzer{
+		Name:      "pairs",
+		Doc:       "pairs allows verification of key/value pairs in ...interface{} args; see -pair-func especially",
+		URL:       ruledoc.URL,
+		Flags:     *fset,
+		Run:        c.run,
+		ResultType: reflect.TypeOf(new(CheckedCalls)),
+		FactTypes: []analysis.Fact{new(ContainerUsage), new(SelectorCoverage), new(KeyInventory), new(CallSites), new(ShimFunc), new(StringerKeys), new(WrittenKeys), new(AnnotatedFunc), new(AssumedPair), new(HelperFunc)},
+	}
+}
+
+func (c *checker) isWhitelisted(p *analysis.Pass, e ast.Expr) bool {
+	// constants are of basic types, so can't be whitelisted
+	typ := p.TypesInfo.Types[e]
+	if typ.Value != nil {
+		return false
+	}
+	if ptr, ok := typ.Type.(*types.Pointer); ok {
+		if c.whitelisted(p, ptr.Elem()) {
+			return true
 		}
-		if named, ok := typ.Type.(*types.Named); ok {
-			if whitelistedTypes[whitelistableType{pkg: named.Obj().Pkg().Path(), typ: named.Obj().Name()}] {
-				return true
+	}
+	return c.whitelisted(p, typ.Type)
+}
+
+// whitelisted returns true if t is a named type passed to -assume-pair, or
+// declared one with //splinter:assume-pair.
+func (c *checker) whitelisted(p *analysis.Pass, t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil { // universe types like error
+		return false
+	}
+	if c.whitelistedTypes[whitelistableType{pkg: c.aliases.canonical(calls.PkgPath(named.Obj().Pkg())), typ: named.Obj().Name()}] {
+		return true
+	}
+	return p.ImportObjectFact(named.Origin().Obj(), new(AssumedPair))
+}
+
+// callSelectors returns the selectors that could match the func called by
+// call, most generous first, along with a name for the func suitable for
+// diagnostics.  ok is false if the call is not to a package func or to a
+// method with a named receiver.
+func (c *checker) callSelectors(i *types.Info, call *ast.CallExpr) (sels []funcSelector, name string, ok bool) {
+	callee, ok := calls.Resolve(i, call)
+	if !ok {
+		return nil, "", false
+	}
+
+	if callee.Pkg != "" {
+		callee.Pkg = c.aliases.canonical(callee.Pkg)
+	}
+	for i, e := range callee.Embedded {
+		callee.Embedded[i].Pkg = c.aliases.canonical(e.Pkg)
+	}
+	for _, s := range callee.Selectors() {
+		sels = append(sels, newFuncSelector(s))
+	}
+	return sels, callee.Name, true
+}
+
+// calleePkg returns the import path of the package declaring the func
+// selected by sels, as returned by callSelectors.
+func calleePkg(sels []funcSelector) string {
+	return sels[len(sels)-1].pkg
+}
+
+// pairOffset returns the most precise of sels, as returned by
+// callSelectors, that's a pair func, with the offset of its pairs.  If a
+// more generous selector is a pair func too but with another offset, it's
+// returned as other, since the configuration is at odds with itself.
+// Selectors less precise than one passed to -not-pair-func are ignored.
+func (c *checker) pairOffset(p *analysis.Pass, sels []funcSelector, call *ast.CallExpr) (sel funcSelector, offset int, other funcSelector, ok bool) {
+	for i := len(sels) - 1; i >= 0; i-- {
+		if c.notPairFuncs[sels[i]] {
+			break // exempt from any less precise selector
+		}
+		o, found := c.offsets[sels[i]]
+		if !found && i == len(sels)-1 {
+			// annotated funcs, generated shims and generic helpers
+			// are pair funcs without being configured
+			o, found = c.annotatedOffset(p, call)
+			if !found {
+				o, found = c.shimOffset(p, call)
+			}
+			if !found {
+				o, found = c.helperOffset(p, call)
 			}
 		}
-		return false
+		switch {
+		case !found:
+		case !ok:
+			sel, offset, ok = sels[i], o, true
+		case o != offset:
+			other = sels[i]
+		}
+	}
+	return sel, offset, other, ok
+}
 
+// unpaired returns the index of the arg of an odd number of pairs that
+// begins the broken pairing: the first in a key's place that isn't a
+// string, where a key was likely left out, or else the last, a key left
+// without a value.
+func unpaired(p *analysis.Pass, pairs []ast.Expr) int {
+	for i := 0; i < len(pairs)-1; i += 2 {
+		if kind, _, _ := keys.Classify(p.TypesInfo, pairs[i]); kind == keys.NonStringConstant || kind == keys.NonStringExpression {
+			return i
+		}
 	}
+	return len(pairs) - 1
+}
 
-	// it'd be better to make a value that has an argsCorrect method than
-	// this weird closure oriented style.  If I get around to it I'll
-	// change this. --fREW 2020-01-17
-	argsCorrect := func(p *analysis.Pass, name string, offset int, c *ast.CallExpr) {
-		if len(c.Args) <= offset {
+// argsCorrect checks the pairs of call, to name, starting at offset.  The
+// ignored rules aren't reported.  An odd number of args is reported at the
+// unpaired arg, and the pairs either side of it are still checked, unless
+// odd-arity is ignored, in which case they're checked as they are.
+func (c *checker) argsCorrect(p *analysis.Pass, name string, offset int, call *ast.CallExpr, ignored ruleSet) {
+	if len(call.Args) <= offset {
+		return
+	}
+
+	// if we only have 1 arg it needs to be one of the whitelisted
+	// types
+	if len(call.Args)-offset == 1 {
+		if c.isWhitelisted(p, call.Args[offset]) {
+			if c.emptyContainers {
+				c.emptyContainerCorrect(p, name, offset, call.Args[offset])
+			}
 			return
 		}
+	}
 
-		// if we only have 1 arg it needs to be one of the whitelisted
-		// types
-		if len(c.Args)-offset == 1 {
-			if isWhitelisted(p, c.Args[offset]) {
-				return
-			}
+	// the arg number of each of the pairs, which skip the unpaired arg of
+	// an odd number
+	pairs := call.Args[offset:]
+	at := make([]int, len(pairs))
+	for i := range at {
+		at[i] = i + offset
+	}
+
+	if len(pairs)%2 != 0 {
+		u := unpaired(p, pairs)
+		i := u + offset
+		c.report(p, call.Args[i], OddArity, "%d args passed to %s; must be even: arg %d, %s, is unpaired", len(call.Args), name, i, types.ExprString(call.Args[i]))
+		if !ignored[OddArity] {
+			// the pairs either side of it are checked as if it weren't
+			// there
+			pairs = append(pairs[:u:u], pairs[u+1:]...)
+			at = append(at[:u:u], at[u+1:]...)
 		}
+	}
 
-		if (len(c.Args)-offset)%2 != 0 {
-			p.Reportf(c.Pos(), "%d args passed to %s; must be even", len(c.Args), name)
+	for i, a := range pairs {
+		if c.isWhitelisted(p, a) {
+			c.report(p, a, WhitelistedType, "arg %d to %s is a whitelisted type; should pass one or none", at[i], name)
 			return
 		}
+	}
 
-		for i, a := range c.Args[offset:] {
-			if isWhitelisted(p, a) {
-				p.Reportf(c.Pos(), "arg %d to %s is a whitelisted type; should pass one or none", i+offset, name)
-				return
-			}
+	for i, a := range pairs {
+		if i%2 != 0 {
+			c.valueCorrect(p, name, at[i], a)
+			continue
 		}
 
-		for i, a := range c.Args[offset:] {
-			if i%2 != 0 {
-				continue
-			}
+		var value ast.Expr // none for the last of an odd number
+		if i+1 < len(pairs) {
+			value = pairs[i+1]
+		}
+		c.keyCorrect(p, name, at[i], a, value)
+	}
+	if c.repeatedValues && len(at) == len(call.Args)-offset {
+		c.repeatedValuesCorrect(p, name, offset, pairs)
+	}
+}
 
-			typ := p.TypesInfo.Types[a]
+// keyCorrect checks the key at arg i of a call to name, whose value is the
+// arg after it, if any.
+func (c *checker) keyCorrect(p *analysis.Pass, name string, i int, a, value ast.Expr) {
+	// TODO prefer *anonymous* constant
 
-			// TODO prefer *anonymous* constant
+	switch kind, typ, key := c.classify(p, a); kind {
+	case keys.Constant:
+		if c.numericKeys && numericKey.MatchString(key) {
+			c.numericKeyCorrect(p, name, i, a, key, value)
+			return
+		}
+		if canonical, ok := c.keyAliases[key]; ok {
+			// the fix brings it in line with the rest
+			c.deprecatedKeyCorrect(p, name, i, a, key, canonical)
+		} else {
+			c.keyPatternCorrect(p, name, i, a, key)
+			c.vocabularyCorrect(p, name, i, a, key)
+		}
+		c.sunsetCorrect(p, name, i, a, key)
+		if c.convertedKeys {
+			c.conversionCorrect(p, name, i, a, key)
+		}
+	case keys.NonStringConstant:
+		c.report(p, a, NonStringKey, "arg %d to %s is constant %s but should be a constant string",
+			i,
+			name,
+			types.TypeString(typ, nil),
+		)
+	case keys.StringTypeParam:
+		if c.stringTypeParams {
+			return
+		}
+		fallthrough
+	case keys.NonStringExpression:
+		c.report(p, a, ExpressionKey, "arg %d to %s is expression %s but should be a constant string",
+			i,
+			name,
+			types.TypeString(typ, nil),
+		)
+	}
+}
 
-			// it's a string constant, this is preferred
-			if typ.Value != nil { // constant
-				if typ.Value.Kind() != constant.String {
-					p.Reportf(a.Pos(), "arg %d to %s is constant %s but should be a constant string",
-						i+offset,
-						name,
-						types.TypeString(typ.Type, nil),
-					)
-				}
-				continue
-			}
+// valueCorrect runs the opt-in checks on the value at arg i of a call to
+// name.
+func (c *checker) valueCorrect(p *analysis.Pass, name string, i int, a ast.Expr) {
+	if len(c.sideEffects) != 0 {
+		c.sideEffectsCorrect(p, name, i, a)
+	}
+	if c.typedNils {
+		c.typedNilCorrect(p, name, i, a)
+	}
+	if len(c.forbiddenTypes) != 0 {
+		c.forbiddenValueCorrect(p, name, i, a)
+	}
+}
 
-			if typ.Type != nil { // expression
-				b, ok := typ.Type.Underlying().(*types.Basic)
-				if ok && b.Kind() == types.String {
-					// it's a string expression, this is not preferred, but is acceptable
-					continue
-				}
-				p.Reportf(a.Pos(), "arg %d to %s is expression %s but should be a constant string",
-					i+offset,
-					name,
-					types.TypeString(typ.Type, nil),
-				)
-			}
+func (c *checker) isWrapFunc(sels []funcSelector) bool {
+	for _, sel := range sels {
+		if c.wrapFuncs[sel] {
+			return true
 		}
 	}
+	return false
+}
 
-	return &analysis.Analyzer{
-		Name:  "pairs",
-		Doc:   "pairs allows verification of key/value pairs in ...interface{} args; see -pair-func especially",
-		Flags: *fset,
-		Run: func(p *analysis.Pass) (interface{}, error) {
-			i := p.TypesInfo
-
-			for _, f := range p.Files {
-				astutil.Apply(f, func(cur *astutil.Cursor) bool {
-					c, ok := cur.Node().(*ast.CallExpr)
-					if !ok {
-						return true
-					}
-					s, ok := c.Fun.(*ast.SelectorExpr) // possibly method calls
-					if !ok {
-						return true
-					}
+func (c *checker) isExclusiveWrap(sels []funcSelector) bool {
+	for _, sel := range sels {
+		if c.exclusiveWraps[sel] {
+			return true
+		}
+	}
+	return false
+}
 
-					// package functions
-					nv, ok := i.Selections[s]
-					if !ok {
-						pkgName := i.Uses[s.X.(*ast.Ident)].(*types.PkgName) // 😅
-						path := pkgName.Imported().Path()
+func (c *checker) run(p *analysis.Pass) (interface{}, error) {
+	if c.configErr != nil {
+		return nil, c.configErr
+	}
+	c.warnOnce.Do(func() {
+		if w := c.rulesVersion.warning(); w != "" {
+			fmt.Fprintf(os.Stderr, "splinter: %s\n", w)
+		}
+	})
+	c, err := c.discover(p)
+	if err != nil {
+		return nil, err
+	}
+	p = ruledoc.Link(p)
+	p = ignore.Filter(p)
+	if !c.checkGenerated {
+		p = ignore.Generated(p)
+	}
+	if c.skipTests.skip {
+		p = ignore.Tests(p, c.skipTests.external)
+	}
 
-						offset, ok := offsets[funcSelector{pkg: path, fun: s.Sel.Name}]
-						if !ok { // we don't care about this function
-							return true
-						}
+	feeds := containerFeeds{}
+	added := builderKeys{}
+	literals := literalKeys{}
+	var coverage selectorCoverage
+	if c.coverage {
+		coverage = selectorCoverage{}
+	}
+	inventory := keyInventory{}
+	var sites *callSites
+	if c.sites {
+		sites = &callSites{}
+	}
+	var written *writtenKeys
+	if len(c.getterFuncs) != 0 {
+		written = &writtenKeys{written: map[string]bool{}}
+	}
 
-						argsCorrect(p, path+"."+s.Sel.Name, offset, c)
+	c.exportAnnotated(p)
+	if c.shimGenerators.Regexp != nil {
+		c.exportShims(p)
+	}
+	if c.stringerKeys {
+		exportStringers(p)
+	}
+	c.exportHelpers(p)
+	factories := c.findFactories(p)
+	checked := &CheckedCalls{args: map[*ast.CallExpr][]ArgClass{}}
 
-						return true
-					}
+	for _, f := range p.Files {
+		astutil.Apply(f, func(cur *astutil.Cursor) bool {
+			call, ok := cur.Node().(*ast.CallExpr)
+			if !ok {
+				return true
+			}
 
-					named, ok := nv.Recv().(*types.Named)
-					if !ok {
-						// if there is no receiver (or
-						// it's anonymous) it's some
-						// weird thing like an
-						// anonymous struct with a func
-						// being called.  structs with func
-						// fields do not conform to interfaces,
-						// and thus are not relevant to this
-						return true
+			sels, name, ok := c.callSelectors(p.TypesInfo, call)
+			if !ok {
+				if offset, name, ok := factories.factoryCall(p, call); ok && !call.Ellipsis.IsValid() {
+					checked.add(c, p, offset, call)
+					p, flush := c.grouped(p, call)
+					c.argsCorrect(p, name, offset, call, nil)
+					flush()
+					if len(call.Args) > offset {
+						written.add(c, p, call.Args[offset:])
 					}
+					return true
+				}
+				coverage.unresolved(c, call)
+				return true
+			}
+			written.read(c, p, name, sels, call)
 
-					// try generous interface first
-					offset, ok := offsets[funcSelector{fun: s.Sel.Name}]
-					if !ok {
-						// otherwise try concrete type
-						offset, ok = offsets[funcSelector{fun: s.Sel.Name, pkg: named.Obj().Pkg().Path(), typ: named.Obj().Name()}]
+			if sel, offset, other, ok := c.pairOffset(p, sels, call); ok && c.pairsOverBuilder(sels, sel) {
+				p := c.related(p, call, c.pairFuncDecl(sel, offset))
+				ignored := c.calleeRules.ignored(calleePkg(sels))
+				if other != (funcSelector{}) {
+					c.report(ignored.filter(p), call.Fun, ConflictingOffset, "%s matches both %s=%d and %s=%d; checking at the offset of the more precise selector",
+						name,
+						sel, offset,
+						other, c.offsets[other],
+					)
+				}
+				offset := c.shape(p, sel, offset, call)
+				checked.add(c, p, offset, call)
+				if call.Ellipsis.IsValid() {
+					if len(call.Args) == offset+1 && isEmptySpread(p, call.Args[offset]) {
+						// no pairs at all, which is fine
+						coverage.checked(p, sel)
+					} else if s, ok := c.spread(p, offset, call); ok {
+						p, flush := c.grouped(coverage.checked(p, sel), call)
+						c.spreadCorrect(ignored.filter(p), name, s)
+						flush()
+					} else {
+						// the pairs are in a slice we can't see into
+						coverage.skipped(sel)
 					}
-					if !ok {
-						return true
+					return true
+				}
+				p, flush := c.grouped(coverage.checked(p, sel), call)
+				p = ignored.filter(p)
+				c.argsCorrect(p, name, offset, call, ignored)
+				if profile := c.backends.profile(sels); profile != "" && len(call.Args) > offset {
+					c.reservedCorrect(p, name, profile, offset, call.Args[offset:])
+				}
+				if level := c.levels.level(sels); level != "" && level != "debug" && len(c.debugKeys) != 0 && len(call.Args) > offset {
+					c.debugKeysCorrect(p, name, level, offset, call.Args[offset:])
+				}
+				if c.sortedKeys && len(call.Args) > offset {
+					c.sortedKeysCorrect(p, name, offset, call.Args[offset:])
+				}
+				if c.repeatedKeys && !ignored[RepeatedKey] {
+					for i := offset; i < len(call.Args); i += 2 {
+						literals.add(p, call.Args[i])
 					}
+				}
+				if c.isWrapFunc(sels) {
+					c.errorsCorrect(p, name, offset, call)
+				}
+				if c.isExclusiveWrap(sels) {
+					c.mixedWrapCorrect(p, name, offset, call)
+				}
+				if c.isErrorPathFunc(sels) {
+					c.errorPathCorrect(p, name, f, call)
+				}
+				feeds.add(c, p, name, offset, call)
+				if len(call.Args) > offset {
+					inventory.addPairs(p, call.Args[offset:])
+					sites.add(p, sels, call, call.Args[offset:])
+					written.add(c, p, call.Args[offset:])
+				} else {
+					sites.add(p, sels, call, nil)
+				}
+				flush()
+			}
+			return true
+		}, func(cur *astutil.Cursor) bool {
+			// builder calls are checked on the way out, so the calls
+			// in a chain like b.Add(k, v).Add(k, v) are seen in order
+			call, ok := cur.Node().(*ast.CallExpr)
+			if !ok || len(c.builderFuncs) == 0 {
+				return true
+			}
 
-					argsCorrect(p, types.SelectionString(nv, nil), offset, c)
+			sels, name, ok := c.callSelectors(p.TypesInfo, call)
+			if !ok {
+				return true
+			}
+			if sel, ok := c.builderSelector(sels); ok {
+				if pair, _, _, ok := c.pairOffset(p, sels, call); ok && c.pairsOverBuilder(sels, pair) {
+					return true // checked as a pair func on the way in
+				}
+				if call.Ellipsis.IsValid() {
+					coverage.skipped(sel)
 					return true
-				}, nil)
+				}
+				checked.add(c, p, 0, call)
+				ignored := c.calleeRules.ignored(calleePkg(sels))
+				p := ignored.filter(coverage.checked(c.related(p, call, builderFuncDecl(sel)), sel))
+				c.builderCorrect(p, name, call, added)
+				if profile := c.backends.profile(sels); profile != "" && len(call.Args) == 2 {
+					c.reservedCorrect(p, name, profile, 0, call.Args[:1])
+				}
+				if level := c.levels.level(sels); level != "" && level != "debug" && len(c.debugKeys) != 0 && len(call.Args) == 2 {
+					c.debugKeysCorrect(p, name, level, 0, call.Args[:1])
+				}
+				if c.repeatedKeys && !ignored[RepeatedKey] && len(call.Args) == 2 {
+					literals.add(p, call.Args[0])
+				}
+				inventory.addPairs(p, call.Args)
+				sites.add(p, sels, call, call.Args)
+				written.add(c, p, call.Args)
 			}
-			return nil, nil
-		},
+			return true
+		})
+	}
+
+	if c.repeatedKeys {
+		literals.report(c, p)
+	}
+	feeds.export(p)
+	coverage.export(p)
+	if c.inventory {
+		inventory.export(p)
 	}
+	sites.export(p)
+	written.report(c, p)
+	return checked, nil
 }
@@ -1,14 +1,18 @@
 package pairs
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/ZipRecruiter/splinter/internal/ruledoc"
 )
 
 func TestAnalysis(t *testing.T) {
 	filemap := map[string]string{
-		"a/a.go": `package a
+		"a/a.go": `package a // want package:"a/b.Pairs<-a/b.NewPairs:1/1/0 a/b.Pairs<-method \\(\\*a/b.Pairs\\) AddPairs\\(i ...interface{}\\):1/1/0"
 
 import "a/b"
 
@@ -107,3 +111,46 @@ func (p *Pairs) AddPairs(i ...interface{}) {
 
 	analysistest.Run(t, dir, a, "a")
 }
+
+func TestDiagnosticRanges(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+func Log(kv ...interface{}) {}
+
+func Foo(n int) {
+	Log("id", n, 1+n, n) // want "arg 2 to a.Log is expression int but should be a constant string"
+	Log("id",
+		n, "x") // want "3 args passed to a.Log; must be even: arg 2, \"x\", is unpaired"
+}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("pair-func", "a.Log=0"); err != nil {
+		t.Fatal(err)
+	}
+
+	results := analysistest.Run(t, dir, a, "a")
+
+	// each diagnostic spans the whole offending expression, and links to
+	// the documentation of its rule
+	var got []string
+	for _, d := range results[0].Diagnostics {
+		start, end := results[0].Pass.Fset.Position(d.Pos), results[0].Pass.Fset.Position(d.End)
+		got = append(got, fmt.Sprintf("%d:%d-%d:%d", start.Line, start.Column, end.Line, end.Column))
+		if url := ruledoc.Rule(d.Category); d.URL != url {
+			t.Errorf("%s links to %q; should be %q", d.Message, d.URL, url)
+		}
+	}
+	expected := []string{"6:15-6:18", "8:6-8:9"}
+	if d := cmp.Diff(expected, got); d != "" {
+		t.Errorf("unexpected ranges (-expected +got):\n%s", d)
+	}
+}
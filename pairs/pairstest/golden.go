@@ -0,0 +1,223 @@
+// Package pairstest runs examples of the pairs analyzer, as txtar archives
+// with the diagnostics expected of them, as tests.
+package pairstest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+	analysischecker "golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/txtar"
+
+	"github.com/ZipRecruiter/splinter/pairs"
+)
+
+// goldenFile is the name of the file of an example holding the diagnostics
+// expected of it.
+const goldenFile = "golden"
+
+// UpdateGoldenEnv is the environment variable which, when set to 1, makes
+// RunGolden rewrite the golden diagnostics of each example to those
+// reported, rather than compare them.
+const UpdateGoldenEnv = "SPLINTER_UPDATE_GOLDEN"
+
+// RunGolden runs each example in dir, a txtar archive named <name>.txtar,
+// as a subtest, failing it if the analyzer's diagnostics differ from the
+// golden ones.  The comment of an archive holds the flags of the analyzer,
+// one per line as -name=value, where blank lines and lines starting with #
+// are ignored; its files are a GOPATH tree of packages, every one of which
+// is analyzed, and a file named golden with the expected diagnostics, one
+// per line as <file>:<line>:<col>: <rule>: <message>, sorted by position.
+// Examples setting a -preset left out of the build are skipped.
+//
+// This is the executable spec of the analyzer: contributors adding rules
+// add examples of them, and users can point it at a corpus of their own to
+// check what an upgrade changes.
+func RunGolden(t *testing.T, dir string) {
+	t.Helper()
+	archives, err := filepath.Glob(filepath.Join(dir, "*.txtar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archives) == 0 {
+		t.Fatalf("no examples in %s", dir)
+	}
+	for _, archive := range archives {
+		t.Run(strings.TrimSuffix(filepath.Base(archive), ".txtar"), func(t *testing.T) {
+			runExample(t, archive)
+		})
+	}
+}
+
+// exampleFlags returns the flags set by the comment of an example, as name
+// and value pairs.
+func exampleFlags(ar *txtar.Archive) ([][2]string, error) {
+	var flags [][2]string
+	for _, line := range strings.Split(string(ar.Comment), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(strings.TrimPrefix(line, "-"), "=")
+		if !ok || !strings.HasPrefix(line, "-") {
+			return nil, fmt.Errorf("invalid flag %q; should be -name=value", line)
+		}
+		flags = append(flags, [2]string{name, value})
+	}
+	return flags, nil
+}
+
+func runExample(t *testing.T, archive string) {
+	ar, err := txtar.ParseFile(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	flags, err := exampleFlags(ar)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := pairs.NewAnalyzer()
+	for _, f := range flags {
+		if f[0] == "preset" && !builtIn(f[1]) {
+			t.Skipf("preset %s isn't built in", f[1])
+		}
+		if err := a.Flags.Set(f[0], f[1]); err != nil {
+			t.Fatalf("-%s=%s: %s", f[0], f[1], err)
+		}
+	}
+
+	filemap := map[string]string{}
+	var golden *txtar.File
+	pkgs := map[string]bool{}
+	for i, f := range ar.Files {
+		if f.Name == goldenFile {
+			golden = &ar.Files[i]
+			continue
+		}
+		filemap[f.Name] = string(f.Data)
+		if strings.HasSuffix(f.Name, ".go") {
+			pkgs[path.Dir(f.Name)] = true
+		}
+	}
+	var patterns []string
+	for pkg := range pkgs {
+		patterns = append(patterns, pkg)
+	}
+	sort.Strings(patterns)
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	got, err := exampleDiagnostics(a, dir, patterns)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if os.Getenv(UpdateGoldenEnv) == "1" {
+		if golden == nil {
+			ar.Files = append(ar.Files, txtar.File{Name: goldenFile})
+			golden = &ar.Files[len(ar.Files)-1]
+		}
+		golden.Data = []byte(got)
+		if err := os.WriteFile(archive, txtar.Format(ar), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	var expected string
+	if golden != nil {
+		expected = string(golden.Data)
+	}
+	if got != expected {
+		t.Errorf("diagnostics differ from %s; set %s=1 to update it\nexpected:\n%s\ngot:\n%s", archive, UpdateGoldenEnv, expected, got)
+	}
+}
+
+// builtIn returns true if the named preset was built in.
+func builtIn(name string) bool {
+	for _, p := range pairs.Presets {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// exampleDiagnostics returns the diagnostics of a on the packages matching
+// patterns in the GOPATH tree at dir, in the form of a golden file.
+func exampleDiagnostics(a *analysis.Analyzer, dir string, patterns []string) (string, error) {
+	src := filepath.Join(dir, "src")
+	cfg := &packages.Config{
+		Mode: packages.LoadAllSyntax,
+		Dir:  src,
+		Env:  append(os.Environ(), "GOPATH="+dir, "GO111MODULE=off", "GOPROXY=off", "GOWORK=off"),
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return "", err
+	}
+	if n := packages.PrintErrors(pkgs); n > 0 {
+		return "", fmt.Errorf("%d errors loading packages", n)
+	}
+
+	graph, err := analysischecker.Analyze([]*analysis.Analyzer{a}, pkgs, nil)
+	if err != nil {
+		return "", err
+	}
+
+	type line struct {
+		file          string
+		line, col     int
+		rule, message string
+	}
+	var lines []line
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			return "", act.Err
+		}
+		for _, d := range act.Diagnostics {
+			posn := act.Package.Fset.Position(d.Pos)
+			file := posn.Filename
+			if rel, err := filepath.Rel(src, file); err == nil {
+				file = filepath.ToSlash(rel)
+			}
+			lines = append(lines, line{file, posn.Line, posn.Column, d.Category, d.Message})
+		}
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		li, lj := lines[i], lines[j]
+		if li.file != lj.file {
+			return li.file < lj.file
+		}
+		if li.line != lj.line {
+			return li.line < lj.line
+		}
+		if li.col != lj.col {
+			return li.col < lj.col
+		}
+		if li.rule != lj.rule {
+			return li.rule < lj.rule
+		}
+		return li.message < lj.message
+	})
+
+	var b bytes.Buffer
+	for _, l := range lines {
+		fmt.Fprintf(&b, "%s:%d:%d: %s: %s\n", l.file, l.line, l.col, l.rule, l.message)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,171 @@
+package pairs
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Preset is a named bundle of flag values configuring the analyzer for the
+// pair-style APIs of a library.
+type Preset struct {
+	Name string
+
+	// Packages are the import paths of the library, so that tools can
+	// pick presets by what a module imports.
+	Packages []string
+
+	// Flags maps the names of flags to the values the preset sets.
+	Flags map[string][]string
+}
+
+// selectors returns pair func values for the named funcs or methods of pkg,
+// which is a package path or a package path and type, all with one offset.
+func selectors(pkg string, offset int, names ...string) []string {
+	var v []string
+	for _, name := range names {
+		v = append(v, fmt.Sprintf("%s.%s=%d", pkg, name, offset))
+	}
+	return v
+}
+
+func concat(lists ...[]string) []string {
+	var v []string
+	for _, l := range lists {
+		v = append(v, l...)
+	}
+	return v
+}
+
+var slogFuncs = concat(
+	selectors("log/slog", 1, "Debug", "Info", "Warn", "Error", "Group"),
+	selectors("log/slog", 2, "DebugContext", "InfoContext", "WarnContext", "ErrorContext"),
+	selectors("log/slog", 3, "Log"),
+	selectors("log/slog", 0, "With"),
+	selectors("log/slog.Logger", 1, "Debug", "Info", "Warn", "Error"),
+	selectors("log/slog.Logger", 2, "DebugContext", "InfoContext", "WarnContext", "ErrorContext"),
+	selectors("log/slog.Logger", 3, "Log"),
+	selectors("log/slog.Logger", 0, "With"),
+)
+
+// Presets lists the presets that can be passed to -preset.  The slog preset
+// is always built in, since it's the default; each of the others is in a
+// file of its own, left out of builds with the splinter_minimal tag or the
+// splinter_no_<preset> tag naming it, like splinter_no_zap_sugar, to keep
+// the binary and its flags small.  They're added in the order of their
+// files' names.
+var Presets = []Preset{{
+	Name:     "slog",
+	Packages: []string{"log/slog"},
+	Flags: map[string][]string{
+		"pair-func": slogFuncs,
+		"backend":   profiled("slog", slogFuncs),
+		"level":     leveled(slogFuncs),
+	},
+}}
+
+// presetFlag applies the presets it's set to to the flags of an analyzer.
+// It's a flag.Value accepting a comma separated list of preset names.
+type presetFlag struct {
+	fset  *flag.FlagSet
+	names []string
+}
+
+func (f *presetFlag) Set(v string) error {
+	for _, name := range strings.Split(v, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if err := f.apply(name); err != nil {
+			return err
+		}
+		f.names = append(f.names, name)
+	}
+	return nil
+}
+
+func (f *presetFlag) apply(name string) error {
+	for _, p := range Presets {
+		if p.Name != name {
+			continue
+		}
+		for flag, values := range p.Flags {
+			for _, v := range values {
+				if err := f.fset.Set(flag, v); err != nil {
+					return fmt.Errorf("preset %s: -%s %s: %w", name, flag, v, err)
+				}
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unknown preset %q; should be one of %s", name, presetNames())
+}
+
+// DefaultPreset is applied to every analyzer unless -no-defaults is given,
+// so that a run without any configuration still checks something.
+const DefaultPreset = "slog"
+
+// noDefaultsFlag removes the values DefaultPreset set from the flags of an
+// analyzer when it's set to true, except those of presets named by -preset,
+// and puts them back when it's set to false.
+type noDefaultsFlag struct {
+	presets *presetFlag
+	set     bool
+}
+
+func (f *noDefaultsFlag) Set(v string) error {
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return err
+	}
+	if b == f.set {
+		return nil
+	}
+	f.set = b
+	if !b || slices.Contains(f.presets.names, DefaultPreset) {
+		return f.presets.apply(DefaultPreset)
+	}
+
+	for _, p := range Presets {
+		if p.Name != DefaultPreset {
+			continue
+		}
+		for flag, values := range p.Flags {
+			for _, v := range values {
+				if err := f.presets.fset.Set(flag, "!"+v); err != nil {
+					return fmt.Errorf("-no-defaults: -%s %s: %w", flag, v, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (f *noDefaultsFlag) String() string {
+	if f == nil {
+		return "false"
+	}
+	return strconv.FormatBool(f.set)
+}
+
+// IsBoolFlag lets -no-defaults be given without a value.
+func (f *noDefaultsFlag) IsBoolFlag() bool { return true }
+
+func presetNames() string {
+	var names []string
+	for _, p := range Presets {
+		names = append(names, p.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func (f *presetFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return strings.Join(f.names, ",")
+}
@@ -0,0 +1,24 @@
+//go:build !splinter_minimal && !splinter_no_event_headers
+
+package pairs
+
+func init() {
+	Presets = append(Presets, Preset{
+		// header helpers add one pair per call, and a misaligned header
+		// silently misroutes a message downstream
+		Name: "event-headers",
+		Packages: []string{
+			"github.com/ThreeDotsLabs/watermill/message",
+			"github.com/nats-io/nats.go",
+			"github.com/cloudevents/sdk-go/v2/event",
+		},
+		Flags: map[string][]string{
+			"builder-func": {
+				"github.com/ThreeDotsLabs/watermill/message.Metadata.Set",
+				"github.com/nats-io/nats.go.Header.Add",
+				"github.com/nats-io/nats.go.Header.Set",
+				"github.com/cloudevents/sdk-go/v2/event.Event.SetExtension",
+			},
+		},
+	})
+}
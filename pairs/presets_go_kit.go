@@ -0,0 +1,21 @@
+//go:build !splinter_minimal && !splinter_no_go_kit
+
+package pairs
+
+var goKitFuncs = concat(
+	selectors("github.com/go-kit/log.Logger", 0, "Log"),
+	selectors("github.com/go-kit/log", 1, "With", "WithPrefix", "WithSuffix"),
+	selectors("github.com/go-kit/kit/log.Logger", 0, "Log"),
+	selectors("github.com/go-kit/kit/log", 1, "With", "WithPrefix", "WithSuffix"),
+)
+
+func init() {
+	Presets = append(Presets, Preset{
+		Name:     "go-kit",
+		Packages: []string{"github.com/go-kit/log", "github.com/go-kit/kit/log"},
+		Flags: map[string][]string{
+			"pair-func": goKitFuncs,
+			"backend":   profiled("gokit", goKitFuncs),
+		},
+	})
+}
@@ -0,0 +1,17 @@
+//go:build !splinter_minimal && !splinter_no_grpc_metadata
+
+package pairs
+
+func init() {
+	Presets = append(Presets, Preset{
+		// metadata.Pairs panics on an odd number of args
+		Name:     "grpc-metadata",
+		Packages: []string{"google.golang.org/grpc/metadata"},
+		Flags: map[string][]string{
+			"pair-func": concat(
+				selectors("google.golang.org/grpc/metadata", 0, "Pairs"),
+				selectors("google.golang.org/grpc/metadata", 1, "AppendToOutgoingContext"),
+			),
+		},
+	})
+}
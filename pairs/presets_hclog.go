@@ -0,0 +1,16 @@
+//go:build !splinter_minimal && !splinter_no_hclog
+
+package pairs
+
+func init() {
+	Presets = append(Presets, Preset{
+		Name:     "hclog",
+		Packages: []string{"github.com/hashicorp/go-hclog"},
+		Flags: map[string][]string{
+			"pair-func": concat(
+				selectors("github.com/hashicorp/go-hclog.Logger", 1, "Trace", "Debug", "Info", "Warn", "Error"),
+				selectors("github.com/hashicorp/go-hclog.Logger", 0, "With"),
+			),
+		},
+	})
+}
@@ -0,0 +1,18 @@
+//go:build !splinter_minimal && !splinter_no_klog
+
+package pairs
+
+func init() {
+	Presets = append(Presets, Preset{
+		Name:     "klog",
+		Packages: []string{"k8s.io/klog/v2"},
+		Flags: map[string][]string{
+			"pair-func": concat(
+				selectors("k8s.io/klog/v2", 1, "InfoS"),
+				selectors("k8s.io/klog/v2", 2, "ErrorS"),
+				selectors("k8s.io/klog/v2.Verbose", 1, "InfoS"),
+				selectors("k8s.io/klog/v2.Verbose", 2, "ErrorS"),
+			),
+		},
+	})
+}
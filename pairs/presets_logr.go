@@ -0,0 +1,17 @@
+//go:build !splinter_minimal && !splinter_no_logr
+
+package pairs
+
+func init() {
+	Presets = append(Presets, Preset{
+		Name:     "logr",
+		Packages: []string{"github.com/go-logr/logr"},
+		Flags: map[string][]string{
+			"pair-func": concat(
+				selectors("github.com/go-logr/logr.Logger", 1, "Info"),
+				selectors("github.com/go-logr/logr.Logger", 2, "Error"),
+				selectors("github.com/go-logr/logr.Logger", 0, "WithValues"),
+			),
+		},
+	})
+}
@@ -0,0 +1,259 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+// skipUnlessPreset skips the test if the named preset was left out of the
+// build by its tags.
+func skipUnlessPreset(t *testing.T, name string) {
+	t.Helper()
+	for _, p := range Presets {
+		if p.Name == name {
+			return
+		}
+	}
+	t.Skipf("preset %s isn't built in", name)
+}
+
+func TestPresets(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import (
+	"context"
+	"log/slog"
+)
+
+func Foo(ctx context.Context, l *slog.Logger, id int) {
+	slog.Info("saved", "user_id", id)
+	slog.Info("saved", "user_id") // want "2 args passed to log/slog.Info; must be even"
+	l.InfoContext(ctx, "saved", id, "user_id") // want "arg 2 to method \\(\\*log/slog.Logger\\) InfoContext\\(ctx context.Context, msg string, args ...any\\) is expression int but should be a constant string"
+	l.With("userID", id).Info("saved") // want "key \"userID\" \\(arg 0 to method \\(\\*log/slog.Logger\\) With\\(args ...any\\) \\*log/slog.Logger\\) is not in the keys vocabulary"
+}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("preset", "slog"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Flags.Set("key", "user_id,request_id"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Flags.Set("preset", "nope"); err == nil {
+		t.Error("expected error for unknown preset")
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
+
+func TestPresetSelectors(t *testing.T) {
+	for _, p := range Presets {
+		a := NewAnalyzer()
+		if err := a.Flags.Set("preset", p.Name); err != nil {
+			t.Errorf("preset %s: %s", p.Name, err)
+		}
+	}
+}
+
+func TestGRPCMetadataPreset(t *testing.T) {
+	skipUnlessPreset(t, "grpc-metadata")
+
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func Foo(ctx context.Context, id, key string) {
+	_ = metadata.Pairs("user-id", id)
+	_ = metadata.Pairs("user-id", id, "request-id") // want "3 args passed to google.golang.org/grpc/metadata.Pairs; must be even"
+	_ = metadata.AppendToOutgoingContext(ctx, "user-id") // want "2 args passed to google.golang.org/grpc/metadata.AppendToOutgoingContext; must be even"
+	_ = metadata.AppendToOutgoingContext(ctx, key, id)
+}
+`,
+		"google.golang.org/grpc/metadata/metadata.go": `package metadata
+
+import "context"
+
+type MD map[string][]string
+
+func Pairs(kv ...string) MD { return nil }
+
+func AppendToOutgoingContext(ctx context.Context, kv ...string) context.Context { return ctx }
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("preset", "grpc-metadata"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
+
+func TestEventHeadersPreset(t *testing.T) {
+	skipUnlessPreset(t, "event-headers")
+
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import (
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/nats-io/nats.go"
+)
+
+func Foo(md message.Metadata, h nats.Header, key, id string) {
+	md.Set("user_id", id)
+	md.Set(key, id) // want "arg 0 to method \\(github.com/ThreeDotsLabs/watermill/message.Metadata\\) Set\\(key string, value string\\) is expression string but should be a constant string"
+	h.Add("user_id", id)
+	h.Set(key, id) // want "arg 0 to method \\(github.com/nats-io/nats.go.Header\\) Set\\(key string, value string\\) is expression string but should be a constant string"
+}
+`,
+		"github.com/ThreeDotsLabs/watermill/message/message.go": `package message
+
+type Metadata map[string]string
+
+func (m Metadata) Set(key, value string) { m[key] = value }
+`,
+		"github.com/nats-io/nats.go/nats.go": `package nats
+
+type Header map[string][]string
+
+func (h Header) Add(key, value string) {}
+
+func (h Header) Set(key, value string) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("preset", "event-headers"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
+
+func TestZerologPreset(t *testing.T) {
+	skipUnlessPreset(t, "zerolog")
+
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import "github.com/rs/zerolog"
+
+func Foo(l zerolog.Logger, key, id string) {
+	l.Info().Str("user_id", id).Int("attempt", 1).Msg("ok")
+	l.Info().Str(key, id).Msg("bad") // want "arg 0 to method \\(\\*github.com/rs/zerolog.Event\\) Str\\(key string, val string\\) \\*github.com/rs/zerolog.Event is expression string but should be a constant string"
+	l.With().Interface(key, id).Logger() // want "arg 0 to method \\(github.com/rs/zerolog.Context\\) Interface\\(key string, i interface{}\\) github.com/rs/zerolog.Context is expression string but should be a constant string"
+}
+`,
+		"github.com/rs/zerolog/zerolog.go": `package zerolog
+
+type Logger struct{}
+
+func (l Logger) Info() *Event { return &Event{} }
+
+func (l Logger) With() Context { return Context{} }
+
+type Event struct{}
+
+func (e *Event) Str(key, val string) *Event { return e }
+
+func (e *Event) Int(key string, i int) *Event { return e }
+
+func (e *Event) Msg(msg string) {}
+
+type Context struct{}
+
+func (c Context) Interface(key string, i interface{}) Context { return c }
+
+func (c Context) Logger() Logger { return Logger{} }
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("preset", "zerolog"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
+
+func TestDefaultPreset(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import "log/slog"
+
+func Foo(id int) {
+	slog.Info("saved", "user_id") // want "2 args passed to log/slog.Info; must be even"
+}
+`,
+		"b/b.go": `package b
+
+import "log/slog"
+
+func Foo(id int) {
+	slog.Info("saved", "user_id")
+}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	// slog is checked without any flags
+	analysistest.Run(t, dir, NewAnalyzer(), "a")
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("no-defaults", "true"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, dir, a, "b")
+	if got := a.Flags.Lookup("pair-func").Value.String(); got != "" {
+		t.Errorf("pair-func = %q with -no-defaults; expected none", got)
+	}
+
+	// a preset named explicitly stays
+	a = NewAnalyzer()
+	for flag, v := range map[string]string{"preset": "slog", "no-defaults": "true"} {
+		if err := a.Flags.Set(flag, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	analysistest.Run(t, dir, a, "a")
+}
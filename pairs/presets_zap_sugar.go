@@ -0,0 +1,20 @@
+//go:build !splinter_minimal && !splinter_no_zap_sugar
+
+package pairs
+
+var zapSugarFuncs = concat(
+	selectors("go.uber.org/zap.SugaredLogger", 1, "Debugw", "Infow", "Warnw", "Errorw", "DPanicw", "Panicw", "Fatalw"),
+	selectors("go.uber.org/zap.SugaredLogger", 0, "With"),
+)
+
+func init() {
+	Presets = append(Presets, Preset{
+		Name:     "zap-sugar",
+		Packages: []string{"go.uber.org/zap"},
+		Flags: map[string][]string{
+			"pair-func": zapSugarFuncs,
+			"backend":   profiled("zap", zapSugarFuncs),
+			"level":     leveled(zapSugarFuncs),
+		},
+	})
+}
@@ -0,0 +1,30 @@
+//go:build !splinter_minimal && !splinter_no_zerolog
+
+package pairs
+
+// zerologFields returns builder func values for the methods of typ, a
+// zerolog type, that add a field by key.
+func zerologFields(typ string) []string {
+	var v []string
+	for _, name := range []string{
+		"Str", "Strs", "Stringer", "Bytes", "Hex", "RawJSON",
+		"Int", "Int8", "Int16", "Int32", "Int64", "Uint", "Uint8", "Uint16", "Uint32", "Uint64",
+		"Float32", "Float64", "Bool", "Time", "Dur", "IPAddr", "AnErr",
+		"Interface", "Any", "Dict", "Array", "Object",
+	} {
+		v = append(v, "github.com/rs/zerolog."+typ+"."+name)
+	}
+	return v
+}
+
+func init() {
+	Presets = append(Presets, Preset{
+		// zerolog takes no pairs, but each field method of its events
+		// and contexts adds one, so they're checked as builders
+		Name:     "zerolog",
+		Packages: []string{"github.com/rs/zerolog"},
+		Flags: map[string][]string{
+			"builder-func": concat(zerologFields("Event"), zerologFields("Context")),
+		},
+	})
+}
@@ -0,0 +1,15 @@
+//go:build !splinter_minimal && !splinter_no_zr_errors
+
+package pairs
+
+func init() {
+	Presets = append(Presets, Preset{
+		Name:     "zr-errors",
+		Packages: []string{"go.zr.org/common/go/errors", "go.zr.org/common/go/errors/details"},
+		Flags: map[string][]string{
+			"pair-func":   {"go.zr.org/common/go/errors.Wrap=2", "go.zr.org/common/go/errors/details.Pairs.AddPairs=0"},
+			"wrap-func":   {"go.zr.org/common/go/errors.Wrap"},
+			"assume-pair": {"go.zr.org/common/go/errors/details.Pairs"},
+		},
+	})
+}
@@ -0,0 +1,112 @@
+package pairs
+
+import (
+	"fmt"
+	"go/ast"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/internal/calls"
+	"github.com/ZipRecruiter/splinter/internal/config"
+	"github.com/ZipRecruiter/splinter/internal/keys"
+)
+
+// backendFields lists, by backend profile, the fields each backend adds to
+// every entry with its usual configuration.
+var backendFields = map[string][]string{
+	"zap":   {"level", "ts", "logger", "caller", "msg", "stacktrace"},
+	"slog":  {"time", "level", "msg", "source"},
+	"gokit": {"ts", "caller", "level"},
+}
+
+func profileNames() string {
+	var names []string
+	for name := range backendFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// backendProfiles maps pair funcs to the profile of the backend they log
+// to.  It is a flag.Value accepting [pkg[.type]].<func>=<profile>.
+type backendProfiles map[funcSelector]string
+
+func (b backendProfiles) Set(v string) error {
+	if v, ok := config.Removal(v); ok {
+		if i := strings.LastIndexByte(v, '='); i >= 0 {
+			v = v[:i]
+		}
+		sel, err := calls.ParseSelector(v)
+		if err != nil {
+			return err
+		}
+		delete(b, newFuncSelector(sel))
+		return nil
+	}
+
+	i := strings.LastIndexByte(v, '=')
+	if i < 0 {
+		return fmt.Errorf("invalid backend %q; should be of form [pkg[.type]].<func>=<profile>", v)
+	}
+	sel, err := calls.ParseSelector(v[:i])
+	if err != nil {
+		return err
+	}
+	profile := v[i+1:]
+	if _, ok := backendFields[profile]; !ok {
+		return fmt.Errorf("unknown backend profile %q; should be one of %s", profile, profileNames())
+	}
+
+	b[newFuncSelector(sel)] = profile
+	return nil
+}
+
+func (b backendProfiles) String() string {
+	var s []string
+	for sel, profile := range b {
+		s = append(s, sel.String()+"="+profile)
+	}
+	sort.Strings(s)
+	return strings.Join(s, ",")
+}
+
+// profile returns the backend profile of the func selected by sels, if any.
+func (b backendProfiles) profile(sels []funcSelector) string {
+	for _, sel := range sels {
+		if profile, ok := b[sel]; ok {
+			return profile
+		}
+	}
+	return ""
+}
+
+// profiled returns the selectors of pairFuncs, which are of the form taken
+// by -pair-func, annotated with profile in the form taken by -backend.
+func profiled(profile string, pairFuncs []string) []string {
+	var v []string
+	for _, f := range pairFuncs {
+		v = append(v, f[:strings.LastIndexByte(f, '=')]+"="+profile)
+	}
+	return v
+}
+
+// reservedCorrect reports the constant keys among args, the pairs of a call
+// to name starting at arg offset, that collide with a field the backend
+// of profile adds to every entry.
+func (c *checker) reservedCorrect(p *analysis.Pass, name, profile string, offset int, args []ast.Expr) {
+	for i := 0; i < len(args); i += 2 {
+		kind, _, key := c.classify(p, args[i])
+		if kind != keys.Constant {
+			continue
+		}
+		for _, field := range backendFields[profile] {
+			if key == field {
+				c.report(p, args[i], ReservedKey, "key %q (arg %d to %s) collides with a field %s adds to every entry", key, i+offset, name, profile)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestBackendProfiles(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import (
+	"a/zap"
+	"log/slog"
+)
+
+func Foo(s *zap.SugaredLogger, name string) {
+	s.Infow("failed", "caller", name) // want "key \"caller\" \\(arg 1 to method \\(\\*a/zap.SugaredLogger\\) Infow\\(msg string, kv ...interface{}\\)\\) collides with a field zap adds to every entry"
+	s.Infow("failed", "time", name)
+	s.Log("caller", name)
+
+	slog.Info("failed", "time", name) // want "key \"time\" \\(arg 1 to log/slog.Info\\) collides with a field slog adds to every entry"
+	slog.Info("failed", "caller", name)
+}
+`,
+		"a/zap/zap.go": `package zap
+
+type SugaredLogger struct{}
+
+func (s *SugaredLogger) Infow(msg string, kv ...interface{}) {}
+
+func (s *SugaredLogger) Log(kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for flag, v := range map[string]string{
+		"preset":    "slog",
+		"pair-func": "a/zap.SugaredLogger.Infow=1",
+		"backend":   "a/zap.SugaredLogger.Infow=zap",
+	} {
+		if err := a.Flags.Set(flag, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := a.Flags.Set("pair-func", "a/zap.SugaredLogger.Log=0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Flags.Set("backend", "a/zap.SugaredLogger.Log=logrus"); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
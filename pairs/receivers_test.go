@@ -0,0 +1,220 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestNestedReceivers(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import "a/log"
+
+type D struct{ logger *log.Logger }
+type C struct{ d D }
+type B struct{ c *C }
+type A struct{ b B }
+
+// loggers embedded in loggers, as wrappers add behavior
+type E1 struct{ *log.Logger }
+type E2 struct{ E1 }
+type E3 struct{ *E2 }
+
+// exempted with -not-pair-func
+type Quiet struct{ E2 }
+
+func Foo(a A, as []*A, e E3, q Quiet) {
+	a.b.c.d.logger.Log("id") // want "1 args passed to method \\(\\*a/log.Logger\\) Log\\(kv ...interface{}\\); must be even"
+	as[0].b.c.d.logger.Log("id") // want "1 args passed to .*; must be even"
+	(&a.b).c.d.logger.Log("id") // want "1 args passed to .*; must be even"
+
+	e.E2.E1.Logger.Log("id") // want "1 args passed to method \\(\\*a/log.Logger\\) Log\\(kv ...interface{}\\); must be even"
+	e.E2.Log("id") // want "1 args passed to method \\(\\*a.E2\\) Log\\(kv ...interface{}\\); must be even"
+	e.Log("id") // want "1 args passed to method \\(a.E3\\) Log\\(kv ...interface{}\\); must be even"
+	q.Log("id")
+}
+`,
+		"a/log/log.go": `package log
+
+type Logger struct{}
+
+func (*Logger) Log(kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("pair-func", "a/log.Logger.Log=0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Flags.Set("not-pair-func", "a.Quiet.Log"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, dir, a, "a")
+}
+
+func TestEmbeddedSelectors(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import "a/log"
+
+type E1 struct{ *log.Logger }
+type E2 struct{ E1 }
+type E3 struct{ *E2 }
+
+func Foo(l *log.Logger, e1 E1, e3 E3) {
+	l.Log("id")
+	e1.Log("id")
+	e3.Log("id") // want "1 args passed to method \\(a.E3\\) Log\\(kv ...interface{}\\); must be even"
+	e3.E2.Log("id") // want "1 args passed to .*; must be even"
+}
+`,
+		"a/log/log.go": `package log
+
+type Logger struct{}
+
+func (*Logger) Log(kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	// the types a method is promoted through select it too
+	a := NewAnalyzer()
+	if err := a.Flags.Set("pair-func", "a.E2.Log=0"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, dir, a, "a")
+}
+
+func TestNestedBuilders(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import "a/details"
+
+type request struct{ meta meta }
+type meta struct{ inner *inner }
+type inner struct{ details *details.Builder }
+
+var global request
+
+func Foo(r request, other request) {
+	r.meta.inner.details.Add("id", 1)
+	r.meta.inner.details.Add("id", 2) // want "key \"id\" passed to .* was already added on line 12"
+	other.meta.inner.details.Add("id", 3)
+
+	// package variables can be added to by other funcs
+	global.meta.inner.details.Add("id", 1)
+	global.meta.inner.details.Add("id", 2)
+}
+`,
+		"b/b.go": `package b
+
+import "a/details"
+
+type request struct{ meta meta }
+type meta struct{ inner *inner }
+type inner struct{ details *details.Builder }
+
+func Foo(r request) {
+	// beyond -receiver-depth
+	r.meta.inner.details.Add("id", 1)
+	r.meta.inner.details.Add("id", 2)
+}
+`,
+		"a/details/details.go": `package details
+
+type Builder struct{ kv []interface{} }
+
+func (b *Builder) Add(k string, v interface{}) *Builder {
+	b.kv = append(b.kv, k, v)
+	return b
+}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for _, f := range [][2]string{
+		{"builder-func", "a/details.Builder.Add"},
+		{"duplicate-keys", "true"},
+	} {
+		if err := a.Flags.Set(f[0], f[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	analysistest.Run(t, dir, a, "a")
+
+	if err := a.Flags.Set("receiver-depth", "2"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, dir, a, "b")
+}
+
+// TestPointerReceivers checks that calls through pointers match the
+// selectors of the types they point to, and that methods of universe types
+// like error match generous selectors.
+func TestPointerReceivers(t *testing.T) {
+	filemap := map[string]string{
+		"a/log/log.go": `package log
+
+type Logger struct{}
+
+func (*Logger) Log(kv ...interface{}) {}
+
+type Value struct{}
+
+func (Value) Log(kv ...interface{}) {}
+`,
+		"a/a.go": `package a
+
+import "a/log"
+
+func Foo(l *log.Logger, v *log.Value, w log.Value, err error) {
+	l.Log("a") // want "1 args passed to .*; must be even"
+	v.Log("a") // want "1 args passed to .*; must be even"
+	w.Log("a") // want "1 args passed to .*; must be even"
+	(*v).Log("a") // want "1 args passed to .*; must be even"
+
+	l.Log("err", err.Error()) // want "calls method \\(error\\) Error\\(\\) string, which has side effects"
+}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for _, f := range []struct{ name, value string }{
+		{"pair-func", "a/log.Logger.Log=0"},
+		{"pair-func", "a/log.Value.Log=0"},
+		{"side-effect-func", ".Error"},
+	} {
+		if err := a.Flags.Set(f.name, f.value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
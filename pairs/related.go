@@ -0,0 +1,58 @@
+package pairs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// related returns a copy of p that adds related information to the
+// diagnostics reported for call, a call of a pair func or builder func,
+// pointing at the declaration of the func, described by decl, if its
+// position is known.
+func (c *checker) related(p *analysis.Pass, call *ast.CallExpr, decl string) *analysis.Pass {
+	pos := declaration(p.TypesInfo, call)
+
+	rel := *p
+	rel.Report = func(d analysis.Diagnostic) {
+		if pos.IsValid() {
+			d.Related = append(d.Related, analysis.RelatedInformation{Pos: pos, Message: decl})
+		}
+		p.Report(d)
+	}
+	return &rel
+}
+
+// pairFuncDecl describes the declaration of a pair func selected by sel,
+// taking its pairs from offset, for related information.
+func (c *checker) pairFuncDecl(sel funcSelector, offset int) string {
+	if o, ok := c.offsets[sel]; ok && o == offset {
+		return fmt.Sprintf("declared here; -pair-func %s=%d selects it", sel, offset)
+	}
+	return fmt.Sprintf("declared here; a pair func taking its pairs from arg %d", offset)
+}
+
+// builderFuncDecl describes the declaration of a builder func selected by
+// sel, for related information.
+func builderFuncDecl(sel funcSelector) string {
+	return fmt.Sprintf("declared here; -builder-func %s selects it", sel)
+}
+
+// declaration returns the position of the declaration of the func or
+// method call calls, or of the named func type of the value it calls, or
+// token.NoPos if it calls neither.
+func declaration(info *types.Info, call *ast.CallExpr) token.Pos {
+	switch obj := typeutil.Callee(info, call).(type) {
+	case *types.Func:
+		return obj.Origin().Pos()
+	case *types.Var:
+		if named, ok := obj.Type().(*types.Named); ok {
+			return named.Obj().Pos()
+		}
+	}
+	return token.NoPos
+}
@@ -0,0 +1,82 @@
+package pairs
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestRelated(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import "a/log"
+
+func Foo(l *log.Logger, b *log.Builder, f log.Func, id int) {
+	log.Log("id", id, "name") // want "3 args passed to a/log.Log; must be even"
+	l.Log(id, "id") // want "arg 0 to .* is expression int but should be a constant string"
+	b.Add(id, "id") // want "arg 0 to .* is expression int but should be a constant string"
+	f("id") // want "1 args passed to a/log.Func; must be even"
+	log.Annotated(id, "id") // want "arg 0 to .* is expression int but should be a constant string"
+}
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+
+type Logger struct{}
+
+func (*Logger) Log(kv ...interface{}) {}
+
+type Builder struct{}
+
+func (b *Builder) Add(k, v interface{}) *Builder { return b }
+
+type Func func(kv ...interface{})
+
+//splinter:pairs
+func Annotated(kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for _, f := range [][2]string{
+		{"pair-func", "a/log.Log=0"},
+		{"pair-func", ".Log=0"},
+		{"pair-func", "a/log.Func=0"},
+		{"builder-func", "a/log.Builder.Add"},
+	} {
+		if err := a.Flags.Set(f[0], f[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	results := analysistest.Run(t, dir, a, "a")
+
+	var got []string
+	fset := results[0].Pass.Fset
+	for _, d := range results[0].Diagnostics {
+		for _, r := range d.Related {
+			posn := fset.Position(r.Pos)
+			got = append(got, fmt.Sprintf("%s:%d: %s", filepath.Base(posn.Filename), posn.Line, r.Message))
+		}
+	}
+	expected := []string{
+		"log.go:3: declared here; -pair-func a/log.Log=0 selects it",
+		"log.go:7: declared here; -pair-func .Log=0 selects it",
+		"log.go:11: declared here; -builder-func a/log.Builder.Add selects it",
+		"log.go:13: declared here; -pair-func a/log.Func=0 selects it",
+		"log.go:16: declared here; a pair func taking its pairs from arg 0",
+	}
+	if d := cmp.Diff(expected, got); d != "" {
+		t.Errorf("unexpected related information (-expected +got):\n%s", d)
+	}
+}
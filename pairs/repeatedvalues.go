@@ -0,0 +1,61 @@
+package pairs
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// repeatedValuesCorrect reports the values among pairs, starting at arg
+// offset of a call to name, that are the same variable or field as the
+// value of the pair before them, under a different key.  Two keys for one
+// value are usually a copy-paste of the pair whose key wasn't updated.
+func (c *checker) repeatedValuesCorrect(p *analysis.Pass, name string, offset int, pairs []ast.Expr) {
+	for i := 3; i < len(pairs); i += 2 {
+		prev, value := pairs[i-2], pairs[i]
+		if !isVariable(p, prev) || types.ExprString(ast.Unparen(prev)) != types.ExprString(ast.Unparen(value)) {
+			continue
+		}
+		if c.sameKey(p, pairs[i-3], pairs[i-1]) {
+			continue // reported as a duplicate, if at all
+		}
+		c.report(p, value, RepeatedValue, "arg %d to %s is %s, the same value as arg %d under another key; is the key a copy-paste error?",
+			i+offset,
+			name,
+			types.ExprString(value),
+			i-2+offset,
+		)
+	}
+}
+
+// isVariable returns true if e is a variable, or a field selected from one,
+// whose value is the same wherever it's read in a call.  Calls and
+// constants may well be the same for different keys.
+func isVariable(p *analysis.Pass, e ast.Expr) bool {
+	switch e := ast.Unparen(e).(type) {
+	case *ast.Ident:
+		_, ok := p.TypesInfo.Uses[e].(*types.Var)
+		return ok
+	case *ast.SelectorExpr:
+		if sel, ok := p.TypesInfo.Selections[e]; ok {
+			return sel.Kind() == types.FieldVal && isVariable(p, e.X)
+		}
+		return isVariable(p, e.Sel) // package variable
+	}
+	return false
+}
+
+// sameKey returns true if the keys a and b are the same constant, taking
+// key aliases into account, or the same expression.
+func (c *checker) sameKey(p *analysis.Pass, a, b ast.Expr) bool {
+	av, bv := p.TypesInfo.Types[a].Value, p.TypesInfo.Types[b].Value
+	if av != nil && bv != nil {
+		if av.Kind() == constant.String && bv.Kind() == constant.String {
+			return c.keyAliases.canonical(constant.StringVal(av)) == c.keyAliases.canonical(constant.StringVal(bv))
+		}
+		return av.ExactString() == bv.ExactString()
+	}
+	return types.ExprString(ast.Unparen(a)) == types.ExprString(ast.Unparen(b))
+}
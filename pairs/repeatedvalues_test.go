@@ -0,0 +1,51 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestRepeatedValues(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import (
+	"time"
+
+	"a/log"
+)
+
+type Req struct{ ID, TraceID string }
+
+func Foo(id int, r Req) {
+	log.Log("req_id", id, "trace_id", id) // want "arg 3 to a/log.Log is id, the same value as arg 1 under another key; is the key a copy-paste error\\?"
+	log.Log("req_id", r.ID, "trace_id", r.ID) // want "arg 3 to a/log.Log is r.ID, the same value as arg 1 under another key"
+	log.Log("req_id", r.ID, "trace_id", r.TraceID)
+	log.Log("req_id", id, "user", "x", "trace_id", id)
+	log.Log("ok", true, "done", true)
+	log.Log("start", time.Now(), "end", time.Now())
+}
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("pair-func", "a/log.Log=0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Flags.Set("repeated-values", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
@@ -0,0 +1,377 @@
+package pairs
+
+import (
+	"fmt"
+	"go/ast"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/internal/pkgpattern"
+)
+
+// The rules checked by the analyzer.  Each is used as the Category of the
+// diagnostics it reports, so drivers can select or filter by rule.
+const (
+	OddArity          = "odd-arity"
+	NonStringKey      = "non-string-key"
+	ExpressionKey     = "expression-key"
+	WhitelistedType   = "whitelisted-type"
+	SideEffectValue   = "side-effect-value"
+	MultipleErrors    = "multiple-errors"
+	KeyPattern        = "key-pattern"
+	DuplicateKey      = "duplicate-key"
+	RepeatedKey       = "repeated-key"
+	UnknownKey        = "unknown-key"
+	ReservedKey       = "reserved-key"
+	MixedWrap         = "mixed-wrap"
+	UnattachedError   = "unattached-error"
+	ContainerSpread   = "container-spread"
+	TypedNilValue     = "typed-nil-value"
+	ConflictingOffset = "conflicting-offset"
+	ConvertedKey      = "converted-key"
+	RepeatedValue     = "repeated-value"
+	EmptyContainer    = "empty-container"
+	UnwrittenKey      = "unwritten-key"
+	DebugKey          = "debug-key"
+	ForbiddenValue    = "forbidden-value"
+	InvalidDirective  = "invalid-directive"
+	DeprecatedKey     = "deprecated-key"
+	UnsortedKeys      = "unsorted-keys"
+	SunsetKey         = "sunset-key"
+	ExpiredKey        = "expired-key"
+	NumericKey        = "numeric-key"
+)
+
+// Rules lists every rule the analyzer can report.
+var Rules = []string{
+	OddArity,
+	NonStringKey,
+	ExpressionKey,
+	WhitelistedType,
+	SideEffectValue,
+	MultipleErrors,
+	KeyPattern,
+	DuplicateKey,
+	RepeatedKey,
+	UnknownKey,
+	ReservedKey,
+	MixedWrap,
+	UnattachedError,
+	ContainerSpread,
+	TypedNilValue,
+	ConflictingOffset,
+	ConvertedKey,
+	RepeatedValue,
+	EmptyContainer,
+	UnwrittenKey,
+	DebugKey,
+	ForbiddenValue,
+	InvalidDirective,
+	DeprecatedKey,
+	UnsortedKeys,
+	SunsetKey,
+	ExpiredKey,
+	NumericKey,
+}
+
+// RuleDoc describes a rule for tools that list the rules.
+type RuleDoc struct {
+	// Code is the rule's stable code, like SPL001, which can be used in
+	// place of its name.  Codes are never changed or reused; the pairs
+	// analyzer's are SPL001 to SPL099.
+	Code string
+
+	Description string
+
+	// Rationale, if set, explains why the rule matters, for the
+	// documentation its diagnostics link to.
+	Rationale string
+
+	// Flags are the flags that enable or configure the rule.
+	Flags []string
+
+	// OptIn is true if the rule is only checked once one of its flags
+	// is set, beyond the pair funcs of the default preset.
+	OptIn bool
+
+	// Fixable is true if the rule's diagnostics can suggest a fix, as
+	// applied by splinter fix.
+	Fixable bool
+
+	// Warning is true if the rule's diagnostics are warnings, which don't
+	// fail a run, rather than errors.
+	Warning bool
+}
+
+// RuleDocs describes each of Rules.
+var RuleDocs = map[string]RuleDoc{
+	OddArity: {
+		Code:        "SPL001",
+		Description: "a pair func is passed an odd number of pair args, or raw args spread along with a container's pairs are odd",
+		Rationale:   "Every key after a missing key or value is read as a value, and every value as a key, so the rest of the entry is garbled, usually without anyone noticing until it's searched for.",
+		Flags:       []string{"pair-func", "preset", "container-accessor"},
+	},
+	NonStringKey: {
+		Code:        "SPL002",
+		Description: "a key is a constant that isn't a string",
+		Rationale:   "A key that isn't a string is usually a value in a key's place, having shifted the pairs after it, and log backends render it inconsistently if at all.",
+		Flags:       []string{"pair-func", "preset", "stringer-keys"},
+	},
+	ExpressionKey: {
+		Code:        "SPL003",
+		Description: "a key is an expression rather than a constant string",
+		Rationale:   "A key computed at run time can't be checked by splinter, searched for in the code or relied on by dashboards and alerts, and a value mistakenly passed in a key's place looks the same.  Keys varying with the data, like a user ID, also fill indexes with fields.  Pass a constant key and the varying part as its value.",
+		Flags:       []string{"pair-func", "preset", "builder-func", "string-type-param-keys"},
+	},
+	WhitelistedType: {
+		Code:        "SPL004",
+		Description: "an -assume-pair type is passed along with other pair args",
+		Flags:       []string{"assume-pair"},
+		OptIn:       true,
+	},
+	SideEffectValue: {
+		Code:        "SPL005",
+		Description: "a value calls a func with side effects",
+		Flags:       []string{"side-effect-func"},
+		OptIn:       true,
+	},
+	MultipleErrors: {
+		Code:        "SPL006",
+		Description: "errors are passed in the pairs of a wrap func where they belong in its error arg, or more than one is",
+		Flags:       []string{"wrap-func"},
+		OptIn:       true,
+	},
+	KeyPattern: {
+		Code:        "SPL007",
+		Description: "a constant key doesn't match the key pattern or case",
+		Flags:       []string{"key-pattern", "key-case"},
+		OptIn:       true,
+		Fixable:     true,
+	},
+	DuplicateKey: {
+		Code:        "SPL008",
+		Description: "a key is added more than once to the same builder in a func",
+		Flags:       []string{"duplicate-keys", "builder-func", "receiver-depth"},
+		OptIn:       true,
+	},
+	RepeatedKey: {
+		Code:        "SPL009",
+		Description: "a string literal key is used more than once in a package",
+		Flags:       []string{"repeated-keys", "keys-package"},
+		OptIn:       true,
+	},
+	UnknownKey: {
+		Code:        "SPL010",
+		Description: "a constant key isn't in the keys vocabulary",
+		Flags:       []string{"key"},
+		OptIn:       true,
+	},
+	ReservedKey: {
+		Code:        "SPL011",
+		Description: "a constant key collides with a field the backend of the pair func adds to every entry",
+		Flags:       []string{"backend", "preset"},
+	},
+	MixedWrap: {
+		Code:        "SPL012",
+		Description: "a call both wraps an error with %w and passes pairs",
+		Flags:       []string{"exclusive-wrap-func"},
+		OptIn:       true,
+	},
+	UnattachedError: {
+		Code:        "SPL013",
+		Description: "a pair func called in an if err != nil block doesn't pass err",
+		Flags:       []string{"error-path-func"},
+		OptIn:       true,
+	},
+	ContainerSpread: {
+		Code:        "SPL014",
+		Description: "raw pairs are appended to the slice a container accessor returns, which may overwrite the container's pairs",
+		Flags:       []string{"container-accessor"},
+		OptIn:       true,
+	},
+	TypedNilValue: {
+		Code:        "SPL015",
+		Description: "a value is a nil pointer, which isn't a nil interface",
+		Flags:       []string{"typed-nil-values", "rules-version"},
+		OptIn:       true,
+	},
+	ConflictingOffset: {
+		Code:        "SPL016",
+		Description: "a call matches -pair-func selectors with different offsets",
+		Flags:       []string{"pair-func", "preset"},
+	},
+	ConvertedKey: {
+		Code:        "SPL017",
+		Description: "a constant key is passed through a conversion that doesn't change it",
+		Flags:       []string{"converted-keys", "rules-version"},
+		OptIn:       true,
+		Fixable:     true,
+	},
+	RepeatedValue: {
+		Code:        "SPL018",
+		Description: "a variable is passed as the value of two adjacent pairs with different keys",
+		Flags:       []string{"repeated-values"},
+		OptIn:       true,
+	},
+	EmptyContainer: {
+		Code:        "SPL019",
+		Description: "an -assume-pair container is passed as the pairs while still empty",
+		Flags:       []string{"empty-containers", "assume-pair"},
+		OptIn:       true,
+	},
+	UnwrittenKey: {
+		Code:        "SPL020",
+		Description: "a key a getter func reads is never written by a pair func in the package or its dependencies",
+		Flags:       []string{"getter-func"},
+		OptIn:       true,
+	},
+	DebugKey: {
+		Code:        "SPL021",
+		Description: "a debug-only key is logged by a pair func whose level is above debug",
+		Flags:       []string{"debug-key", "level"},
+		OptIn:       true,
+	},
+	ForbiddenValue: {
+		Code:        "SPL022",
+		Description: "a value is of a type that mustn't be logged whole, like a request or protobuf message",
+		Flags:       []string{"forbid-value-type"},
+		OptIn:       true,
+	},
+	InvalidDirective: {
+		Code:        "SPL023",
+		Description: "a //splinter:pairs or //splinter:assume-pair directive is malformed, or doesn't fit what it's declared on",
+	},
+	DeprecatedKey: {
+		Code:        "SPL024",
+		Description: "a constant key is the deprecated spelling of another key",
+		Flags:       []string{"key-alias"},
+		OptIn:       true,
+		Fixable:     true,
+	},
+	UnsortedKeys: {
+		Code:        "SPL025",
+		Description: "the constant keys of a call aren't in lexical order",
+		Flags:       []string{"sorted-keys"},
+		OptIn:       true,
+		Fixable:     true,
+	},
+	SunsetKey: {
+		Code:        "SPL026",
+		Description: "a constant key is used after its sunset date, within the grace period",
+		Flags:       []string{"key-sunset", "sunset-grace-days"},
+		OptIn:       true,
+		Warning:     true,
+	},
+	ExpiredKey: {
+		Code:        "SPL027",
+		Description: "a constant key is used after the grace period following its sunset date",
+		Flags:       []string{"key-sunset", "sunset-grace-days"},
+		OptIn:       true,
+	},
+	NumericKey: {
+		Code:        "SPL028",
+		Description: "a constant key is only a number, likely a value passed in the key's place",
+		Flags:       []string{"numeric-keys", "rules-version"},
+		OptIn:       true,
+		Fixable:     true,
+	},
+}
+
+// report reports a diagnostic of rule spanning n, the offending expression.
+func (c *checker) report(p *analysis.Pass, n ast.Node, rule, format string, args ...interface{}) {
+	p.Report(analysis.Diagnostic{
+		Pos:      n.Pos(),
+		End:      n.End(),
+		Category: rule,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+type ignoredRules struct {
+	pattern, names string
+	match          *regexp.Regexp
+	rules          ruleSet
+}
+
+// calleeRules ignores rules for calls to funcs declared in packages matching
+// a pattern, for APIs whose shape intentionally breaks a rule.  It is a
+// flag.Value accepting pattern=rule[,rule], where the pattern is an import
+// path that may contain ... wildcards, as with go list, and each rule may be
+// given by its code.
+type calleeRules []ignoredRules
+
+func (r *calleeRules) Set(v string) error {
+	i := strings.LastIndexByte(v, '=')
+	if i < 0 {
+		return fmt.Errorf("invalid callee rules %q; should be of form <pattern>=<rule>[,<rule>]", v)
+	}
+	pattern, names := v[:i], v[i+1:]
+
+	rules := ruleSet{}
+	for _, name := range strings.Split(names, ",") {
+		rule, ok := ruleNamed(name)
+		if !ok {
+			return fmt.Errorf("unknown rule %q", name)
+		}
+		rules[rule] = true
+	}
+
+	*r = append(*r, ignoredRules{pattern: pattern, names: names, match: pkgpattern.Regexp(pattern), rules: rules})
+	return nil
+}
+
+func (r *calleeRules) String() string {
+	var s []string
+	for _, r := range *r {
+		s = append(s, r.pattern+"="+r.names)
+	}
+	return strings.Join(s, " ")
+}
+
+// ruleNamed returns the rule name is the name or code of, if any.
+func ruleNamed(name string) (string, bool) {
+	if slices.Contains(Rules, name) {
+		return name, true
+	}
+	for rule, doc := range RuleDocs {
+		if doc.Code == name {
+			return rule, true
+		}
+	}
+	return "", false
+}
+
+// ruleSet is a set of rules.
+type ruleSet map[string]bool
+
+// ignored returns the rules ignored for calls to funcs declared in pkg.
+func (r calleeRules) ignored(pkg string) ruleSet {
+	ignored := ruleSet{}
+	for _, r := range r {
+		if r.match.MatchString(pkg) {
+			for rule := range r.rules {
+				ignored[rule] = true
+			}
+		}
+	}
+	return ignored
+}
+
+// filter returns p, or a copy of it that drops the diagnostics of the
+// rules in s.
+func (s ruleSet) filter(p *analysis.Pass) *analysis.Pass {
+	if len(s) == 0 {
+		return p
+	}
+
+	filtered := *p
+	filtered.Report = func(d analysis.Diagnostic) {
+		if !s[d.Category] {
+			p.Report(d)
+		}
+	}
+	return &filtered
+}
@@ -0,0 +1,46 @@
+package pairs
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRuleDocs(t *testing.T) {
+	a := NewAnalyzer()
+	code := regexp.MustCompile(`^SPL0\d\d$`)
+	codes := map[string]string{}
+	page, err := os.ReadFile("../docs/rules.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rule := range Rules {
+		if !strings.Contains(string(page), "\n## "+rule+"\n") {
+			t.Errorf("docs/rules.md has no section for %s; regenerate it with splinter rules -markdown", rule)
+		}
+		doc, ok := RuleDocs[rule]
+		if !ok {
+			t.Errorf("no RuleDocs entry for %s", rule)
+			continue
+		}
+		if !code.MatchString(doc.Code) {
+			t.Errorf("%s has code %q; should be SPL001 to SPL099", rule, doc.Code)
+		}
+		if other, ok := codes[doc.Code]; ok {
+			t.Errorf("%s has the code of %s, %s", rule, other, doc.Code)
+		}
+		codes[doc.Code] = rule
+		if doc.Description == "" {
+			t.Errorf("%s has no description", rule)
+		}
+		for _, name := range doc.Flags {
+			if a.Flags.Lookup(name) == nil {
+				t.Errorf("%s names unknown flag -%s", rule, name)
+			}
+		}
+	}
+	if len(RuleDocs) != len(Rules) {
+		t.Errorf("%d RuleDocs entries for %d rules", len(RuleDocs), len(Rules))
+	}
+}
@@ -0,0 +1,127 @@
+package pairs
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultChange is a change to the default value of a flag.
+type DefaultChange struct {
+	Flag     string
+	Old, New string
+}
+
+func (d DefaultChange) String() string {
+	return fmt.Sprintf("-%s (%s -> %s)", d.Flag, d.Old, d.New)
+}
+
+// RulesVersions lists, by rules version, the defaults changed from the
+// version before it; version 1 has the defaults the flags are declared with.
+// A configuration pins a version with -rules-version, so that upgrading
+// splinter doesn't change these defaults until the configuration does.
+var RulesVersions = map[int][]DefaultChange{
+	2: {
+		{Flag: "typed-nil-values", Old: "false", New: "true"},
+		{Flag: "converted-keys", Old: "false", New: "true"},
+	},
+	3: {
+		{Flag: "numeric-keys", Old: "false", New: "true"},
+	},
+}
+
+// LatestRulesVersion is the newest version in RulesVersions.
+const LatestRulesVersion = 3
+
+// RulesVersionChanges returns the defaults changed by the rules versions
+// after from, up to and including to, in order.
+func RulesVersionChanges(from, to int) []DefaultChange {
+	var changes []DefaultChange
+	for v := from + 1; v <= to; v++ {
+		changes = append(changes, RulesVersions[v]...)
+	}
+	return changes
+}
+
+// rulesVersionFlag applies the defaults of the rules version it's set to to
+// the flags of an analyzer not set explicitly, whether before or after it.
+type rulesVersionFlag struct {
+	fset    *flag.FlagSet
+	version int // 0 unless set
+}
+
+func (f *rulesVersionFlag) Set(v string) error {
+	version, err := strconv.Atoi(v)
+	if err != nil || version < 1 || version > LatestRulesVersion {
+		return fmt.Errorf("unknown rules version %q; should be 1 to %d", v, LatestRulesVersion)
+	}
+
+	// every version's defaults are applied, so a later -rules-version
+	// undoes the changes of an earlier, newer one
+	for v := 2; v <= LatestRulesVersion; v++ {
+		for _, d := range RulesVersions[v] {
+			def := d.Old
+			if v <= version {
+				def = d.New
+			}
+			f.fset.Lookup(d.Flag).Value.(*versionedBool).setDefault(def)
+		}
+	}
+	f.version = version
+	return nil
+}
+
+func (f *rulesVersionFlag) String() string {
+	if f == nil || f.version == 0 {
+		return "1"
+	}
+	return strconv.Itoa(f.version)
+}
+
+// warning returns the warning that the version f pins is behind
+// LatestRulesVersion, or "" if it isn't, or f wasn't set.
+func (f *rulesVersionFlag) warning() string {
+	if f.version == 0 || f.version == LatestRulesVersion {
+		return ""
+	}
+	var changes []string
+	for _, d := range RulesVersionChanges(f.version, LatestRulesVersion) {
+		changes = append(changes, d.String())
+	}
+	return fmt.Sprintf("rules version %d is behind %d, which changes the defaults of %s; see splinter migrate-config",
+		f.version, LatestRulesVersion, strings.Join(changes, ", "))
+}
+
+// versionedBool is a boolean flag whose default a rules version changes.
+// It remembers being set, through whichever flag set shares it, so that
+// -rules-version leaves it alone.
+type versionedBool struct {
+	value *bool
+	set   bool
+}
+
+func (b *versionedBool) Set(v string) error {
+	value, err := strconv.ParseBool(v)
+	if err != nil {
+		return err
+	}
+	*b.value, b.set = value, true
+	return nil
+}
+
+// setDefault sets b to v, a default, unless it was set explicitly.
+func (b *versionedBool) setDefault(v string) {
+	if !b.set {
+		*b.value, _ = strconv.ParseBool(v)
+	}
+}
+
+func (b *versionedBool) String() string {
+	if b == nil || b.value == nil {
+		return "false"
+	}
+	return strconv.FormatBool(*b.value)
+}
+
+func (b *versionedBool) IsBoolFlag() bool { return true }
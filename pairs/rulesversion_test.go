@@ -0,0 +1,83 @@
+package pairs
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestRulesVersion(t *testing.T) {
+	tests := []struct {
+		name                                  string
+		args                                  []string
+		typedNils, convertedKeys, numericKeys string
+	}{
+		{"unpinned", nil, "false", "false", "false"},
+		{"1", []string{"-rules-version", "1"}, "false", "false", "false"},
+		{"2", []string{"-rules-version", "2"}, "true", "true", "false"},
+		{"3", []string{"-rules-version", "3"}, "true", "true", "true"},
+		{"set before", []string{"-typed-nil-values=true", "-rules-version", "1"}, "true", "false", "false"},
+		{"set after", []string{"-rules-version", "2", "-typed-nil-values=false"}, "false", "true", "false"},
+		{"set to default before", []string{"-typed-nil-values=false", "-rules-version", "2"}, "false", "true", "false"},
+		{"downgraded", []string{"-rules-version", "3", "-rules-version", "1"}, "false", "false", "false"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a := NewAnalyzer()
+			if err := a.Flags.Parse(test.args); err != nil {
+				t.Fatal(err)
+			}
+			if v := a.Flags.Lookup("typed-nil-values").Value.String(); v != test.typedNils {
+				t.Errorf("typed-nil-values = %s; expected %s", v, test.typedNils)
+			}
+			if v := a.Flags.Lookup("converted-keys").Value.String(); v != test.convertedKeys {
+				t.Errorf("converted-keys = %s; expected %s", v, test.convertedKeys)
+			}
+			if v := a.Flags.Lookup("numeric-keys").Value.String(); v != test.numericKeys {
+				t.Errorf("numeric-keys = %s; expected %s", v, test.numericKeys)
+			}
+		})
+	}
+
+	if err := NewAnalyzer().Flags.Set("rules-version", "4"); err == nil {
+		t.Error("expected error for unknown rules version")
+	}
+}
+
+func TestRulesVersionSharedFlags(t *testing.T) {
+	// like splinter, set the flags through another flag set sharing their
+	// values
+	a := NewAnalyzer()
+	fset := flag.NewFlagSet("splinter", flag.ContinueOnError)
+	a.Flags.VisitAll(func(f *flag.Flag) { fset.Var(f.Value, f.Name, f.Usage) })
+	if err := fset.Parse([]string{"-typed-nil-values=false", "-rules-version", "2"}); err != nil {
+		t.Fatal(err)
+	}
+	if v := a.Flags.Lookup("typed-nil-values").Value.String(); v != "false" {
+		t.Errorf("typed-nil-values = %s; expected false", v)
+	}
+	if v := a.Flags.Lookup("converted-keys").Value.String(); v != "true" {
+		t.Errorf("converted-keys = %s; expected true", v)
+	}
+}
+
+func TestRulesVersionWarning(t *testing.T) {
+	tests := []struct {
+		args    []string
+		warning string
+	}{
+		{nil, ""},
+		{[]string{"-rules-version", "3"}, ""},
+		{[]string{"-rules-version", "2"}, "rules version 2 is behind 3, which changes the defaults of -numeric-keys (false -> true); see splinter migrate-config"},
+		{[]string{"-rules-version", "1"}, "rules version 1 is behind 3, which changes the defaults of -typed-nil-values (false -> true), -converted-keys (false -> true), -numeric-keys (false -> true); see splinter migrate-config"},
+	}
+	for _, test := range tests {
+		a := NewAnalyzer()
+		if err := a.Flags.Parse(test.args); err != nil {
+			t.Fatal(err)
+		}
+		if w := a.Flags.Lookup("rules-version").Value.(*rulesVersionFlag).warning(); w != test.warning {
+			t.Errorf("%v: expected warning %q, got %q", test.args, test.warning, w)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package pairs
+
+import (
+	"fmt"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/internal/config"
+)
+
+// NewAnalyzerFromSettings returns a fresh pairs analyzer configured by
+// settings instead of flags, for golangci-lint to call with the settings
+// block of a custom linter.  Settings are named after the flags they set,
+// as in a configuration file:
+//
+//	settings:
+//	  preset: [slog]
+//	  pair-func:
+//	    - example.com/log.Log=0
+//	  duplicate-keys: true
+func NewAnalyzerFromSettings(settings map[string]any) (*analysis.Analyzer, error) {
+	a := NewAnalyzer()
+	entries, err := config.Settings(settings)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if err := e.Set(&a.Flags); err != nil {
+			return nil, fmt.Errorf("settings: %w", err)
+		}
+	}
+	return a, nil
+}
@@ -0,0 +1,67 @@
+package pairs
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestNewAnalyzerFromSettings(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import "a/log"
+
+func Foo(b *log.Builder) {
+	log.Log("a", 1, 2) // want "3 args passed to a/log.Log; must be even"
+	log.Info("msg", "a", 1, 2) // want "4 args passed to a/log.Info; must be even"
+	b.Add("id", 1).Add("id", 2) // want "key \"id\" passed to .* was already added on line 8"
+}
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+
+func Info(msg string, kv ...interface{}) {}
+
+type Builder struct{}
+
+func (b *Builder) Add(k string, v interface{}) *Builder { return b }
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a, err := NewAnalyzerFromSettings(map[string]any{
+		"pair-func":      []any{"a/log.Log=0", "a/log.Info=1"},
+		"builder-func":   "a/log.Builder.Add",
+		"duplicate-keys": true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
+
+func TestNewAnalyzerFromSettingsErrors(t *testing.T) {
+	tests := []struct {
+		settings map[string]any
+		err      string
+	}{
+		{map[string]any{"nope": true}, `unknown flag "nope"`},
+		{map[string]any{"pair-func": "a/log.Log"}, `invalid value "a/log.Log" for pair-func`},
+		{map[string]any{"pair-func": map[string]any{"a": 1}}, "pair-func should be a value or a list of values"},
+	}
+	for _, test := range tests {
+		_, err := NewAnalyzerFromSettings(test.settings)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("NewAnalyzerFromSettings(%v) = %v; expected an error containing %q", test.settings, err, test.err)
+		}
+	}
+}
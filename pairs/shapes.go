@@ -0,0 +1,84 @@
+package pairs
+
+import (
+	"go/ast"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/internal/calls"
+	"github.com/ZipRecruiter/splinter/internal/keys"
+)
+
+// funcShapes holds the alternative offsets of pair funcs whose pairs can
+// start at more than one arg.  It is a flag.Value accepting the same form
+// as funcOffset, and may be set more than once for the same func.
+type funcShapes map[funcSelector][]int
+
+func (s funcShapes) Set(v string) error {
+	sel, offset, err := calls.ParseOffset(v)
+	if err != nil {
+		return err
+	}
+
+	fs := newFuncSelector(sel)
+	for _, o := range s[fs] {
+		if o == offset {
+			return nil
+		}
+	}
+	s[fs] = append(s[fs], offset)
+	sort.Ints(s[fs])
+	return nil
+}
+
+func (s funcShapes) String() string {
+	var v []string
+	for sel, offsets := range s {
+		for _, offset := range offsets {
+			v = append(v, sel.String()+"="+strconv.Itoa(offset))
+		}
+	}
+	sort.Strings(v)
+	return strings.Join(v, ",")
+}
+
+// shape returns the offset call, to the func selected by sel, is checked
+// at: the lowest of offset and the alternatives for sel that call fits,
+// either with no pairs or with an even number of args after it, all of
+// whose keys are strings.  If call fits none of them, it's checked at
+// offset.
+func (c *checker) shape(p *analysis.Pass, sel funcSelector, offset int, call *ast.CallExpr) int {
+	alternatives := c.shapes[sel]
+	if len(alternatives) == 0 {
+		return offset
+	}
+
+	candidates := append([]int{offset}, alternatives...)
+	sort.Ints(candidates)
+	for _, o := range candidates {
+		if len(call.Args) <= o {
+			return o
+		}
+		if (len(call.Args)-o)%2 != 0 {
+			continue
+		}
+		if stringKeys(p, call.Args[o:]) {
+			return o
+		}
+	}
+	return offset
+}
+
+// stringKeys returns true if every key among pairs is a string.
+func stringKeys(p *analysis.Pass, pairs []ast.Expr) bool {
+	for i := 0; i < len(pairs); i += 2 {
+		switch kind, _, _ := keys.Classify(p.TypesInfo, pairs[i]); kind {
+		case keys.NonStringConstant, keys.NonStringExpression:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestPairShapes(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import "a/log"
+
+func Foo(err error, id int) {
+	log.Log()
+	log.Log("saved")
+	log.Log("id", id)
+	log.Log("saved", "id", id)
+	log.Log("saved", "id", id, "job", 1)
+	log.Log("saved", "id", id, 1) // want "arg 2 to a/log.Log is expression int but should be a constant string"
+	log.Log(id, "id") // want "arg 0 to a/log.Log is expression int but should be a constant string"
+
+	_ = log.Wrap(err)
+	_ = log.Wrap(err, "saving")
+	_ = log.Wrap(err, "id", id)
+	_ = log.Wrap(err, "saving", "id", id)
+
+	// not given another shape
+	log.Info("saved") // want "1 args passed to a/log.Info; must be even"
+}
+`,
+		"a/log/log.go": `package log
+
+func Log(args ...interface{}) {}
+
+func Info(args ...interface{}) {}
+
+func Wrap(err error, args ...interface{}) error { return err }
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for _, f := range []string{"a/log.Log=0", "a/log.Info=0", "a/log.Wrap=1"} {
+		if err := a.Flags.Set("pair-func", f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range []string{"a/log.Log=1", "a/log.Wrap=2", "a/log.Wrap=2"} {
+		if err := a.Flags.Set("pair-shape", f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, expected := a.Flags.Lookup("pair-shape").Value.String(), "a/log.Log=1,a/log.Wrap=2"; got != expected {
+		t.Errorf("-pair-shape = %q; expected %q", got, expected)
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
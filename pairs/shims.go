@@ -0,0 +1,113 @@
+package pairs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"regexp"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// ShimFunc is an object fact marking a func or interface method declared in
+// a generated file matched by -generated-shim whose variadic ...interface{}
+// param, at Offset, takes pairs.
+type ShimFunc struct {
+	Offset int
+}
+
+// AFact implements analysis.Fact.
+func (*ShimFunc) AFact() {}
+
+func (f *ShimFunc) String() string {
+	return fmt.Sprintf("shim=%d", f.Offset)
+}
+
+var generatedMatcher = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// generatedComment returns the comment marking f as generated, as described
+// by https://go.dev/s/generatedcode, if it has one.
+func generatedComment(f *ast.File) (string, bool) {
+	for _, g := range f.Comments {
+		if g.Pos() > f.Package {
+			break
+		}
+		for _, c := range g.List {
+			if generatedMatcher.MatchString(c.Text) {
+				return c.Text, true
+			}
+		}
+	}
+	return "", false
+}
+
+// exportShims exports a ShimFunc fact for each func, method and interface
+// method declared in a file whose generated comment matches -generated-shim,
+// and whose last param is ...interface{}.
+func (c *checker) exportShims(p *analysis.Pass) {
+	export := func(id *ast.Ident) {
+		fn, ok := p.TypesInfo.Defs[id].(*types.Func)
+		if !ok {
+			return
+		}
+		if offset, ok := pairsParam(fn.Type().(*types.Signature)); ok {
+			p.ExportObjectFact(fn, &ShimFunc{Offset: offset})
+		}
+	}
+
+	for _, f := range p.Files {
+		if comment, ok := generatedComment(f); !ok || !c.shimGenerators.MatchString(comment) {
+			continue
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncDecl:
+				export(n.Name)
+				return false
+			case *ast.InterfaceType:
+				for _, m := range n.Methods.List {
+					for _, id := range m.Names {
+						export(id)
+					}
+				}
+			}
+			return true
+		})
+	}
+}
+
+// pairsParam returns the index of the last param of sig if it's
+// ...interface{}.
+func pairsParam(sig *types.Signature) (int, bool) {
+	if !sig.Variadic() {
+		return 0, false
+	}
+	last := sig.Params().At(sig.Params().Len() - 1)
+	slice, ok := last.Type().(*types.Slice)
+	if !ok {
+		return 0, false
+	}
+	iface, ok := slice.Elem().Underlying().(*types.Interface)
+	if !ok || !iface.Empty() {
+		return 0, false
+	}
+	return sig.Params().Len() - 1, true
+}
+
+// shimOffset returns the offset of the pairs of the shim called by call, if
+// it calls one.
+func (c *checker) shimOffset(p *analysis.Pass, call *ast.CallExpr) (int, bool) {
+	if c.shimGenerators.Regexp == nil {
+		return 0, false
+	}
+	fn, ok := typeutil.Callee(p.TypesInfo, call).(*types.Func)
+	if !ok {
+		return 0, false
+	}
+	var shim ShimFunc
+	if !p.ImportObjectFact(fn.Origin(), &shim) {
+		return 0, false
+	}
+	return shim.Offset, true
+}
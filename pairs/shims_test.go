@@ -0,0 +1,64 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestGeneratedShims(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import (
+	"context"
+
+	"a/logging"
+	"a/other"
+)
+
+func Foo(ctx context.Context, l logging.Logger, id int) {
+	logging.Log(ctx, "saved", "user_id", id)
+	logging.Log(ctx, "saved", "user_id") // want "3 args passed to a/logging.Log; must be even"
+	l.Infow("saved", id, "user_id") // want "arg 1 to method \\(a/logging.Logger\\) Infow\\(msg string, kv ...interface{}\\) is expression int but should be a constant string"
+	logging.Format("saved %s", "user_id")
+	other.Log(ctx, "saved", "user_id")
+}
+`,
+		"a/logging/logging.go": `// Code generated by protoc-gen-go-logging. DO NOT EDIT.
+
+package logging
+
+import "context"
+
+func Log(ctx context.Context, msg string, kv ...interface{}) {} // want Log:"shim=2"
+
+func Format(format string, args ...string) {}
+
+type Logger interface {
+	Infow(msg string, kv ...interface{}) // want Infow:"shim=1"
+}
+`,
+		"a/other/other.go": `// Code generated by stringer. DO NOT EDIT.
+
+package other
+
+import "context"
+
+func Log(ctx context.Context, msg string, kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("generated-shim", "protoc-gen-go-logging"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, dir, a, "a", "a/logging")
+}
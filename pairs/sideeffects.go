@@ -0,0 +1,31 @@
+package pairs
+
+import (
+	"go/ast"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// sideEffectsCorrect reports any call within a, the value at arg i of a
+// call to name, that matches a -side-effect-func selector.
+func (c *checker) sideEffectsCorrect(p *analysis.Pass, name string, i int, a ast.Expr) {
+	ast.Inspect(a, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			// a func literal is not called just by being passed
+			return false
+		case *ast.CallExpr:
+			sels, callee, ok := c.callSelectors(p.TypesInfo, n)
+			if !ok {
+				return true
+			}
+			for _, sel := range sels {
+				if c.sideEffects[sel] {
+					c.report(p, n, SideEffectValue, "arg %d to %s calls %s, which has side effects", i, name, callee)
+					return false
+				}
+			}
+		}
+		return true
+	})
+}
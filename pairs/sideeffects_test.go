@@ -0,0 +1,71 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestSideEffects(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import "a/rows"
+
+type logger int
+
+func (l logger) Log(inputs ...interface{}) { }
+
+type counter int
+
+func (c *counter) Inc() int { *c++; return int(*c) }
+
+func Foo(r *rows.Rows) {
+	l := logger(0)
+	var c counter
+
+	l.Log("closed", r.Close()) // want "arg 1 to method \\(a.logger\\) Log\\(inputs ...interface{}\\) calls method \\(\\*a/rows.Rows\\) Close\\(\\) error, which has side effects"
+	l.Log("count", c.Inc()) // want "arg 1 to method \\(a.logger\\) Log\\(inputs ...interface{}\\) calls method \\(a.counter\\) Inc\\(\\) int, which has side effects"
+	l.Log("err", rows.Close(r)) // want "arg 1 to method \\(a.logger\\) Log\\(inputs ...interface{}\\) calls a/rows.Close, which has side effects"
+	l.Log("nested", []int{c.Inc()}) // want "arg 1 to method \\(a.logger\\) Log\\(inputs ...interface{}\\) calls method \\(a.counter\\) Inc\\(\\) int, which has side effects"
+
+	// keys are not values
+	l.Log(r.Name(), "ok")
+
+	// calls that are not listed are fine
+	l.Log("name", r.Name())
+
+	// func literals are not called by being passed
+	l.Log("close", func() error { return r.Close() })
+}
+`,
+		"a/rows/rows.go": `package rows
+
+type Rows struct{}
+
+func (r *Rows) Close() error { return nil }
+
+func (r *Rows) Name() string { return "" }
+
+func Close(r *Rows) error { return r.Close() }
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("pair-func", ".Log=0"); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{"a/rows.Rows.Close", "a/rows.Close", ".Inc"} {
+		if err := a.Flags.Set("side-effect-func", f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
@@ -0,0 +1,91 @@
+package pairs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/internal/keys"
+)
+
+// CallSite describes one call checked in a package.
+type CallSite struct {
+	File string
+	Line int
+
+	Callee string // the most precise selector of the func called
+	Pairs  int
+
+	// Keys are the keys passed, quoted if they're constants and as
+	// written otherwise.
+	Keys []string
+}
+
+// CallSites is a package fact recording every call to a pair func or builder
+// func checked in the package, so drivers can list them to audit what the
+// configuration covers.
+type CallSites struct {
+	Sites []CallSite // sorted by File then Line
+}
+
+// AFact implements analysis.Fact.
+func (*CallSites) AFact() {}
+
+func (s *CallSites) String() string {
+	var sites []string
+	for _, site := range s.Sites {
+		sites = append(sites, fmt.Sprintf("%d:%s/%d", site.Line, site.Callee, site.Pairs))
+	}
+	return strings.Join(sites, " ")
+}
+
+// callSites collects the calls checked in a package.  A nil *callSites,
+// when -sites is off, collects nothing.
+type callSites struct {
+	sites []CallSite
+}
+
+// add records call, to the func selected by sels, with the given pairs; a
+// trailing key counts as a pair.
+func (s *callSites) add(p *analysis.Pass, sels []funcSelector, call *ast.CallExpr, pairs []ast.Expr) {
+	if s == nil {
+		return
+	}
+
+	posn := p.Fset.Position(call.Pos())
+	site := CallSite{
+		File:   posn.Filename,
+		Line:   posn.Line,
+		Callee: sels[len(sels)-1].String(),
+		Pairs:  (len(pairs) + 1) / 2,
+		Keys:   []string{},
+	}
+	for i := 0; i < len(pairs); i += 2 {
+		if kind, _, k := keys.Classify(p.TypesInfo, pairs[i]); kind == keys.Constant {
+			site.Keys = append(site.Keys, strconv.Quote(k))
+		} else {
+			site.Keys = append(site.Keys, types.ExprString(pairs[i]))
+		}
+	}
+	s.sites = append(s.sites, site)
+}
+
+// export exports the sites as a CallSites fact, if there are any.
+func (s *callSites) export(p *analysis.Pass) {
+	if s == nil || len(s.sites) == 0 {
+		return
+	}
+
+	sort.SliceStable(s.sites, func(i, j int) bool {
+		if s.sites[i].File != s.sites[j].File {
+			return s.sites[i].File < s.sites[j].File
+		}
+		return s.sites[i].Line < s.sites[j].Line
+	})
+	p.ExportPackageFact(&CallSites{Sites: s.sites})
+}
@@ -0,0 +1,71 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestCallSites(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a // want package:"8:a/log.Log/2 9:a/log.Info/0 10:a/log.Builder.Add/1 11:a/log.Log/2"
+
+import "a/log"
+
+func Foo(b *log.Builder, name string, kv []interface{}) {
+	log.Log(kv...)
+
+	log.Log("a", 1, name, 2)
+	log.Info("msg")
+	b.Add("c", 3)
+	log.Log("a", 1, 2) // want "3 args passed to a/log.Log; must be even"
+}
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+
+func Info(msg string, kv ...interface{}) {}
+
+type Builder struct{}
+
+func (b *Builder) Add(k string, v interface{}) *Builder { return b }
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for _, f := range []string{"a/log.Log=0", "a/log.Info=1"} {
+		if err := a.Flags.Set("pair-func", f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for flag, v := range map[string]string{
+		"builder-func": "a/log.Builder.Add",
+		"sites":        "true",
+	} {
+		if err := a.Flags.Set(flag, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results := analysistest.Run(t, dir, a, "a")
+	for _, r := range results {
+		for _, facts := range r.Facts {
+			for _, fact := range facts {
+				sites, ok := fact.(*CallSites)
+				if !ok {
+					continue
+				}
+				if got := sites.Sites[0].Keys; len(got) != 2 || got[0] != `"a"` || got[1] != "name" {
+					t.Errorf("keys of the first site = %q; expected the quoted constant and the expression", got)
+				}
+			}
+		}
+	}
+}
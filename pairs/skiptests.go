@@ -0,0 +1,43 @@
+package pairs
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// skipTestsAll is the value of -skip-tests which skips external test
+// packages too.
+const skipTestsAll = "all"
+
+// skipTestsFlag is the value of -skip-tests: given without a value it skips
+// the _test.go files of a package, and given all, its external test package
+// as well.
+type skipTestsFlag struct {
+	skip, external bool
+}
+
+func (f *skipTestsFlag) Set(v string) error {
+	if v == skipTestsAll {
+		f.skip, f.external = true, true
+		return nil
+	}
+	skip, err := strconv.ParseBool(v)
+	if err != nil {
+		return fmt.Errorf("%q is neither a boolean nor %s", v, skipTestsAll)
+	}
+	f.skip, f.external = skip, false
+	return nil
+}
+
+func (f *skipTestsFlag) String() string {
+	switch {
+	case f == nil || !f.skip:
+		return "false"
+	case f.external:
+		return skipTestsAll
+	}
+	return "true"
+}
+
+// IsBoolFlag lets -skip-tests be given without a value.
+func (f *skipTestsFlag) IsBoolFlag() bool { return true }
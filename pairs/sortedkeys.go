@@ -0,0 +1,93 @@
+package pairs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/internal/keys"
+)
+
+// sortedKeysCorrect reports the first constant key among pairs, the pairs
+// of a call to name starting at arg offset, that sorts before the constant
+// key passed ahead of it.  If every key is a constant and no value can
+// have side effects, whose order would change, a fix sorting the pairs is
+// suggested.
+func (c *checker) sortedKeysCorrect(p *analysis.Pass, name string, offset int, pairs []ast.Expr) {
+	type pair struct {
+		key  string
+		arg  int
+		k, v ast.Expr
+	}
+	var passed []pair // the pairs with constant keys, as passed
+	fixable := true
+	unsorted := -1
+	for i := 0; i+1 < len(pairs); i += 2 {
+		kind, _, key := c.classify(p, pairs[i])
+		if kind != keys.Constant {
+			fixable = false
+			continue
+		}
+		if unsorted < 0 && len(passed) > 0 && key < passed[len(passed)-1].key {
+			unsorted = len(passed)
+		}
+		fixable = fixable && !mayHaveEffects(pairs[i+1])
+		passed = append(passed, pair{key, i + offset, pairs[i], pairs[i+1]})
+	}
+	if unsorted < 0 {
+		return
+	}
+
+	bad := passed[unsorted]
+	d := analysis.Diagnostic{
+		Pos:      bad.k.Pos(),
+		End:      bad.k.End(),
+		Category: UnsortedKeys,
+		Message:  fmt.Sprintf("key %q (arg %d to %s) should come before %q; keys should be sorted", bad.key, bad.arg, name, passed[unsorted-1].key),
+	}
+
+	if fixable && len(pairs)%2 == 0 {
+		sorted := append([]pair{}, passed...)
+		sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].key < sorted[j].key })
+
+		// each pair is replaced by the one sorted into its place, so
+		// the commas and comments between them stay put
+		var edits []analysis.TextEdit
+		for i, to := range passed {
+			var b strings.Builder
+			if format.Node(&b, p.Fset, sorted[i].k) != nil {
+				return
+			}
+			b.WriteString(", ")
+			if format.Node(&b, p.Fset, sorted[i].v) != nil {
+				return
+			}
+			edits = append(edits, analysis.TextEdit{Pos: to.k.Pos(), End: to.v.End(), NewText: []byte(b.String())})
+		}
+		d.SuggestedFixes = []analysis.SuggestedFix{{Message: "Sort the pairs by key", TextEdits: edits}}
+	}
+	p.Report(d)
+}
+
+// mayHaveEffects returns true if evaluating e may have side effects, since
+// it calls a func or receives from a channel.
+func mayHaveEffects(e ast.Expr) bool {
+	effects := false
+	ast.Inspect(e, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			effects = true
+		case *ast.UnaryExpr:
+			effects = effects || n.Op == token.ARROW
+		}
+		return !effects
+	})
+	return effects
+}
@@ -0,0 +1,62 @@
+package pairs
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestSortedKeys(t *testing.T) {
+	src := `package a
+
+import "a/log"
+
+func Foo(key string, id, n int, ch chan int) {
+	log.Info("msg", "id", id, "n", n)
+	log.Info("msg", %s) // want "key \"id\" \\(arg 3 to a/log.Info\\) should come before \"n\"; keys should be sorted"
+	log.Info("msg", %s) // want "key \"b\" \\(arg 5 to a/log.Info\\) should come before \"c\"; keys should be sorted"
+	log.Info("msg", %s) // want "key \"id\" \\(arg 3 to a/log.Info\\) should come before \"n\""
+
+	// not every key is a constant, or a value may have side effects,
+	// so no fix
+	log.Info("msg", "n", n, key, 1, "id", id) // want "key \"id\" \\(arg 5 to a/log.Info\\) should come before \"n\""
+	log.Info("msg", "n", bar(), "id", id) // want "key \"id\" \\(arg 3 to a/log.Info\\) should come before \"n\""
+	log.Info("msg", "n", <-ch, "id", id) // want "key \"id\" \\(arg 3 to a/log.Info\\) should come before \"n\""
+}
+
+func bar() int { return 0 }
+`
+	filemap := map[string]string{
+		"a/a.go": fmt.Sprintf(src,
+			`"n", n, "id", id`,
+			`"a", 1, "c", n /* see */, "b", id+1`,
+			`"n", func() int { return n }, "id", id`, // not called, so fixed
+		),
+		"a/a.go.golden": fmt.Sprintf(src,
+			`"id", id, "n", n`,
+			`"a", 1, "b", id + 1 /* see */, "c", n`,
+			`"id", id, "n", func() int { return n }`,
+		),
+		"a/log/log.go": `package log
+
+func Info(msg string, kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("pair-func", "a/log.Info=1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Flags.Set("sorted-keys", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.RunWithSuggestedFixes(t, dir, a, "a")
+}
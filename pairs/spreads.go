@@ -0,0 +1,42 @@
+package pairs
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// isEmptySpread returns true if e, a slice spread into the pairs, is known
+// to be nil or empty: nil, a composite literal without elements, or a
+// conversion of either, right there or as the value of a local variable
+// that's declared with one (or without a value) and used nowhere else.
+func isEmptySpread(p *analysis.Pass, e ast.Expr) bool {
+	e = ast.Unparen(e)
+	if id, ok := e.(*ast.Ident); ok {
+		switch obj := p.TypesInfo.Uses[id].(type) {
+		case *types.Nil:
+			return true
+		case *types.Var:
+			value, ok := onlyValue(p, obj, id)
+			if !ok {
+				return false
+			}
+			if value == nil {
+				return true // declared without a value
+			}
+			return isEmptySpread(p, value)
+		}
+		return false
+	}
+
+	switch x := e.(type) {
+	case *ast.CompositeLit:
+		return len(x.Elts) == 0
+	case *ast.CallExpr:
+		if tv, ok := p.TypesInfo.Types[x.Fun]; ok && tv.IsType() && len(x.Args) == 1 {
+			return isEmptySpread(p, x.Args[0])
+		}
+	}
+	return false
+}
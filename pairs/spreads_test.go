@@ -0,0 +1,59 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestEmptySpreads(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a // want package:"a/log.Log:6/3/0"
+
+import "a/log"
+
+func Foo(kv []interface{}, id int) {
+	log.Log(nil...)
+	log.Log([]interface{}{}...)
+	log.Log([]interface{}(nil)...)
+
+	var none []interface{}
+	log.Log(none...)
+
+	empty := []interface{}{}
+	log.Log(empty...)
+
+	log.Log(kv...)
+
+	var added []interface{}
+	added = append(added, "id", id)
+	log.Log(added...)
+
+	log.Log([]interface{}{"id", id}...)
+	log.Log("id", id)
+}
+`,
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for flag, v := range map[string]string{
+		"pair-func": "a/log.Log=0",
+		"coverage":  "true",
+	} {
+		if err := a.Flags.Set(flag, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
@@ -0,0 +1,158 @@
+package pairs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/internal/keys"
+)
+
+// StringerKeys is an object fact marking a named integer type whose String
+// method was generated by stringer, with the String value of each of its
+// constants, if they could be resolved.
+type StringerKeys struct {
+	Names map[int64]string // nil if unresolved
+}
+
+// AFact implements analysis.Fact.
+func (*StringerKeys) AFact() {}
+
+func (k *StringerKeys) String() string {
+	var v []int64
+	for i := range k.Names {
+		v = append(v, i)
+	}
+	sort.Slice(v, func(i, j int) bool { return v[i] < v[j] })
+	var s []string
+	for _, i := range v {
+		s = append(s, fmt.Sprintf("%d=%s", i, k.Names[i]))
+	}
+	return "stringer(" + strings.Join(s, ",") + ")"
+}
+
+var stringerMatcher = regexp.MustCompile(`^// Code generated by "stringer\b.*"; DO NOT EDIT\.$`)
+
+// exportStringers exports a StringerKeys fact for each type given a String
+// method by a file stringer generated.
+func exportStringers(p *analysis.Pass) {
+	for _, f := range p.Files {
+		if comment, ok := generatedComment(f); !ok || !stringerMatcher.MatchString(comment) {
+			continue
+		}
+		for _, d := range f.Decls {
+			fn, ok := d.(*ast.FuncDecl)
+			if !ok || fn.Name.Name != "String" || fn.Recv == nil || len(fn.Recv.List) != 1 {
+				continue
+			}
+			named, ok := p.TypesInfo.TypeOf(fn.Recv.List[0].Type).(*types.Named)
+			if !ok {
+				continue
+			}
+			p.ExportObjectFact(named.Obj(), &StringerKeys{Names: stringerNames(p, f, named)})
+		}
+	}
+}
+
+// stringerNames returns the String values of the constants of named, if
+// stringer generated f for a single run of consecutive values: a _T_name
+// constant of the concatenated names, and a _T_index array of their
+// offsets.  Constants in several runs, which stringer splits into more
+// variables or a map, aren't resolved.
+func stringerNames(p *analysis.Pass, f *ast.File, named *types.Named) map[int64]string {
+	prefix := "_" + named.Obj().Name()
+	scope := p.Pkg.Scope()
+
+	nameConst, ok := scope.Lookup(prefix + "_name").(*types.Const)
+	if !ok || nameConst.Val().Kind() != constant.String {
+		return nil
+	}
+	concat := constant.StringVal(nameConst.Val())
+
+	var index []int
+	for _, d := range f.Decls {
+		gen, ok := d.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			if len(vs.Names) != 1 || vs.Names[0].Name != prefix+"_index" || len(vs.Values) != 1 {
+				continue
+			}
+			lit, ok := vs.Values[0].(*ast.CompositeLit)
+			if !ok {
+				return nil
+			}
+			for _, e := range lit.Elts {
+				tv := p.TypesInfo.Types[e]
+				if tv.Value == nil {
+					return nil
+				}
+				i, ok := constant.Int64Val(tv.Value)
+				if !ok || int(i) > len(concat) {
+					return nil
+				}
+				index = append(index, int(i))
+			}
+		}
+	}
+
+	// the run starts at the lowest constant of the type
+	var values []int64
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if !ok || !types.Identical(c.Type(), named) {
+			continue
+		}
+		if v, ok := constant.Int64Val(c.Val()); ok {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 || len(index) < 2 {
+		return nil
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	names := map[int64]string{}
+	for i := 0; i+1 < len(index); i++ {
+		if index[i] > index[i+1] {
+			return nil
+		}
+		names[values[0]+int64(i)] = concat[index[i]:index[i+1]]
+	}
+	return names
+}
+
+// classify is keys.Classify, except that with -stringer-keys, constants of
+// a type whose String method was generated by stringer are Constant keys
+// whose value is their String value, or Expression keys if it couldn't be
+// resolved.
+func (c *checker) classify(p *analysis.Pass, e ast.Expr) (kind keys.Kind, typ types.Type, value string) {
+	kind, typ, value = keys.Classify(p.TypesInfo, e)
+	if !c.stringerKeys || kind != keys.NonStringConstant {
+		return kind, typ, value
+	}
+
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return kind, typ, value
+	}
+	var fact StringerKeys
+	if !p.ImportObjectFact(named.Obj(), &fact) {
+		return kind, typ, value
+	}
+	if v, ok := constant.Int64Val(p.TypesInfo.Types[e].Value); ok {
+		if name, ok := fact.Names[v]; ok {
+			return keys.Constant, typ, name
+		}
+	}
+	return keys.Expression, typ, ""
+}
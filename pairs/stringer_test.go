@@ -0,0 +1,103 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestStringerKeys(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+import (
+	"a/field"
+	"a/log"
+)
+
+func Foo(l log.Logger, id int) {
+	l.Log(field.UserID, id)
+	l.Log(field.RequestID, id) // want "key \"RequestID\" \\(arg 0 to method \\(a/log.Logger\\) Log\\(kv ...interface{}\\)\\) is not in the keys vocabulary"
+	l.Log(field.Debug, id) // unresolved, but a string
+	l.Log(field.Other(1), id) // want "arg 0 to method \\(a/log.Logger\\) Log\\(kv ...interface{}\\) is constant a/field.Other but should be a constant string"
+
+	b := log.Builder{}
+	b.Add(field.UserID, id).Add(field.UserID, id) // want "key \"UserID\" passed to method \\(a/log.Builder\\) Add\\(key interface{}, value interface{}\\) a/log.Builder was already added on line 15"
+}
+`,
+		"a/field/field.go": `package field
+
+type Field int // want Field:"stringer\\(1=UserID,2=RequestID\\)"
+
+const (
+	UserID Field = iota + 1
+	RequestID
+)
+
+type Level int // want Level:"stringer\\(\\)"
+
+const Debug Level = 10
+
+type Other int
+`,
+		"a/field/field_string.go": `// Code generated by "stringer -type=Field"; DO NOT EDIT.
+
+package field
+
+import "strconv"
+
+const _Field_name = "UserIDRequestID"
+
+var _Field_index = [...]uint8{0, 6, 15}
+
+func (i Field) String() string {
+	i -= 1
+	if i < 0 || i >= Field(len(_Field_index)-1) {
+		return "Field(" + strconv.FormatInt(int64(i+1), 10) + ")"
+	}
+	return _Field_name[_Field_index[i]:_Field_index[i+1]]
+}
+`,
+		"a/field/level_string.go": `// Code generated by "stringer -type=Level"; DO NOT EDIT.
+
+package field
+
+var _Level_map = map[Level]string{10: "Debug"}
+
+func (i Level) String() string {
+	return _Level_map[i]
+}
+`,
+		"a/log/log.go": `package log
+
+type Logger interface {
+	Log(kv ...interface{})
+}
+
+type Builder struct{}
+
+func (b Builder) Add(key, value interface{}) Builder { return b }
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for flag, v := range map[string]string{
+		"pair-func":      ".Log=0",
+		"builder-func":   "a/log.Builder.Add",
+		"duplicate-keys": "true",
+		"stringer-keys":  "true",
+		"key":            "UserID",
+	} {
+		if err := a.Flags.Set(flag, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysistest.Run(t, dir, a, "a", "a/field")
+}
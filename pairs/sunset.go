@@ -0,0 +1,82 @@
+package pairs
+
+import (
+	"fmt"
+	"go/ast"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/internal/config"
+)
+
+// sunsetLayout is the layout of the dates of -key-sunset.
+const sunsetLayout = "2006-01-02"
+
+// keySunsets maps keys to the dates they're sunset on, from which their uses
+// are reported, for a planned removal of keys from the log schema.  It is a
+// flag.Value accepting <key>=<YYYY-MM-DD>.
+type keySunsets map[string]time.Time
+
+func (k keySunsets) Set(v string) error {
+	if v, ok := config.Removal(v); ok {
+		if i := strings.IndexByte(v, '='); i >= 0 {
+			v = v[:i]
+		}
+		delete(k, v)
+		return nil
+	}
+
+	key, date, ok := strings.Cut(v, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("invalid key sunset %q; should be of form <key>=<YYYY-MM-DD>", v)
+	}
+	t, err := time.Parse(sunsetLayout, date)
+	if err != nil {
+		return fmt.Errorf("invalid key sunset %q; %q isn't a date of form YYYY-MM-DD", v, date)
+	}
+	k[key] = t
+	return nil
+}
+
+func (k keySunsets) String() string {
+	var s []string
+	for key, date := range k {
+		s = append(s, key+"="+date.Format(sunsetLayout))
+	}
+	sort.Strings(s)
+	return strings.Join(s, ",")
+}
+
+// effective returns the date the diagnostics of the sunsets depend on, so
+// that Fingerprint changes with it.
+func (k keySunsets) effective(now time.Time) string {
+	if len(k) == 0 {
+		return ""
+	}
+	return now.Format(sunsetLayout)
+}
+
+// sunsetCorrect reports key, the constant at arg i of a call to name, if
+// it's been sunset: as a SunsetKey warning during the -sunset-grace-days
+// after its sunset date, and as an ExpiredKey error from then on.
+func (c *checker) sunsetCorrect(p *analysis.Pass, name string, i int, a ast.Expr, key string) {
+	sunset, ok := c.sunsets[key]
+	if !ok {
+		return
+	}
+	now := time.Now()
+	if now.Before(sunset) {
+		return
+	}
+	expiry := sunset.AddDate(0, 0, c.sunsetGrace)
+	if now.Before(expiry) {
+		c.report(p, a, SunsetKey, "key %q (arg %d to %s) was sunset on %s; its uses are errors from %s",
+			key, i, name, sunset.Format(sunsetLayout), expiry.Format(sunsetLayout))
+		return
+	}
+	c.report(p, a, ExpiredKey, "key %q (arg %d to %s) was sunset on %s and its grace period ended on %s; remove it",
+		key, i, name, sunset.Format(sunsetLayout), expiry.Format(sunsetLayout))
+}
@@ -0,0 +1,77 @@
+package pairs
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestKeySunsets(t *testing.T) {
+	date := func(days int) string {
+		return time.Now().AddDate(0, 0, days).Format(sunsetLayout)
+	}
+	// sunset ten days ago, so the grace period of 30 days runs for 20 more
+	sunset, expiry := date(-10), date(20)
+	// sunset 40 days ago, so the grace period ended 10 days ago
+	expired, ended := date(-40), date(-10)
+
+	filemap := map[string]string{
+		"a/a.go": fmt.Sprintf(`package a
+
+import "a/log"
+
+const userName = "user_name"
+
+func Foo(id int, name string) {
+	log.Log("id", id)
+	log.Log("session", id) // want "key \"session\" \\(arg 0 to a/log.Log\\) is not in the keys vocabulary"
+	log.Log("future", id)
+	log.Log(userName, name) // want "key \"user_name\" \\(arg 0 to a/log.Log\\) was sunset on %[1]s; its uses are errors from %[2]s"
+	log.Log("old_id", id) // want "key \"old_id\" \\(arg 0 to a/log.Log\\) was sunset on %[3]s and its grace period ended on %[4]s; remove it"
+}
+`, sunset, expiry, expired, ended),
+		"a/log/log.go": `package log
+
+func Log(kv ...interface{}) {}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for _, f := range [][2]string{
+		{"pair-func", "a/log.Log=0"},
+		{"key", "id"},
+		{"key-sunset", "future=" + date(10)},
+		{"key-sunset", "user_name=" + sunset},
+		{"key-sunset", "old_id=" + expired},
+	} {
+		if err := a.Flags.Set(f[0], f[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	analysistest.Run(t, dir, a, "a")
+}
+
+func TestKeySunsetsFlag(t *testing.T) {
+	k := keySunsets{}
+	for _, v := range []string{"user_name", "=2026-01-01", "user_name=01/02/2026"} {
+		if err := k.Set(v); err == nil {
+			t.Errorf("%q is accepted", v)
+		}
+	}
+	for _, v := range []string{"user_name=2026-01-01", "old_id=2025-12-31", "!old_id"} {
+		if err := k.Set(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s := k.String(); s != "user_name=2026-01-01" {
+		t.Errorf("unexpected value %q", s)
+	}
+}
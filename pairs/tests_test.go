@@ -0,0 +1,131 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestUnexportedTestHelpers(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+func logPairs(kv ...interface{}) {}
+
+type logger struct{}
+
+func (logger) log(kv ...interface{}) {}
+
+func Foo() {
+	logPairs("id") // want "1 args passed to a.logPairs; must be even"
+	logger{}.log("id") // want "1 args passed to method \\(a.logger\\) log\\(kv ...interface{}\\); must be even"
+}
+`,
+		"a/a_test.go": `package a
+
+import "testing"
+
+func TestFoo(t *testing.T) {
+	logPairs("id") // want "1 args passed to a.logPairs; must be even"
+	logger{}.log("id") // want "1 args passed to method \\(a.logger\\) log\\(kv ...interface{}\\); must be even"
+}
+`,
+		"a/x_test.go": `package a_test
+
+import "testing"
+
+// test helpers declared in the external test package match selectors for
+// the package under test
+func logPairs(kv ...interface{}) {}
+
+type logger struct{}
+
+func (logger) log(kv ...interface{}) {}
+
+func TestBar(t *testing.T) {
+	logPairs("id") // want "1 args passed to a_test.logPairs; must be even"
+	logger{}.log("id") // want "1 args passed to method \\(a_test.logger\\) log\\(kv ...interface{}\\); must be even"
+}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for _, f := range []string{"a.logPairs=0", "a.logger.log=0"} {
+		if err := a.Flags.Set("pair-func", f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
+
+func TestExternalTestPackages(t *testing.T) {
+	filemap := map[string]string{
+		"a/a.go": `package a
+
+type Pairs struct{}
+`,
+		"a/export_test.go": `package a
+
+// TestLogger only exists in the test variant of a, which a_test imports
+type TestLogger struct{}
+
+func (TestLogger) Log(kv ...interface{}) {}
+`,
+		"a/x_test.go": `package a_test // want package:"20:a.TestLogger.Log/1 21:a.TestLogger.Log/1 22:a.TestLogger.Log/1 23:a.TestLogger.Log/1"
+
+import (
+	"testing"
+
+	"a"
+)
+
+// testPairs is declared in the external test package, but selected as
+// a.testPairs like the test helpers
+type testPairs struct{}
+
+// newLogger is a test-only factory of pair funcs
+func newLogger() func(...interface{}) {
+	return a.TestLogger{}.Log
+}
+
+func TestFoo(t *testing.T) {
+	var l a.TestLogger
+	l.Log("id", 1)
+	l.Log(testPairs{})
+	l.Log(a.Pairs{})
+	l.Log("id") // want "1 args passed to method \\(a.TestLogger\\) Log\\(kv ...interface{}\\); must be even"
+	newLogger()("id") // want "1 args passed to the func returned by a_test.newLogger; must be even"
+}
+`,
+	}
+
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	for _, f := range []string{"a.testPairs", "a.Pairs"} {
+		if err := a.Flags.Set("assume-pair", f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for flag, v := range map[string]string{
+		"pair-func": "a.TestLogger.Log=0",
+		"sites":     "true",
+	} {
+		if err := a.Flags.Set(flag, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysistest.Run(t, dir, a, "a")
+}
@@ -0,0 +1,101 @@
+package pairs
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// typedNilCorrect reports a, the value at arg i of a call to name, if it's
+// a pointer known to be nil: a conversion of nil, like (*T)(nil), or a local
+// variable declared without a value and never assigned.  Passed as an
+// interface{}, such a value isn't a nil interface, and renders as <nil>
+// while comparing unequal to nil.
+func (c *checker) typedNilCorrect(p *analysis.Pass, name string, i int, a ast.Expr) {
+	a = ast.Unparen(a)
+	ptr, ok := p.TypesInfo.TypeOf(a).(*types.Pointer)
+	if !ok {
+		return
+	}
+
+	switch x := a.(type) {
+	case *ast.CallExpr:
+		if tv := p.TypesInfo.Types[x.Fun]; !tv.IsType() || len(x.Args) != 1 || !isNil(p.TypesInfo, ast.Unparen(x.Args[0])) {
+			return
+		}
+	case *ast.Ident:
+		v, ok := p.TypesInfo.Uses[x].(*types.Var)
+		if !ok || !neverAssigned(p, v) {
+			return
+		}
+	default:
+		return
+	}
+
+	c.report(p, a, TypedNilValue, "arg %d to %s is a nil %s, which isn't a nil interface{}",
+		i,
+		name,
+		types.TypeString(ptr, types.RelativeTo(p.Pkg)),
+	)
+}
+
+// neverAssigned returns true if v is a local variable declared with var
+// and no value, and its func never assigns it or takes its address.
+func neverAssigned(p *analysis.Pass, v *types.Var) bool {
+	if v.Pkg() != p.Pkg || v.Parent() == nil || v.Parent() == p.Pkg.Scope() {
+		return false
+	}
+
+	var file *ast.File
+	for _, f := range p.Files {
+		if f.Pos() <= v.Pos() && v.Pos() < f.End() {
+			file = f
+		}
+	}
+	if file == nil {
+		return false
+	}
+
+	path, _ := astutil.PathEnclosingInterval(file, v.Pos(), v.Pos())
+	var spec *ast.ValueSpec
+	var body ast.Node
+	for _, n := range path {
+		switch n := n.(type) {
+		case *ast.ValueSpec:
+			spec = n
+		case *ast.FuncDecl:
+			body = n.Body
+		case *ast.FuncLit:
+			body = n.Body
+		}
+		if body != nil {
+			break
+		}
+	}
+	if spec == nil || len(spec.Values) != 0 || body == nil {
+		return false
+	}
+
+	is := func(e ast.Expr) bool {
+		id, ok := ast.Unparen(e).(*ast.Ident)
+		return ok && p.TypesInfo.ObjectOf(id) == v
+	}
+	assigned := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				assigned = assigned || is(lhs)
+			}
+		case *ast.RangeStmt:
+			assigned = assigned || (n.Key != nil && is(n.Key)) || (n.Value != nil && is(n.Value))
+		case *ast.UnaryExpr:
+			assigned = assigned || (n.Op == token.AND && is(n.X))
+		}
+		return !assigned
+	})
+	return !assigned
+}
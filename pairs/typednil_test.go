@@ -0,0 +1,66 @@
+package pairs
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestTypedNilValues(t *testing.T) {
+	src := `package a
+
+func Log(kv ...interface{}) {}
+
+type MyError struct{}
+
+func (*MyError) Error() string { return "" }
+
+func find() *MyError { return nil }
+
+var global *MyError
+
+func Foo(param *MyError, ok bool) {
+	var cause *MyError
+	Log("cause", cause) // want "arg 1 to a.Log is a nil \\*MyError, which isn't a nil interface{}"
+	Log("cause", (*MyError)(nil)) // want "arg 1 to a.Log is a nil \\*MyError, which isn't a nil interface{}"
+	Log("cause", nil)
+	Log("cause", param)
+	Log("cause", global)
+	Log("cause", find())
+
+	var assigned *MyError
+	if ok {
+		assigned = find()
+	}
+	Log("cause", assigned)
+
+	var addressed *MyError
+	set(&addressed)
+	Log("cause", addressed)
+
+	var count int
+	Log("count", count)
+
+	func() {
+		var inner *MyError
+		Log("cause", inner) // want "arg 1 to a.Log is a nil \\*MyError, which isn't a nil interface{}"
+	}()
+}
+
+func set(e **MyError) {}
+`
+	dir, cleanup, err := analysistest.WriteFiles(map[string]string{"a/a.go": src})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	a := NewAnalyzer()
+	if err := a.Flags.Set("pair-func", "a.Log=0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Flags.Set("typed-nil-values", "true"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, dir, a, "a")
+}
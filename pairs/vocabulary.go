@@ -0,0 +1,52 @@
+package pairs
+
+import (
+	"go/ast"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/internal/config"
+)
+
+// vocabulary is the set of known keys.  It is a flag.Value accepting a
+// key, or a comma separated list of them; with a leading !, they're removed.
+type vocabulary map[string]bool
+
+func (v vocabulary) Set(s string) error {
+	s, remove := config.Removal(s)
+	for _, key := range strings.Split(s, ",") {
+		if key = strings.TrimSpace(key); key == "" {
+			continue
+		}
+		if remove {
+			delete(v, key)
+		} else {
+			v[key] = true
+		}
+	}
+	return nil
+}
+
+func (v vocabulary) String() string {
+	var s []string
+	for key := range v {
+		s = append(s, key)
+	}
+	sort.Strings(s)
+	return strings.Join(s, ",")
+}
+
+// vocabularyCorrect reports key, the constant at arg i of a call to name, if
+// there's a vocabulary and key isn't in it, nor among the debug-only or
+// sunset keys, which are reported by their own rules.
+func (c *checker) vocabularyCorrect(p *analysis.Pass, name string, i int, a ast.Expr, key string) {
+	if len(c.vocabulary) == 0 || c.vocabulary[key] || c.debugKeys[key] {
+		return
+	}
+	if _, ok := c.sunsets[key]; ok {
+		return
+	}
+	c.report(p, a, UnknownKey, "key %q (arg %d to %s) is not in the keys vocabulary", key, i, name)
+}
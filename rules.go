@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ZipRecruiter/splinter/events"
+	"github.com/ZipRecruiter/splinter/internal/driver"
+	"github.com/ZipRecruiter/splinter/internal/ruledoc"
+	"github.com/ZipRecruiter/splinter/pairs"
+)
+
+// ruleDoc is the JSON form of a rule, as written by `splinter rules -json`.
+type ruleDoc struct {
+	ID          string   `json:"id"`
+	Code        string   `json:"code"`
+	Analyzer    string   `json:"analyzer"`
+	Severity    string   `json:"severity"`
+	Description string   `json:"description"`
+	Rationale   string   `json:"rationale,omitempty"`
+	Flags       []string `json:"flags"`
+	OptIn       bool     `json:"opt_in"`
+	Fixable     bool     `json:"fixable"`
+	URL         string   `json:"url"`
+}
+
+// ruleDocs returns the docs of every rule, in the order of rules.
+func ruleDocs() []ruleDoc {
+	var docs []ruleDoc
+	for _, id := range pairs.Rules {
+		d := pairs.RuleDocs[id]
+		severity := driver.Error
+		if d.Warning {
+			// unless a Policy escalates it
+			severity = driver.Warning
+		}
+		docs = append(docs, ruleDoc{ID: id, Code: d.Code, Analyzer: "pairs", Severity: severity.String(), Description: d.Description, Rationale: d.Rationale, Flags: d.Flags, OptIn: d.OptIn, Fixable: d.Fixable, URL: ruledoc.Rule(id)})
+	}
+	for _, id := range events.Rules {
+		d := events.RuleDocs[id]
+		docs = append(docs, ruleDoc{ID: id, Code: d.Code, Analyzer: "events", Severity: driver.Error.String(), Description: d.Description, Flags: d.Flags, OptIn: d.OptIn, Fixable: d.Fixable, URL: ruledoc.Rule(id)})
+	}
+	return docs
+}
+
+// rulesCmd implements `splinter rules`, which lists every rule with its code,
+// what it reports, the flags enabling or configuring it, whether it's opt-in
+// and whether its diagnostics suggest fixes, as JSON for tools with -json,
+// or as the Markdown of the page their diagnostics link to with -markdown.
+func rulesCmd(args []string) int {
+	fset := flag.NewFlagSet("rules", flag.ExitOnError)
+	fset.Usage = func() {
+		fmt.Fprintf(fset.Output(), "usage: splinter rules [-json | -markdown]\n\n")
+		fset.PrintDefaults()
+	}
+	asJSON := fset.Bool("json", false, "write the rules as a JSON array of objects with id, code, analyzer, severity, description, flags, opt_in, fixable and url fields")
+	markdown := fset.Bool("markdown", false, "write the rules as the Markdown of docs/rules.md, which diagnostics link to")
+	fset.Parse(args)
+
+	docs := ruleDocs()
+	if *markdown {
+		writeRulesMarkdown(os.Stdout, docs)
+		return 0
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "\t")
+		if err := enc.Encode(docs); err != nil {
+			fmt.Fprintf(os.Stderr, "splinter rules: %s\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	for _, d := range docs {
+		var notes []string
+		if d.OptIn {
+			notes = append(notes, "opt-in")
+		}
+		if d.Fixable {
+			notes = append(notes, "fixable")
+		}
+		desc := d.Description
+		if len(d.Flags) != 0 {
+			desc += " (-" + strings.Join(d.Flags, ", -") + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", d.Code, d.ID, strings.Join(notes, ", "), desc)
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "splinter rules: %s\n", err)
+		return 1
+	}
+	return 0
+}
+
+// writeRulesMarkdown writes docs as a Markdown page with a section for each
+// rule, headed by its name so that its anchor is the one ruledoc.Rule links
+// to.
+func writeRulesMarkdown(w io.Writer, docs []ruleDoc) {
+	fmt.Fprintf(w, "# Rules\n\n")
+	fmt.Fprintf(w, "The rules checked by splinter, which the URL of each diagnostic links to.\n")
+	fmt.Fprintf(w, "This page is written by `splinter rules -markdown`.\n")
+	for _, d := range docs {
+		fmt.Fprintf(w, "\n## %s\n\n", d.ID)
+
+		notes := []string{"`" + d.Code + "`", d.Analyzer, d.Severity}
+		if d.OptIn {
+			notes = append(notes, "opt-in")
+		}
+		if d.Fixable {
+			notes = append(notes, "fixable")
+		}
+		fmt.Fprintf(w, "%s\n\n", strings.Join(notes, " · "))
+
+		fmt.Fprintf(w, "Reported when %s.\n", d.Description)
+		if d.Rationale != "" {
+			fmt.Fprintf(w, "\n%s\n", d.Rationale)
+		}
+		if len(d.Flags) != 0 {
+			fmt.Fprintf(w, "\nEnabled or configured by `-%s`.\n", strings.Join(d.Flags, "`, `-"))
+		}
+	}
+}
@@ -0,0 +1,455 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/analysis/unitchecker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/ZipRecruiter/splinter/internal/config"
+	"github.com/ZipRecruiter/splinter/internal/driver"
+	"github.com/ZipRecruiter/splinter/pairs"
+)
+
+// analyzerFlags registers the flags of every analyzer on fset, along with
+// -config, which sets flags from a configuration file.  Unlike
+// multichecker, flags aren't prefixed by the analyzer name, so the names
+// must be unique across analyzers.
+func analyzerFlags(fset *flag.FlagSet, analyzers []*analysis.Analyzer) {
+	for _, a := range analyzers {
+		a.Flags.VisitAll(func(f *flag.Flag) {
+			if f.Name == "config" {
+				return // the binary's own -config sets the flags of every analyzer
+			}
+			if fset.Lookup(f.Name) != nil {
+				panic(fmt.Sprintf("%s flag -%s conflicts with another flag", a.Name, f.Name))
+			}
+			fset.Var(f.Value, f.Name, f.Usage)
+		})
+	}
+	fset.Var(&config.Flag{FlagSet: fset}, "config", "set flags from this YAML (or, ending in .toml, TOML) file, as written by splinter init; presets apply first, then -config files in order, then the other flags, wherever they appear")
+}
+
+// policyFlags registers the flags configuring the policy applied to the
+// diagnostics on fset.
+func policyFlags(fset *flag.FlagSet) *driver.Policy {
+	policy := &driver.Policy{Escalate: driver.RuleSet{}, Warnings: warningRules(), Severities: driver.Severities{}, MaxExit: driver.Warning, Codes: ruleCodes()}
+	fset.Var(&policy.Tiers, "tier", "assign packages matching a pattern to a maturity tier, as pattern=experimental or pattern=stable")
+	fset.Var(policy.Severities, "severity", "set the severity of rules (or their codes), as rule[,rule]=error, warning or info, overriding -escalate and their defaults")
+	fset.Var(&policy.MaxExit, "max-severity-exit", "the most severe diagnostics can be without failing the run: error, warning or info")
+	fset.Var(policy.Escalate, "escalate", "comma separated rules (or their codes) that are only errors in stable packages, and warnings elsewhere")
+	fset.Var(&policy.Suppress, "suppress", "silence the diagnostics at <file>:<line> or within [pkg[.type]].<func>, optionally only of some rules, as scope=rule[,rule]")
+	fset.Var(&policy.Disable, "disable", "turn off rules, as rule[,rule], or only in the files matching a glob relative to the working directory, as glob=rule[,rule]")
+	fset.Var(&policy.Paths.Include, "include", "comma separated globs of the file paths, relative to the working directory, to report diagnostics in, where ** matches any number of directories; all by default")
+	fset.Var(&policy.Paths.Exclude, "exclude", "comma separated globs of the file paths, relative to the working directory, not to report diagnostics in, like 'vendor/**,**/testdata/**'")
+	return policy
+}
+
+// policyFlagNames returns the names of the flags policyFlags defines.
+func policyFlagNames() map[string]bool {
+	fset := flag.NewFlagSet("policy", flag.ContinueOnError)
+	policyFlags(fset)
+	names := map[string]bool{}
+	fset.VisitAll(func(f *flag.Flag) { names[f.Name] = true })
+	return names
+}
+
+// run implements the default mode of splinter, which analyzes packages the
+// way singlechecker would, including when run by go vet -vettool.
+func run(analyzers []*analysis.Analyzer, args []string) int {
+	fset := flag.NewFlagSet("splinter", flag.ExitOnError)
+	fset.Usage = func() {
+		fmt.Fprintf(fset.Output(), "splinter checks key/value pairs and related conventions.\n\n")
+		fmt.Fprintf(fset.Output(), "Usage: splinter [-flag] [package]\n")
+		fmt.Fprintf(fset.Output(), "       splinter fix [-flag] [package]\n")
+		fmt.Fprintf(fset.Output(), "       splinter containers [-flag] [package]\n")
+		fmt.Fprintf(fset.Output(), "       splinter init [-o file] [package]\n")
+		fmt.Fprintf(fset.Output(), "       splinter annotate [-o dir] [-flag] [package]\n")
+		fmt.Fprintf(fset.Output(), "       splinter check-config file\n")
+		fmt.Fprintf(fset.Output(), "       splinter migrate-config [-to version] [-keep-defaults] file\n")
+		fmt.Fprintf(fset.Output(), "       splinter import-golangci [-o file] [-force] .golangci.yml\n")
+		fmt.Fprintf(fset.Output(), "       splinter keys [-flag] [package]\n")
+		fmt.Fprintf(fset.Output(), "       splinter keys diff old.json new.json\n")
+		fmt.Fprintf(fset.Output(), "       splinter merge-reports [-o file] report.json...\n")
+		fmt.Fprintf(fset.Output(), "       splinter sites [-flag] [package]\n")
+		fmt.Fprintf(fset.Output(), "       splinter doctor [-flag] [package]\n")
+		fmt.Fprintf(fset.Output(), "       splinter rules [-json]\n\n")
+		fmt.Fprintf(fset.Output(), "Flags:\n")
+		fset.PrintDefaults()
+	}
+	analyzerFlags(fset, analyzers)
+
+	printFlags := fset.Bool("flags", false, "print analyzer flags in JSON")
+	version := &versionFlag{}
+	fset.Var(version, "V", "print version, along with the fingerprint of the configuration, and exit")
+	jsonOut := fset.Bool("json", false, "emit JSON output, in the shape of go vet -json, of the diagnostics the policy flags keep")
+	jsonlOut := fset.Bool("jsonl", false, "stream diagnostics as lines of JSON on stdout as they're reported")
+	contextLines := fset.Int("c", -1, "display offending line with this many lines of context")
+	tests := fset.Bool("test", true, "indicates whether test files should be analyzed, too")
+	policy := policyFlags(fset)
+	workspace := fset.Bool("workspace", false, "analyze every module of the enclosing go.work workspace in one run; relative patterns (default ./...) apply within each module")
+	includeIgnored := fset.Bool("include-ignored", false, "analyze patterns within testdata directories and files excluded by build constraints, like //go:build ignore, which are skipped otherwise")
+	applyFixes := fset.Bool("fix", false, "apply all suggested fixes")
+	diff := fset.Bool("diff", false, "with -fix, don't update the files, but print a unified diff")
+	watchMode := fset.Bool("watch", false, "keep running, re-analyzing packages as their files change")
+	cacheDir := fset.String("cache", "", "cache diagnostics in this directory, so later runs only analyze packages that changed")
+	stats := fset.Bool("stats", false, "after the diagnostics, print how many calls each configured selector checked and skipped, and the diagnostics they produced")
+	baselineFile := fset.String("baseline", "", "drop the diagnostics recorded in this file by -baseline-write, reporting only new ones")
+	baselineWrite := fset.String("baseline-write", "", "record the diagnostics in this file, for -baseline, instead of reporting them")
+	collapseCopies := fset.Bool("collapse-copies", false, "report the diagnostics at the same place in files with identical content, like vendored or generated copies, once, naming the other copies")
+	diffBase := fset.String("diff-base", "", "only report diagnostics on lines changed since the merge base with this git ref, like origin/main")
+	shard := &driver.Shard{}
+	fset.Var(shard, "shard", "only analyze shard i of N disjoint shards of the packages, as i/N, for spreading a large repository over several runs whose -json reports splinter merge-reports combines")
+	sinks := &driver.Sinks{}
+	fset.Var(sinks, "sink", "also send the diagnostics as JSON to file:<path> or POST them to an http:// or https:// URL")
+	fset.Func("sink-template", "render what's sent to -sink with the text/template in this file, given .Findings, .Errors, .Warnings and .Infos", sinks.SetTemplate)
+	config.Parse(fset, args)
+
+	// -V=full: identify the binary and configuration, so that go vet
+	// caches results by both.
+	if version.full {
+		return printVersion(analyzers)
+	}
+
+	// -flags: print flags so that go vet knows which ones are legitimate.
+	if *printFlags {
+		return printFlagsJSON(fset)
+	}
+
+	args = fset.Args()
+	if len(args) == 0 && !*workspace {
+		fset.Usage()
+		return 1
+	}
+
+	if err := validRules(policy.Escalate); err != nil {
+		fmt.Fprintf(os.Stderr, "splinter: -escalate: %s\n", err)
+		return 2
+	}
+	if err := validRules(policy.Severities.Rules()); err != nil {
+		fmt.Fprintf(os.Stderr, "splinter: -severity: %s\n", err)
+		return 2
+	}
+	if err := validRules(policy.Suppress.Rules()); err != nil {
+		fmt.Fprintf(os.Stderr, "splinter: -suppress: %s\n", err)
+		return 2
+	}
+	if err := validRules(policy.Disable.Rules()); err != nil {
+		fmt.Fprintf(os.Stderr, "splinter: -disable: %s\n", err)
+		return 2
+	}
+	policy.ResolveCodes()
+	for _, s := range policy.Suppress.Expired() {
+		fmt.Fprintf(os.Stderr, "splinter: suppression %s no longer applies\n", s)
+	}
+
+	if len(args) == 1 && strings.HasSuffix(args[0], ".cfg") {
+		// unitchecker reports every diagnostic of the analyzers itself
+		var set []string
+		policyNames := policyFlagNames()
+		fset.Visit(func(f *flag.Flag) {
+			if policyNames[f.Name] {
+				set = append(set, "-"+f.Name)
+			}
+		})
+		if len(set) != 0 {
+			fmt.Fprintf(os.Stderr, "splinter: %s can't be applied under go vet; run splinter itself instead\n", strings.Join(set, ", "))
+			return 2
+		}
+		unitchecker.Run(args[0], analyzers)
+		panic("unreachable")
+	}
+
+	if *stats {
+		if *watchMode || *jsonlOut || *cacheDir != "" || *applyFixes {
+			fmt.Fprintf(os.Stderr, "splinter: -stats can't be combined with -watch, -jsonl, -cache or -fix\n")
+			return 2
+		}
+		fset.Set("coverage", "true")
+	}
+
+	if *baselineWrite != "" && (*baselineFile != "" || *diffBase != "" || *watchMode || *jsonOut || *jsonlOut || *applyFixes) {
+		fmt.Fprintf(os.Stderr, "splinter: -baseline-write can't be combined with -baseline, -diff-base, -watch, -json, -jsonl or -fix\n")
+		return 2
+	}
+	if *diffBase != "" {
+		if *watchMode {
+			fmt.Fprintf(os.Stderr, "splinter: -diff-base can't be combined with -watch\n")
+			return 2
+		}
+		var err error
+		if policy.Changed, err = driver.DiffBase(".", *diffBase); err != nil {
+			fmt.Fprintf(os.Stderr, "splinter: -diff-base: %s\n", err)
+			return 2
+		}
+	}
+	if *collapseCopies && (*watchMode || *jsonlOut || *applyFixes || *baselineWrite != "") {
+		fmt.Fprintf(os.Stderr, "splinter: -collapse-copies can't be combined with -watch, -jsonl, -fix or -baseline-write\n")
+		return 2
+	}
+	if sinks.Len() != 0 && (*watchMode || *jsonlOut || *applyFixes) {
+		fmt.Fprintf(os.Stderr, "splinter: -sink can't be combined with -watch, -jsonl or -fix\n")
+		return 2
+	}
+	if *baselineFile != "" {
+		wd, err := os.Getwd()
+		if err == nil {
+			policy.Baseline, err = driver.ReadBaseline(*baselineFile, wd)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "splinter: -baseline: %s\n", err)
+			return 2
+		}
+	}
+
+	cfg := driver.LoadConfig{Tests: *tests, Workspace: *workspace, IncludeIgnored: *includeIgnored}
+	if *watchMode {
+		if *jsonOut || *jsonlOut || *applyFixes {
+			fmt.Fprintf(os.Stderr, "splinter: -watch can't be combined with -json, -jsonl or -fix\n")
+			return 2
+		}
+		return watch(analyzers, cfg, policy, *contextLines, args)
+	}
+	if *jsonlOut && (*jsonOut || *applyFixes) {
+		fmt.Fprintf(os.Stderr, "splinter: -jsonl can't be combined with -json or -fix\n")
+		return 2
+	}
+	if *cacheDir != "" && *jsonlOut {
+		fmt.Fprintf(os.Stderr, "splinter: -cache can't be combined with -jsonl\n")
+		return 2
+	}
+
+	pkgs, err := driver.Load(cfg, args...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter: %s\n", err)
+		return 1
+	}
+	if pkgs = policy.Paths.Packages(pkgs); len(pkgs) == 0 {
+		fmt.Fprintf(os.Stderr, "splinter: no packages have files matched by -include and not -exclude\n")
+		return 1
+	}
+	pkgs = shard.Packages(pkgs)
+
+	if *jsonlOut {
+		errs, err := driver.Stream(os.Stdout, analyzers, pkgs, policy)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "splinter: %s\n", err)
+			return 1
+		}
+		if errs != 0 {
+			return 3
+		}
+		return 0
+	}
+
+	var graph *checker.Graph
+	var diags []driver.Diagnostic
+	if *cacheDir != "" {
+		cache := &driver.Cache{Dir: *cacheDir, Files: configFiles(analyzers)}
+		if cache.Salt, err = cacheSalt(analyzers); err == nil {
+			diags, err = cache.Analyze(analyzers, pkgs)
+		}
+	} else if graph, err = checker.Analyze(analyzers, pkgs, nil); err == nil {
+		diags, err = driver.Diagnostics(graph)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter: %s\n", err)
+		return 1
+	}
+	diags = policy.Apply(diags)
+
+	if *baselineWrite != "" {
+		if err := writeBaseline(*baselineWrite, diags); err != nil {
+			fmt.Fprintf(os.Stderr, "splinter: -baseline-write: %s\n", err)
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "splinter: recorded %d diagnostics in %s\n", len(diags), *baselineWrite)
+		return 0
+	}
+
+	if *applyFixes {
+		fixes := driver.Fixes(diags, nil)
+		if *diff {
+			err = fixes.Diff(os.Stdout)
+		} else {
+			err = fixes.Write()
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "splinter: %s\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	if *collapseCopies {
+		diags = driver.CollapseCopies(diags)
+	}
+
+	if *jsonOut {
+		err = driver.PrintJSON(os.Stdout, diags)
+	} else {
+		err = driver.PrintText(os.Stderr, diags, *contextLines)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter: %s\n", err)
+		return 1
+	}
+	if err := sinks.Send(diags); err != nil {
+		fmt.Fprintf(os.Stderr, "splinter: %s\n", err)
+		return 1
+	}
+	if *stats {
+		printStats(os.Stderr, configuredSelectors(fset), coverage(graph))
+	}
+
+	if policy.Failures(diags) != 0 {
+		return 3
+	}
+	return 0
+}
+
+// writeBaseline records diags in a baseline at path, with their files
+// relative to the working directory.
+func writeBaseline(path string, diags []driver.Diagnostic) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := driver.NewBaseline(diags, wd).Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func printFlagsJSON(fset *flag.FlagSet) int {
+	type jsonFlag struct {
+		Name  string
+		Bool  bool
+		Usage string
+	}
+	var flags []jsonFlag
+	policyNames := policyFlagNames()
+	fset.VisitAll(func(f *flag.Flag) {
+		// -fix has no effect on unitchecker (as invoked by go vet), and
+		// neither does the policy
+		if f.Name == "fix" || policyNames[f.Name] {
+			return
+		}
+		b, ok := f.Value.(interface{ IsBoolFlag() bool })
+		flags = append(flags, jsonFlag{f.Name, ok && b.IsBoolFlag(), f.Usage})
+	})
+	data, err := json.MarshalIndent(flags, "", "\t")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter: %s\n", err)
+		return 1
+	}
+	os.Stdout.Write(data)
+	return 0
+}
+
+// versionFlag minimally complies with the -V protocol required by go vet.
+// The version is printed once the rest of the flags are parsed, since it
+// includes the fingerprint of the configuration they set.
+type versionFlag struct{ full bool }
+
+func (*versionFlag) IsBoolFlag() bool { return true }
+func (*versionFlag) Get() interface{} { return nil }
+func (*versionFlag) String() string   { return "" }
+func (v *versionFlag) Set(s string) error {
+	if s != "full" {
+		return fmt.Errorf("unsupported flag value: -V=%s (use -V=full)", s)
+	}
+	v.full = true
+	return nil
+}
+
+// printVersion prints the version line of the -V protocol.  go vet only
+// takes the build ID from it for a development version, so the fingerprint
+// of the configuration is hashed into the build ID as well as printed.
+func printVersion(analyzers []*analysis.Analyzer) int {
+	progname, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter: %s\n", err)
+		return 1
+	}
+	sum, err := executableHash()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter: %s\n", err)
+		return 1
+	}
+
+	var fingerprints []string
+	for _, a := range analyzers {
+		fingerprints = append(fingerprints, a.Name+":"+pairs.Fingerprint(a))
+	}
+	config := strings.Join(fingerprints, ",")
+	id := sha256.Sum256(append(sum, config...))
+	fmt.Printf("%s version devel comments-go-here config=%s buildID=%02x\n", progname, config, string(id[:]))
+	return 0
+}
+
+// executableHash returns the SHA-256 of the running binary.
+func executableHash() ([]byte, error) {
+	progname, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(progname)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// configFiles returns the configuration files analyzers discover for each
+// package, for the key of its cached diagnostics.
+func configFiles(analyzers []*analysis.Analyzer) func(*packages.Package) ([]string, error) {
+	return func(p *packages.Package) ([]string, error) {
+		if len(p.GoFiles) == 0 {
+			return nil, nil
+		}
+		dir := filepath.Dir(p.GoFiles[0])
+		var files []string
+		for _, a := range analyzers {
+			fs, err := pairs.ConfigFiles(a, dir)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, fs...)
+		}
+		return files, nil
+	}
+}
+
+// cacheSalt identifies the binary and the configuration of analyzers, so
+// that cached diagnostics are discarded when either changes.
+func cacheSalt(analyzers []*analysis.Analyzer) (string, error) {
+	sum, err := executableHash()
+	if err != nil {
+		return "", err
+	}
+	salt := fmt.Sprintf("%x", sum)
+	for _, a := range analyzers {
+		salt += " " + a.Name + ":" + pairs.Fingerprint(a)
+	}
+	return salt, nil
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ZipRecruiter/splinter/events"
+	"github.com/ZipRecruiter/splinter/pairs"
+)
+
+// writeModule writes files, by path relative to a new module named m, to a
+// temporary directory, which it makes the working directory for the rest
+// of the test, and returns.
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	files["go.mod"] = "module m\n\ngo 1.22\n"
+	for name, src := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	return dir
+}
+
+// captureStdout returns what f writes to stdout, along with what it
+// returns.
+func captureStdout(t *testing.T, f func() int) (string, int) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	out := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		out <- string(data)
+	}()
+
+	code := f()
+	os.Stdout = stdout
+	w.Close()
+	return <-out, code
+}
+
+// runSplinter runs splinter with args, with fresh analyzers, and returns
+// what it writes to stdout and its exit code.
+func runSplinter(t *testing.T, args ...string) (string, int) {
+	t.Helper()
+	analyzers := []*analysis.Analyzer{pairs.NewAnalyzer(), events.NewAnalyzer()}
+	return captureStdout(t, func() int { return run(analyzers, args) })
+}
+
+// logModule is a module whose packages a and b each make an odd-arity and
+// an expression-key mistake with m/log.Log.
+var logModule = map[string]string{
+	"log/log.go": "package log\n\nfunc Log(kv ...interface{}) {}\n",
+	"a/a.go":     "package a\n\nimport \"m/log\"\n\nfunc F(n int) {\n\tlog.Log(\"a\")\n\tlog.Log(n, 1)\n}\n",
+	"b/b.go":     "package b\n\nimport \"m/log\"\n\nfunc F(n int) {\n\tlog.Log(\"b\")\n\tlog.Log(n, 1)\n}\n",
+}
+
+// reportCategories returns the categories of the diagnostics in a -json
+// report, by package.
+func reportCategories(t *testing.T, report string) map[string][]string {
+	t.Helper()
+	var tree map[string]map[string][]struct{ Category, Severity string }
+	if err := json.Unmarshal([]byte(report), &tree); err != nil {
+		t.Fatalf("%s\n%s", err, report)
+	}
+	got := map[string][]string{}
+	for pkg, byAnalyzer := range tree {
+		for _, diags := range byAnalyzer {
+			for _, d := range diags {
+				got[pkg] = append(got[pkg], d.Category+" "+d.Severity)
+			}
+		}
+	}
+	return got
+}
+
+func TestJSONPolicy(t *testing.T) {
+	files := map[string]string{}
+	for name, src := range logModule {
+		files[name] = src
+	}
+	writeModule(t, files)
+
+	tests := []struct {
+		args     []string
+		expected string
+		code     int
+	}{
+		{nil, "m/a: odd-arity error, expression-key error; m/b: odd-arity error, expression-key error", 3},
+		{[]string{"-disable", "odd-arity"}, "m/a: expression-key error; m/b: expression-key error", 3},
+		{[]string{"-exclude", "b/**", "-severity", "expression-key=warning"}, "m/a: odd-arity error, expression-key warning", 3},
+		{[]string{"-suppress", "a/a.go:6", "-suppress", "b/b.go:6", "-severity", "expression-key=info"}, "m/a: expression-key info; m/b: expression-key info", 0},
+		{[]string{"-disable", "odd-arity", "-max-severity-exit", "error"}, "m/a: expression-key error; m/b: expression-key error", 0},
+	}
+	for _, test := range tests {
+		t.Run(strings.Join(test.args, " "), func(t *testing.T) {
+			args := append([]string{"-pair-func", "m/log.Log=0", "-json"}, test.args...)
+			out, code := runSplinter(t, append(args, "./a", "./b")...)
+			if got := formatCategories(reportCategories(t, out)); got != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, got)
+			}
+			if code != test.code {
+				t.Errorf("expected exit code %d, got %d", test.code, code)
+			}
+		})
+	}
+}
+
+// formatCategories formats the categories of reportCategories in the order
+// of their packages.
+func formatCategories(byPkg map[string][]string) string {
+	var s []string
+	for _, pkg := range sortedKeys(byPkg) {
+		s = append(s, pkg+": "+strings.Join(byPkg[pkg], ", "))
+	}
+	return strings.Join(s, "; ")
+}
+
+func TestCollapseCopies(t *testing.T) {
+	// the copies, in different packages, report calls to their own Log
+	src := "package app\n\nfunc Log(kv ...interface{}) {}\n\nfunc F() {\n\tLog(\"a\")\n}\n"
+	writeModule(t, map[string]string{
+		"app/app.go":          src,
+		"gen/app/app.go":      src,
+		"vendored/app/app.go": src,
+	})
+
+	out, _ := runSplinter(t, "-pair-func", "m/app.Log=0", "-pair-func", "m/gen/app.Log=0", "-pair-func", "m/vendored/app.Log=0", "-collapse-copies", "-json", "./...")
+	var tree map[string]map[string][]struct{ Message string }
+	if err := json.Unmarshal([]byte(out), &tree); err != nil {
+		t.Fatalf("%s\n%s", err, out)
+	}
+	expected := `{"m/app":{"pairs":[{"Message":"1 args passed to m/app.Log; must be even: arg 0, \"a\", is unpaired (also in 2 identical copies: gen/app/app.go, vendored/app/app.go)"}]}}`
+	if got, _ := json.Marshal(tree); string(got) != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestVetPolicyFlags(t *testing.T) {
+	out, code := runSplinter(t, "-flags")
+	if code != 0 {
+		t.Fatalf("-flags: exit code %d", code)
+	}
+	var flags []struct{ Name string }
+	if err := json.Unmarshal([]byte(out), &flags); err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]bool{}
+	for _, f := range flags {
+		names[f.Name] = true
+	}
+	for name := range policyFlagNames() {
+		if names[name] {
+			t.Errorf("-flags advertises -%s to go vet, which can't apply it", name)
+		}
+	}
+	if !names["pair-func"] || !names["config"] {
+		t.Errorf("-flags doesn't advertise the analyzer flags: %v", names)
+	}
+
+	// set by a configuration file instead
+	config := filepath.Join(t.TempDir(), "splinter.yaml")
+	if err := os.WriteFile(config, []byte("disable: odd-arity\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, code := runSplinter(t, "-config", config, "vet.cfg"); code != 2 {
+		t.Errorf("expected exit code 2 with a policy flag under go vet, got %d", code)
+	}
+}
@@ -0,0 +1,41 @@
+/*
+Package runtimecheck checks key/value pairs at run time, by the same rules
+the pairs analyzer checks them statically: there must be an even number of
+args, and every key must be a string.
+
+The analyzer can only check pairs passed directly to a pair func; pairs
+built up dynamically, like from a config file or across several funcs,
+escape it.  Validate lets a service assert on them at startup, or in tests:
+
+	kv := append(baseFields(), requestFields(r)...)
+	if err := runtimecheck.Validate(kv...); err != nil {
+		t.Fatal(err)
+	}
+*/
+package runtimecheck
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Validate returns an error describing every problem with the pairs kv,
+// or nil if there are none.  Keys may be of any type whose underlying type
+// is string.
+func Validate(kv ...interface{}) error {
+	var errs []error
+	if len(kv)%2 != 0 {
+		errs = append(errs, fmt.Errorf("%d args; must be even", len(kv)))
+	}
+	for i := 0; i < len(kv); i += 2 {
+		if kv[i] == nil {
+			errs = append(errs, fmt.Errorf("arg %d is nil but should be a string", i))
+			continue
+		}
+		if t := reflect.TypeOf(kv[i]); t.Kind() != reflect.String {
+			errs = append(errs, fmt.Errorf("arg %d is %s but should be a string", i, t))
+		}
+	}
+	return errors.Join(errs...)
+}
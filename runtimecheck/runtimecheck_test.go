@@ -0,0 +1,28 @@
+package runtimecheck
+
+import "testing"
+
+type key string
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		kv       []interface{}
+		expected string
+	}{
+		{nil, ""},
+		{[]interface{}{"user_id", 1, key("request_id"), nil}, ""},
+		{[]interface{}{"user_id"}, "1 args; must be even"},
+		{[]interface{}{"user_id", 1, 2, "request_id"}, "arg 2 is int but should be a string"},
+		{[]interface{}{nil, 1, "user_id", 1, 3.5}, "5 args; must be even\narg 0 is nil but should be a string\narg 4 is float64 but should be a string"},
+	}
+	for _, test := range tests {
+		err := Validate(test.kv...)
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != test.expected {
+			t.Errorf("Validate(%v): expected %q, got %q", test.kv, test.expected, got)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+
+	"github.com/ZipRecruiter/splinter/internal/config"
+	"github.com/ZipRecruiter/splinter/internal/driver"
+	"github.com/ZipRecruiter/splinter/pairs"
+)
+
+// sites implements `splinter sites`, which lists every call checked in the
+// analyzed packages, from the pairs CallSites facts.
+func sites(analyzers []*analysis.Analyzer, args []string) int {
+	fset := flag.NewFlagSet("sites", flag.ExitOnError)
+	fset.Usage = func() {
+		fmt.Fprintf(fset.Output(), "usage: splinter sites [analyzer flags] packages...\n\n")
+		fset.PrintDefaults()
+	}
+	workspace := fset.Bool("workspace", false, "list the calls in every module of the enclosing go.work workspace; relative patterns (default ./...) apply within each module")
+	analyzerFlags(fset, analyzers)
+	config.Parse(fset, args)
+	fset.Set("sites", "true")
+
+	pkgs, err := driver.Load(driver.LoadConfig{Tests: true, Workspace: *workspace}, fset.Args()...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter sites: %s\n", err)
+		return 1
+	}
+	graph, err := checker.Analyze(analyzers, pkgs, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter sites: %s\n", err)
+		return 1
+	}
+
+	var all []pairs.CallSite
+	for _, fact := range pairsFacts[pairs.CallSites](roots(graph)) {
+		all = append(all, fact.Sites...)
+	}
+	wd, _ := os.Getwd()
+	printSites(os.Stdout, wd, all)
+	return 0
+}
+
+// printSites writes sites to w, sorted by position, one per line with tab
+// separated columns: the position, relative to dir if it's within it, the
+// callee, the number of pairs and the space separated keys.
+func printSites(w io.Writer, dir string, sites []pairs.CallSite) {
+	sort.SliceStable(sites, func(i, j int) bool {
+		if sites[i].File != sites[j].File {
+			return sites[i].File < sites[j].File
+		}
+		if sites[i].Line != sites[j].Line {
+			return sites[i].Line < sites[j].Line
+		}
+		return sites[i].Callee < sites[j].Callee
+	})
+
+	for _, s := range sites {
+		file := s.File
+		if rel, err := filepath.Rel(dir, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+		fmt.Fprintf(w, "%s:%d\t%s\t%d\t%s\n", file, s.Line, s.Callee, s.Pairs, strings.Join(s.Keys, " "))
+	}
+}
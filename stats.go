@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/tools/go/analysis/checker"
+
+	"github.com/ZipRecruiter/splinter/pairs"
+)
+
+// configuredSelectors returns the selectors passed to -pair-func and
+// -builder-func, in the form SelectorStats uses.
+func configuredSelectors(fset *flag.FlagSet) []string {
+	var sels []string
+	for _, name := range []string{"pair-func", "builder-func"} {
+		f := fset.Lookup(name)
+		if f == nil || f.Value.String() == "" {
+			continue
+		}
+		for _, v := range strings.Split(f.Value.String(), ",") {
+			if i := strings.LastIndexByte(v, '='); i >= 0 {
+				v = v[:i]
+			}
+			sels = append(sels, v)
+		}
+	}
+	return sels
+}
+
+// coverage returns the pairs SelectorCoverage facts of the root packages of
+// graph.
+func coverage(graph *checker.Graph) []*pairs.SelectorCoverage {
+	var facts []*pairs.SelectorCoverage
+	for _, fact := range pairsFacts[pairs.SelectorCoverage](roots(graph)) {
+		facts = append(facts, fact)
+	}
+	return facts
+}
+
+// printStats merges the stats in facts by selector and writes them to w,
+// including the selectors that matched no calls at all.
+func printStats(w io.Writer, selectors []string, facts []*pairs.SelectorCoverage) {
+	merged := map[string]*pairs.SelectorStats{}
+	for _, sel := range selectors {
+		merged[sel] = &pairs.SelectorStats{Selector: sel}
+	}
+	for _, fact := range facts {
+		for _, st := range fact.Selectors {
+			m := merged[st.Selector]
+			if m == nil {
+				m = &pairs.SelectorStats{Selector: st.Selector}
+				merged[st.Selector] = m
+			}
+			m.Checked += st.Checked
+			m.Skipped += st.Skipped
+			m.Diagnostics += st.Diagnostics
+		}
+	}
+
+	fmt.Fprintln(w, "selector coverage:")
+	for _, sel := range sortedKeys(merged) {
+		st := merged[sel]
+		fmt.Fprintf(w, "\t%s: %d calls checked, %d skipped, %d diagnostics\n", sel, st.Checked, st.Skipped, st.Diagnostics)
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/ZipRecruiter/splinter/internal/driver"
+)
+
+// settle is how long watch waits after a change for more changes, so that
+// saving several files (or an editor's write-then-rename) is analyzed once.
+const settle = 200 * time.Millisecond
+
+// watcher re-analyzes the packages in directories whose Go files change.
+type watcher struct {
+	analyzers    []*analysis.Analyzer
+	cfg          driver.LoadConfig
+	policy       *driver.Policy
+	contextLines int
+
+	fs   *fsnotify.Watcher
+	dirs map[string]bool
+}
+
+// watch implements -watch: after analyzing the packages matched by
+// patterns, it re-analyzes the packages of any directory whose Go files
+// change, printing their diagnostics, until interrupted.  Only the changed
+// packages are re-analyzed, not the packages importing them.
+func watch(analyzers []*analysis.Analyzer, cfg driver.LoadConfig, policy *driver.Policy, contextLines int, patterns []string) int {
+	fs, err := fsnotify.NewWatcher()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "splinter: %s\n", err)
+		return 1
+	}
+	defer fs.Close()
+
+	w := &watcher{
+		analyzers:    analyzers,
+		cfg:          cfg,
+		policy:       policy,
+		contextLines: contextLines,
+		fs:           fs,
+		dirs:         map[string]bool{},
+	}
+	if err := w.analyze(patterns); err != nil {
+		fmt.Fprintf(os.Stderr, "splinter: %s\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "splinter: watching %d directories\n", len(w.dirs))
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+
+	changed := map[string]bool{}
+	var timer <-chan time.Time
+	for {
+		select {
+		case ev := <-fs.Events:
+			if !strings.HasSuffix(ev.Name, ".go") || ev.Op == fsnotify.Chmod {
+				continue
+			}
+			changed[filepath.Dir(ev.Name)] = true
+			timer = time.After(settle)
+		case err := <-fs.Errors:
+			fmt.Fprintf(os.Stderr, "splinter: %s\n", err)
+		case <-timer:
+			timer = nil
+
+			var dirs []string
+			for dir := range changed {
+				dirs = append(dirs, dir)
+			}
+			sort.Strings(dirs)
+			changed = map[string]bool{}
+
+			fmt.Fprintf(os.Stderr, "splinter: %s changed\n", strings.Join(dirs, ", "))
+			// errors, like a file that doesn't parse mid-edit, are
+			// reported but don't stop watching
+			if err := w.analyze(dirs); err != nil {
+				fmt.Fprintf(os.Stderr, "splinter: %s\n", err)
+			}
+		case <-interrupt:
+			return 0
+		}
+	}
+}
+
+// analyze analyzes the packages matched by patterns, prints their
+// diagnostics and watches their directories.
+func (w *watcher) analyze(patterns []string) error {
+	pkgs, err := driver.Load(w.cfg, patterns...)
+	if err != nil {
+		return err
+	}
+	graph, err := checker.Analyze(w.analyzers, pkgs, nil)
+	if err != nil {
+		return err
+	}
+	diags, err := driver.Diagnostics(graph)
+	if err != nil {
+		return err
+	}
+	diags = w.policy.Apply(diags)
+	if err := driver.PrintText(os.Stderr, diags, w.contextLines); err != nil {
+		return err
+	}
+
+	for _, p := range pkgs {
+		if err := w.watchPackage(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// watchPackage watches the directories of the Go files of p.
+func (w *watcher) watchPackage(p *packages.Package) error {
+	if strings.HasSuffix(p.PkgPath, ".test") {
+		return nil // generated test main, in the build cache
+	}
+	for _, f := range p.GoFiles {
+		dir := filepath.Dir(f)
+		if w.dirs[dir] {
+			continue
+		}
+		if err := w.fs.Add(dir); err != nil {
+			return err
+		}
+		w.dirs[dir] = true
+	}
+	return nil
+}